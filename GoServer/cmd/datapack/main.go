@@ -0,0 +1,90 @@
+// datapack 卡牌、羁绊和响应码数据包工具
+//
+//	datapack export -o data/cards.yaml -version 2026.10  将MySQL中的数据导出为数据包
+//	datapack validate data/cards.yaml                    检查数据包的完整性
+package main
+
+import (
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/service"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "export":
+		runExport(os.Args[2:])
+	case "validate":
+		runValidate(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  datapack export -o <file.json|file.yaml> [-version <version>]")
+	fmt.Fprintln(os.Stderr, "  datapack validate <file.json|file.yaml>")
+	os.Exit(2)
+}
+
+// runExport 将MySQL中的卡牌、羁绊和响应码导出为数据包，数据不完整时不写入文件
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "", "output file (.json, .yaml or .yml)")
+	version := fs.String("version", "", "data pack version label")
+	envPath := fs.String("env", ".env", "env file with DB_* settings")
+	fs.Parse(args)
+	if *output == "" {
+		usage()
+	}
+
+	if err := godotenv.Load(*envPath); err != nil {
+		log.Printf("No env file %s found, using system environment variables", *envPath)
+	}
+
+	pack, err := service.ExportGameData()
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+	pack.Version = *version
+	if err := pack.Validate(); err != nil {
+		log.Fatalf("Exported data has integrity problems, no data pack written:\n%v", err)
+	}
+
+	if err := service.WriteDataPack(pack, *output); err != nil {
+		log.Fatalf("Failed to write data pack: %v", err)
+	}
+	printSummary(*output, pack)
+}
+
+// runValidate 检查数据包的完整性，存在问题时以非零状态退出
+func runValidate(args []string) {
+	if len(args) != 1 {
+		usage()
+	}
+
+	pack, err := service.ReadDataPack(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := pack.Validate(); err != nil {
+		log.Fatalf("Data pack %s is invalid:\n%v", args[0], err)
+	}
+	printSummary(args[0], pack)
+}
+
+func printSummary(path string, pack *models.DataPack) {
+	fmt.Printf("%s: version %q, %d cards, %d bonds, %d recipes, %d response codes\n",
+		path, pack.Version, len(pack.Cards), len(pack.Bonds), len(pack.Recipes), len(pack.ResponseCodes))
+}
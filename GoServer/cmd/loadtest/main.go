@@ -0,0 +1,569 @@
+// loadtest 游戏服务器压力测试：模拟多名玩家连接运行中的服务器并完成完整对局，
+// 报告请求延迟分位数、错误响应码，以及对局结束后泄漏的房间、连接和协程
+//
+//	loadtest -addr localhost:9060 -players 20 -games 3
+//	loadtest -players 40 -room-size 4 -admin admin -admin-password secret
+//
+// 服务器端的泄漏检查需要GAME_ADMINS中的管理员账户，未提供时只检查本地协程
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"GoServer/tcpgameserver/client"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+)
+
+// options 压力测试参数
+type options struct {
+	addr          string
+	players       int
+	games         int
+	roomSize      int
+	prefix        string
+	password      string
+	frameMode     protocol.FrameMode
+	timeout       time.Duration
+	think         time.Duration
+	ramp          time.Duration
+	compose       bool
+	admin         string
+	adminPassword string
+	settle        time.Duration
+}
+
+// pushCodes 服务器主动推送的消息码，不作为请求的响应
+var pushCodes = map[string]bool{
+	client.CodeWelcome:       true,
+	client.CodeGameEnd:       true,
+	client.CodeQueueStatus:   true,
+	client.CodeGameStart:     true,
+	client.CodeBonds:         true,
+	client.CodeTimeoutUpdate: true,
+	client.CodePlayUpdate:    true,
+	client.CodeComposeUpdate: true,
+	"2201":                   true, // 卡牌池已抽空
+	"2202":                   true, // 弃牌堆已洗回卡牌池
+}
+
+func main() {
+	log.SetFlags(0)
+	opts := parseOptions()
+
+	metrics := newMetrics()
+	localBefore := runtime.NumGoroutine()
+
+	var admin *client.Client
+	var statsBefore *models.ServerStats
+	if opts.admin != "" {
+		var err error
+		admin, statsBefore, err = connectAdmin(opts, metrics)
+		if err != nil {
+			log.Fatalf("Admin connection failed: %v", err)
+		}
+		defer admin.Close()
+	}
+
+	started := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < opts.players; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			username := fmt.Sprintf("%s_%d", opts.prefix, index)
+			if err := runPlayer(opts, username, metrics); err != nil {
+				metrics.fail(username, err)
+			}
+		}(i)
+		time.Sleep(opts.ramp)
+	}
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	// 等待服务器处理断开连接和房间清理
+	time.Sleep(opts.settle)
+
+	var statsAfter *models.ServerStats
+	if admin != nil {
+		stats, err := fetchStats(admin, opts.timeout, metrics)
+		if err != nil {
+			log.Printf("Failed to fetch server stats after the run: %v", err)
+		} else {
+			statsAfter = stats
+		}
+	}
+
+	leaked := metrics.report(os.Stdout, opts, elapsed)
+	leaked = reportLeaks(os.Stdout, statsBefore, statsAfter, localBefore, runtime.NumGoroutine()) || leaked
+	if leaked {
+		os.Exit(1)
+	}
+}
+
+// parseOptions 解析命令行参数
+func parseOptions() *options {
+	opts := &options{}
+	frameMode := flag.String("frame", string(protocol.FrameModeLine), "frame mode: line or length")
+	flag.StringVar(&opts.addr, "addr", "localhost:9060", "game server TCP address")
+	flag.IntVar(&opts.players, "players", 10, "number of simulated players")
+	flag.IntVar(&opts.games, "games", 1, "games each player plays before disconnecting")
+	flag.IntVar(&opts.roomSize, "room-size", models.MinRoomSize, "players per room (2-4)")
+	flag.StringVar(&opts.prefix, "prefix", "loadtest", "username prefix of simulated players")
+	flag.StringVar(&opts.password, "password", "loadtest", "password of simulated players")
+	flag.DurationVar(&opts.timeout, "timeout", 60*time.Second, "max wait for a single server message")
+	flag.DurationVar(&opts.think, "think", 0, "delay before each play")
+	flag.DurationVar(&opts.ramp, "ramp", 10*time.Millisecond, "delay between player connections")
+	flag.BoolVar(&opts.compose, "compose", true, "compose three cards of the same name before playing")
+	flag.StringVar(&opts.admin, "admin", "", "admin account for server leak checks (must be in GAME_ADMINS)")
+	flag.StringVar(&opts.adminPassword, "admin-password", "", "admin account password")
+	flag.DurationVar(&opts.settle, "settle", 5*time.Second, "wait after the run before checking for leaks")
+	flag.Parse()
+
+	mode, err := protocol.ParseFrameMode(*frameMode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.frameMode = mode
+	if opts.players <= 0 || opts.games <= 0 {
+		log.Fatal("players and games must be positive")
+	}
+	if opts.roomSize < models.MinRoomSize || opts.roomSize > models.MaxRoomSize {
+		log.Fatalf("room size must be between %d and %d", models.MinRoomSize, models.MaxRoomSize)
+	}
+	if opts.players%opts.roomSize != 0 {
+		log.Printf("Warning: %d players cannot be split evenly into rooms of %d, some players may wait until timeout",
+			opts.players, opts.roomSize)
+	}
+	return opts
+}
+
+// runPlayer 模拟一名玩家：注册、登录并完成指定局数的对局后断开
+func runPlayer(opts *options, username string, metrics *metrics) error {
+	start := time.Now()
+	c, err := client.Dial(opts.addr, opts.frameMode, opts.timeout)
+	if err != nil {
+		return fmt.Errorf("connect: %v", err)
+	}
+	defer c.Close()
+	metrics.observe("connect", time.Since(start))
+
+	p := &player{client: c, username: username, opts: opts, metrics: metrics}
+	if err := p.login(); err != nil {
+		return err
+	}
+
+	for game := 0; game < opts.games; game++ {
+		if err := p.playGame(game == 0); err != nil {
+			return fmt.Errorf("game %d: %v", game+1, err)
+		}
+		metrics.gameCompleted()
+	}
+	return nil
+}
+
+// connectAdmin 登录管理员账户并获取压力测试前的服务器运行状态
+func connectAdmin(opts *options, metrics *metrics) (*client.Client, *models.ServerStats, error) {
+	c, err := client.Dial(opts.addr, opts.frameMode, opts.timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.Login(opts.admin, opts.adminPassword); err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	response, err := awaitResponse(c, opts.timeout)
+	if err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	if response.Code != client.CodeLoginSuccess {
+		c.Close()
+		return nil, nil, fmt.Errorf("login rejected with code %s (%s)", response.Code, response.Message)
+	}
+
+	stats, err := fetchStats(c, opts.timeout, metrics)
+	if err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	return c, stats, nil
+}
+
+// fetchStats 查询服务器运行状态
+func fetchStats(c *client.Client, timeout time.Duration, metrics *metrics) (*models.ServerStats, error) {
+	if err := c.GetServerStats(); err != nil {
+		return nil, err
+	}
+	response, err := awaitResponse(c, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if response.Code != client.CodeServerStats {
+		return nil, fmt.Errorf("server stats rejected with code %s (%s), is the account listed in GAME_ADMINS?", response.Code, response.Message)
+	}
+
+	var stats models.ServerStats
+	if err := response.Decode(&stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// awaitResponse 等待请求的响应：跳过服务器主动推送的消息，返回第一条其他消息
+func awaitResponse(c *client.Client, timeout time.Duration) (*client.Response, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := c.Next(time.Until(deadline))
+		if err != nil {
+			return nil, err
+		}
+		if !pushCodes[response.Code] {
+			return response, nil
+		}
+	}
+}
+
+// player 模拟玩家
+type player struct {
+	client   *client.Client
+	username string
+	opts     *options
+	metrics  *metrics
+}
+
+// login 注册（账户已存在时跳过）并登录
+func (p *player) login() error {
+	if _, err := p.request("register", func() error {
+		return p.client.Register(p.username, p.opts.password)
+	}, client.CodeRegisterOK, client.CodeUserExists); err != nil {
+		return err
+	}
+
+	_, err := p.request("login", func() error {
+		return p.client.Login(p.username, p.opts.password)
+	}, client.CodeLoginSuccess)
+	return err
+}
+
+// request 发送请求并等待响应，记录延迟；响应码不是期望值时记录错误码并返回错误
+func (p *player) request(op string, send func() error, expected ...string) (*client.Response, error) {
+	start := time.Now()
+	if err := send(); err != nil {
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	response, err := awaitResponse(p.client, p.opts.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+	p.metrics.observe(op, response.ReceivedAt.Sub(start))
+
+	for _, code := range expected {
+		if response.Code == code {
+			return response, nil
+		}
+	}
+	p.metrics.errorCode(op, response.Code)
+	return nil, fmt.Errorf("%s: unexpected response %s (%s)", op, response.Code, response.Message)
+}
+
+// playGame 加入匹配并完成一局游戏：轮到自己时合成并出牌，直到收到游戏结束消息
+func (p *player) playGame(first bool) error {
+	start := time.Now()
+	var err error
+	if first {
+		err = p.client.Ready(p.opts.roomSize)
+	} else {
+		err = p.client.Restart(p.opts.roomSize)
+	}
+	if err != nil {
+		return err
+	}
+
+	response, err := p.waitForState("match", client.CodeGameStart)
+	if err != nil {
+		return err
+	}
+	p.metrics.observe("match", response.ReceivedAt.Sub(start))
+
+	var state models.PlayerGameInfo
+	if err := response.Decode(&state); err != nil {
+		return err
+	}
+
+	for {
+		if state.Round == "current" && state.Health > 0 && len(state.SelfCards) > 0 {
+			next, ended, err := p.takeTurn(&state)
+			if err != nil {
+				return err
+			}
+			if ended {
+				return nil
+			}
+			state = *next
+			continue
+		}
+
+		response, err := p.waitForState("turn", client.CodePlayUpdate, client.CodeComposeUpdate, client.CodeTimeoutUpdate, client.CodeGameEnd)
+		if err != nil {
+			return err
+		}
+		if response.Code == client.CodeGameEnd {
+			return nil
+		}
+		if err := response.Decode(&state); err != nil {
+			return err
+		}
+	}
+}
+
+// takeTurn 执行一个回合：先合成三张同名卡牌，再打出伤害最高的最多三张卡牌
+// 返回出牌后的状态，游戏结束时ended为true
+func (p *player) takeTurn(state *models.PlayerGameInfo) (*models.PlayerGameInfo, bool, error) {
+	if p.opts.think > 0 {
+		time.Sleep(p.opts.think)
+	}
+
+	if p.opts.compose {
+		if cards := sameNameCards(state.SelfCards, 3); cards != nil {
+			response, err := p.request("compose", func() error {
+				return p.client.ComposeCard(state.RoomId, cards)
+			}, client.CodeComposeSuccess, client.CodeComposeFailed)
+			if err != nil {
+				return nil, false, err
+			}
+			if response.Code == client.CodeComposeFailed {
+				p.metrics.errorCode("compose", response.Code)
+			} else {
+				// 合成成功后等待合成的状态更新，使用更新后的手牌出牌
+				update, err := p.waitForState("compose", client.CodeComposeUpdate, client.CodeGameEnd)
+				if err != nil {
+					return nil, false, err
+				}
+				if update.Code == client.CodeGameEnd {
+					return nil, true, nil
+				}
+				if err := update.Decode(state); err != nil {
+					return nil, false, err
+				}
+				if state.Round != "current" || len(state.SelfCards) == 0 {
+					return state, false, nil
+				}
+			}
+		}
+	}
+
+	start := time.Now()
+	if err := p.client.PlayCard(state.RoomId, strongestCards(state.SelfCards, 3), ""); err != nil {
+		return nil, false, err
+	}
+	response, err := p.waitForState("play", client.CodePlayUpdate, client.CodeTimeoutUpdate, client.CodeGameEnd)
+	if err != nil {
+		return nil, false, err
+	}
+	p.metrics.observe("play", response.ReceivedAt.Sub(start))
+	if response.Code == client.CodeGameEnd {
+		return nil, true, nil
+	}
+
+	var next models.PlayerGameInfo
+	if err := response.Decode(&next); err != nil {
+		return nil, false, err
+	}
+	return &next, false, nil
+}
+
+// waitForState 等待指定消息码的消息，期间收到的非推送消息记为错误响应码
+func (p *player) waitForState(op string, codes ...string) (*client.Response, error) {
+	deadline := time.Now().Add(p.opts.timeout)
+	for {
+		response, err := p.client.Next(time.Until(deadline))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", op, err)
+		}
+		for _, code := range codes {
+			if response.Code == code {
+				return response, nil
+			}
+		}
+		if !pushCodes[response.Code] {
+			p.metrics.errorCode(op, response.Code)
+		}
+	}
+}
+
+// sameNameCards 找出手牌中数量达到count张的同名卡牌，没有时返回nil
+func sameNameCards(cards []models.Card, count int) []models.Card {
+	byName := make(map[string][]models.Card)
+	for _, card := range cards {
+		byName[card.Name] = append(byName[card.Name], card)
+		if len(byName[card.Name]) == count {
+			return byName[card.Name]
+		}
+	}
+	return nil
+}
+
+// strongestCards 选出伤害最高的最多count张卡牌
+func strongestCards(cards []models.Card, count int) []models.Card {
+	sorted := append([]models.Card(nil), cards...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Damage > sorted[j].Damage
+	})
+	if len(sorted) > count {
+		sorted = sorted[:count]
+	}
+	return sorted
+}
+
+// metrics 压力测试统计
+type metrics struct {
+	mutex      sync.Mutex
+	latencies  map[string][]time.Duration // 操作 -> 延迟
+	errorCodes map[string]int             // 操作 响应码 -> 次数
+	failures   map[string]string          // 用户名 -> 中止原因
+	games      int                        // 完成的对局数（每名玩家分别计数）
+}
+
+// newMetrics 创建压力测试统计
+func newMetrics() *metrics {
+	return &metrics{
+		latencies:  make(map[string][]time.Duration),
+		errorCodes: make(map[string]int),
+		failures:   make(map[string]string),
+	}
+}
+
+func (m *metrics) observe(op string, latency time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.latencies[op] = append(m.latencies[op], latency)
+}
+
+func (m *metrics) errorCode(op, code string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.errorCodes[op+" "+code]++
+}
+
+func (m *metrics) fail(username string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.failures[username] = err.Error()
+}
+
+func (m *metrics) gameCompleted() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.games++
+}
+
+// report 输出延迟分位数、错误响应码和中止的玩家，有玩家中止时返回true
+func (m *metrics) report(out *os.File, opts *options, elapsed time.Duration) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	fmt.Fprintf(out, "Players: %d  Room size: %d  Games per player: %d  Elapsed: %s\n",
+		opts.players, opts.roomSize, opts.games, elapsed.Round(time.Millisecond))
+	fmt.Fprintf(out, "Player games completed: %d/%d  Failed players: %d\n\n",
+		m.games, opts.players*opts.games, len(m.failures))
+
+	fmt.Fprintf(out, "%-10s %8s %10s %10s %10s %10s\n", "op", "count", "p50", "p90", "p99", "max")
+	for _, op := range sortedKeys(m.latencies) {
+		samples := m.latencies[op]
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		fmt.Fprintf(out, "%-10s %8d %10s %10s %10s %10s\n", op, len(samples),
+			formatLatency(percentile(samples, 50)), formatLatency(percentile(samples, 90)),
+			formatLatency(percentile(samples, 99)), formatLatency(samples[len(samples)-1]))
+	}
+
+	if len(m.errorCodes) > 0 {
+		fmt.Fprintln(out, "\nError response codes:")
+		for _, key := range sortedKeys(m.errorCodes) {
+			fmt.Fprintf(out, "  %-20s %d\n", key, m.errorCodes[key])
+		}
+	}
+
+	if len(m.failures) > 0 {
+		fmt.Fprintln(out, "\nFailed players:")
+		for _, username := range sortedKeys(m.failures) {
+			fmt.Fprintf(out, "  %s: %s\n", username, m.failures[username])
+		}
+	}
+	return len(m.failures) > 0
+}
+
+// reportLeaks 比较压力测试前后的服务器状态和本地协程数量，存在泄漏时返回true
+func reportLeaks(out *os.File, before, after *models.ServerStats, localBefore, localAfter int) bool {
+	leaked := false
+	fmt.Fprintln(out, "\nLeak check:")
+
+	if before == nil || after == nil {
+		fmt.Fprintln(out, "  server: skipped (run with -admin to compare server stats)")
+	} else {
+		checks := []struct {
+			name          string
+			before, after int
+		}{
+			{"rooms", before.Rooms, after.Rooms},
+			{"connections", before.Connections["total"], after.Connections["total"]},
+			{"queued players", before.QueueSize, after.QueueSize},
+		}
+		for _, check := range checks {
+			status := "ok"
+			if check.after > check.before {
+				status = "LEAKED"
+				leaked = true
+			}
+			fmt.Fprintf(out, "  %-16s before %-6d after %-6d %s\n", check.name, check.before, check.after, status)
+		}
+		// 协程数量受计时器和后台任务影响，只作参考
+		fmt.Fprintf(out, "  %-16s before %-6d after %-6d (%+d)\n", "server goroutines",
+			before.Goroutines, after.Goroutines, after.Goroutines-before.Goroutines)
+	}
+
+	status := "ok"
+	if localAfter > localBefore {
+		status = "LEAKED"
+		leaked = true
+	}
+	fmt.Fprintf(out, "  %-16s before %-6d after %-6d %s\n", "client goroutines", localBefore, localAfter, status)
+	return leaked
+}
+
+// percentile 获取已排序样本的分位数
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	index := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+// formatLatency 格式化延迟
+func formatLatency(d time.Duration) string {
+	if d >= time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
+
+// sortedKeys 获取排序后的map键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -155,5 +155,8 @@ func main() {
 		s.BindHandler("POST:/voyara/payment/paypal-webhook", pay.PayPalWebhook)
 	}
 
+	// ── Card Game WebSocket Gateway (same JSON protocol as TCP :9060) ──
+	s.BindHandler(tcpserver.WebSocketPath, tcpserver.HandleWebSocket)
+
 	s.Run()
 }
@@ -0,0 +1,48 @@
+package tcpserver
+
+import (
+	"runtime"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+)
+
+// HandleReloadGameData 处理管理员的热加载请求，重新读取卡牌、羁绊和响应码定义，进行中的对局不受影响
+func HandleReloadGameData(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn || !config.IsGameAdmin(clientInfo.Username) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1902))
+		return
+	}
+
+	eventData := events.NewEventData(events.EventDataLoad, "admin_handler", map[string]interface{}{
+		"client_id": clientID,
+		"username":  clientInfo.Username,
+	})
+	events.Publish(events.EventDataLoad, eventData)
+}
+
+// HandleGetServerStats 处理管理员查询服务器运行状态的请求，用于压力测试检查连接、房间和协程是否泄漏
+func HandleGetServerStats(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn || !config.IsGameAdmin(clientInfo.Username) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1905))
+		return
+	}
+
+	roomStats := service.GetRoomManager().GetRoomStats()
+	rooms, _ := roomStats["total_rooms"].(int)
+	playingRooms, _ := roomStats["playing_rooms"].(int)
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1904, models.ServerStats{
+		Connections:  connManager.GetConnectionStats(),
+		Rooms:        rooms,
+		PlayingRooms: playingRooms,
+		QueueSize:    service.GetMatchmakingQueue().Len(),
+		Goroutines:   runtime.NumGoroutine(),
+	}))
+}
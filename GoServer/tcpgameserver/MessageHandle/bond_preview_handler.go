@@ -0,0 +1,64 @@
+package tcpserver
+
+import (
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// HandlePreviewPlay 处理出牌预览请求，返回所选手牌出牌时的羁绊计算结果，不改变游戏状态
+func HandlePreviewPlay(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getInGameClient(conn, clientID, connManager, 1703)
+	if !ok {
+		return
+	}
+
+	var request models.PreviewPlayRequest
+	if !decodeRoomRequest(req, &request) || len(request.Cards) == 0 {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1704))
+		return
+	}
+
+	previewData := events.NewEventData(events.EventBondPreview, "bond_preview_handler", map[string]interface{}{
+		"client_id": clientID,
+		"player":    clientInfo.Username,
+		"cards":     request.Cards,
+	})
+	previewData.SetRoom(clientInfo.GetGameRoom())
+	events.Publish(events.EventBondPreview, previewData)
+}
+
+// HandleBondHints 处理羁绊提示请求，列出手牌最接近完成的羁绊及缺少的卡牌
+func HandleBondHints(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getInGameClient(conn, clientID, connManager, 1703)
+	if !ok {
+		return
+	}
+
+	var request models.BondHintsRequest
+	if !decodeRoomRequest(req, &request) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1704))
+		return
+	}
+
+	hintsData := events.NewEventData(events.EventBondHints, "bond_preview_handler", map[string]interface{}{
+		"client_id": clientID,
+		"player":    clientInfo.Username,
+		"limit":     request.Limit,
+	})
+	hintsData.SetRoom(clientInfo.GetGameRoom())
+	events.Publish(events.EventBondHints, hintsData)
+}
+
+// getInGameClient 获取已登录且正在游戏中的客户端，否则返回指定的错误码
+func getInGameClient(conn protocol.Conn, clientID string, connManager *service.ConnectionManager, errorCode int) (*types.ClientInfo, bool) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn || clientInfo.GetStatus() != types.StatusInGame || clientInfo.GetGameRoom() == "" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(errorCode))
+		return nil, false
+	}
+	return clientInfo, true
+}
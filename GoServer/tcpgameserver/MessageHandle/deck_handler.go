@@ -0,0 +1,223 @@
+package tcpserver
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"GoServer/tcpgameserver/cards"
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// maxDeckNameLength 卡组名称最大长度
+const maxDeckNameLength = 50
+
+// HandleGetCollection 处理获取卡牌收藏请求，返回拥有的卡牌和组卡规则，首次查询时发放初始收藏
+func HandleGetCollection(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getDeckClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	cardSet := cards.CurrentCardSet()
+	rules := config.GetDeckRules()
+	owned, err := service.GetPlayerCollection(clientInfo.Username, models.StarterCollection(cardSet, rules.MaxCopies))
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2114))
+		return
+	}
+
+	collection := make([]models.CollectionCard, 0, len(owned))
+	for _, template := range cardSet.Templates {
+		if quantity := owned[template.ID]; quantity > 0 {
+			collection = append(collection, models.CollectionCard{
+				CardID:   template.ID,
+				Name:     template.Name,
+				Level:    template.Level,
+				Damage:   template.Damage,
+				Quantity: quantity,
+			})
+		}
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2101, map[string]interface{}{
+		"Cards": collection,
+		"Rules": rules,
+	}))
+}
+
+// HandleGetDecks 处理获取卡组列表请求
+func HandleGetDecks(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getDeckClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	decks, err := service.GetPlayerDecks(clientInfo.Username)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2114))
+		return
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2102, map[string]interface{}{
+		"Decks":    decks,
+		"Selected": clientInfo.GetDeck(),
+	}))
+}
+
+// HandleSaveDeck 处理保存卡组请求，卡组需符合组卡规则且不超过拥有的卡牌数量
+func HandleSaveDeck(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getDeckClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	var request models.SaveDeckRequest
+	if !decodeRoomRequest(req, &request) || !validDeckName(request.Name) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2107))
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	deckCards := models.MergeDeckCards(request.Cards)
+
+	cardSet := cards.CurrentCardSet()
+	rules := config.GetDeckRules()
+	owned, err := service.GetPlayerCollection(clientInfo.Username, models.StarterCollection(cardSet, rules.MaxCopies))
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2114))
+		return
+	}
+	if err := rules.Validate(deckCards, owned, cardSet); err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(deckErrorCode(err)))
+		return
+	}
+
+	if err := service.SavePlayerDeck(clientInfo.Username, request.Name, deckCards, rules.MaxDecks); err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(deckErrorCode(err)))
+		return
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2103, models.Deck{
+		Name:  request.Name,
+		Cards: deckCards,
+		Size:  models.DeckSize(deckCards),
+	}))
+}
+
+// HandleDeleteDeck 处理删除卡组请求，删除已选择的卡组时同时取消选择
+func HandleDeleteDeck(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getDeckClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	var request models.DeckNameRequest
+	if !decodeRoomRequest(req, &request) || !validDeckName(request.Name) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2107))
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+
+	if err := service.DeletePlayerDeck(clientInfo.Username, request.Name); err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(deckErrorCode(err)))
+		return
+	}
+	if clientInfo.GetDeck() == request.Name {
+		clientInfo.SetDeck("")
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2104, map[string]interface{}{
+		"Name": request.Name,
+	}))
+}
+
+// HandleSelectDeck 处理选择卡组请求，选择的卡组用于之后开始的对局（匹配或私人房间），名称为空时改用共享卡牌池
+func HandleSelectDeck(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getDeckClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	var request models.DeckNameRequest
+	if !decodeRoomRequest(req, &request) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2107))
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+
+	if code, ok := selectDeck(clientInfo, request.Name); !ok {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(code))
+		return
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2105, map[string]interface{}{
+		"Name": request.Name,
+	}))
+}
+
+// selectDeck 检查卡组仍符合规则后记录为玩家下一局使用的卡组，失败时返回错误响应码
+func selectDeck(clientInfo *types.ClientInfo, deckName string) (int, bool) {
+	if deckName == "" {
+		clientInfo.SetDeck("")
+		return 0, true
+	}
+	if !validDeckName(deckName) {
+		return 2107, false
+	}
+
+	deck, err := service.GetPlayerDeck(clientInfo.Username, deckName)
+	if err != nil {
+		return deckErrorCode(err), false
+	}
+	owned, err := service.GetPlayerCollection(clientInfo.Username, nil)
+	if err != nil {
+		return 2114, false
+	}
+	if err := config.GetDeckRules().Validate(deck.Cards, owned, cards.CurrentCardSet()); err != nil {
+		return deckErrorCode(err), false
+	}
+
+	clientInfo.SetDeck(deckName)
+	return 0, true
+}
+
+// getDeckClient 获取已登录的客户端，未登录时发送错误响应
+func getDeckClient(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) (*types.ClientInfo, bool) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2106))
+		return nil, false
+	}
+	return clientInfo, true
+}
+
+// validDeckName 检查卡组名称非空且不超过长度限制
+func validDeckName(name string) bool {
+	name = strings.TrimSpace(name)
+	return name != "" && utf8.RuneCountInString(name) <= maxDeckNameLength
+}
+
+// deckErrorCode 卡组错误对应的响应码
+func deckErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrDeckNotFound):
+		return 2108
+	case errors.Is(err, models.ErrDeckSize):
+		return 2109
+	case errors.Is(err, models.ErrDeckCopyLimit):
+		return 2110
+	case errors.Is(err, models.ErrDeckCardNotOwned):
+		return 2111
+	case errors.Is(err, models.ErrDeckInvalidCard):
+		return 2112
+	case errors.Is(err, service.ErrDeckLimitReached):
+		return 2113
+	default:
+		return 2114
+	}
+}
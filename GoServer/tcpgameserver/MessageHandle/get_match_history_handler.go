@@ -0,0 +1,68 @@
+package tcpserver
+
+import (
+	"encoding/json"
+
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+)
+
+const (
+	defaultMatchHistoryLimit = 20  // 默认返回的对局数量
+	maxMatchHistoryLimit     = 100 // 单次最多返回的对局数量
+)
+
+// HandleGetMatchHistory 处理获取对局历史及玩家统计请求
+func HandleGetMatchHistory(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	// 检查用户是否已登录
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1203))
+		return
+	}
+
+	// 解析查询参数（可选）
+	var query models.MatchHistoryQuery
+	if req.Data != nil {
+		dataBytes, err := json.Marshal(req.Data)
+		if err != nil {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1202))
+			return
+		}
+		if err := json.Unmarshal(dataBytes, &query); err != nil {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1202))
+			return
+		}
+	}
+
+	// 默认查询自己的对局
+	if query.Username == "" {
+		query.Username = clientInfo.Username
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultMatchHistoryLimit
+	}
+	if query.Limit > maxMatchHistoryLimit {
+		query.Limit = maxMatchHistoryLimit
+	}
+
+	matches, err := service.GetMatchHistory(query.Username, query.Limit)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1204))
+		return
+	}
+
+	stats, err := service.GetPlayerStats(query.Username)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1204))
+		return
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1201, map[string]interface{}{
+		"Username": query.Username,
+		"Stats":    stats,
+		"Matches":  matches,
+	}))
+}
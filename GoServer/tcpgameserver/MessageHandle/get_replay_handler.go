@@ -0,0 +1,114 @@
+package tcpserver
+
+import (
+	"encoding/json"
+	"log"
+
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+)
+
+const (
+	defaultReplayPageLimit = 50  // 默认返回的回放条目数量
+	maxReplayPageLimit     = 200 // 单次最多返回的回放条目数量
+)
+
+// ReplayQuery 回放查询请求，按条目分页获取
+type ReplayQuery struct {
+	RoomID string `json:"room_id"`
+	Offset int    `json:"offset"` // 起始条目序号（从0开始）
+	Limit  int    `json:"limit"`  // 返回的条目数量上限，响应超过单帧大小时实际返回数量更少
+}
+
+// HandleGetReplay 处理获取对局回放日志请求（仅限已结束的对局）
+func HandleGetReplay(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	// 检查用户是否已登录
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1303))
+		return
+	}
+
+	// 解析查询参数
+	var query ReplayQuery
+	dataBytes, err := json.Marshal(req.Data)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1302))
+		return
+	}
+	if err := json.Unmarshal(dataBytes, &query); err != nil || query.RoomID == "" || query.Offset < 0 {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1302))
+		return
+	}
+
+	// 进行中的对局包含双方手牌，不允许查看
+	if _, err := service.GetRoomManager().GetRoom(query.RoomID); err == nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1305))
+		return
+	}
+
+	entries, err := service.GetReplayLogger().ReadReplay(query.RoomID)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1304))
+		return
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultReplayPageLimit
+	}
+	if query.Limit > maxReplayPageLimit {
+		query.Limit = maxReplayPageLimit
+	}
+
+	response, err := replayPageResponse(query, entries)
+	if err != nil {
+		log.Printf("Failed to page replay %s at entry %d: %v", query.RoomID, query.Offset, err)
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1306))
+		return
+	}
+	SendTCPResponse(conn, response)
+}
+
+// replayPageResponse 创建一页回放条目的响应 (消息码1301)
+// 编码后超过单帧大小时减少本页的条目数量，客户端根据 Offset 和 Entries 的数量继续请求
+func replayPageResponse(query ReplayQuery, entries []models.ReplayEntry) (*models.TcpResponse, error) {
+	start := query.Offset
+	if start > len(entries) {
+		start = len(entries)
+	}
+	end := start + query.Limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	for {
+		response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(1301, map[string]interface{}{
+			"Room_Id": query.RoomID,
+			"Offset":  start,
+			"Total":   len(entries),
+			"Entries": entries[start:end],
+		})
+		payload, err := json.Marshal(response)
+		if err != nil {
+			return nil, err
+		}
+		if len(payload) <= protocol.MaxFrameSize {
+			return response, nil
+		}
+		if end-start <= 1 {
+			return nil, protocol.ErrFrameTooLarge
+		}
+
+		// 按超出的比例估算本页可容纳的条目数量，至少减少一条
+		count := (end - start) * protocol.MaxFrameSize / len(payload)
+		if count >= end-start {
+			count = end - start - 1
+		}
+		if count < 1 {
+			count = 1
+		}
+		end = start + count
+	}
+}
@@ -3,30 +3,26 @@ package tcpserver
 import (
 	"encoding/json"
 	"fmt"
-	"net"
+	"log"
 	"strings"
 	"time"
 
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 )
 
 // SendTCPResponse 发送TCP响应消息
-func SendTCPResponse(conn net.Conn, resp *models.TcpResponse) {
-	jsonBytes, err := json.Marshal(resp)
-	if err != nil {
-		return
-	}
-	jsonBytes = append(jsonBytes, '\n')
-	_, err = conn.Write(jsonBytes)
-	if err != nil {
+func SendTCPResponse(conn protocol.Conn, resp *models.TcpResponse) {
+	if err := protocol.WriteJSON(conn, resp); err != nil {
+		log.Printf("Failed to send response %s: %v", resp.Code, err)
 	}
 }
 
 // HandleTCPMessage 处理TCP消息
-func HandleTCPMessage(msg string, conn net.Conn, clientID string) {
+func HandleTCPMessage(msg string, conn protocol.Conn, clientID string) {
 	// 更新客户端活动时间
 	connManager := service.GetConnectionManager()
 	connManager.UpdateActivity(clientID)
@@ -41,23 +37,77 @@ func HandleTCPMessage(msg string, conn net.Conn, clientID string) {
 	switch req.Message {
 	case "UserLogin":
 		HandleUserLogin(req, conn, clientID, connManager)
+	case "ResumeSession":
+		HandleResumeSession(req, conn, clientID, connManager)
+	case "LinkVoyara":
+		HandleLinkVoyara(req, conn, clientID, connManager)
 	case "UserRegister":
 		HandleUserRegister(req, conn, clientID, connManager)
 	case "UserReady":
-		HandleUserReady(conn, clientID, connManager)
+		HandleUserReady(req, conn, clientID, connManager)
 	case "UserPlayCard":
 		HandleUserPlayCard(req, conn, clientID, connManager)
 	case "UserComposeCard":
 		HandleUserComposeCard(req, conn, clientID, connManager)
 	case "UserRestart":
-		HandleUserRestart(conn, clientID, connManager)
+		HandleUserRestart(req, conn, clientID, connManager)
+	case "GetMatchHistory":
+		HandleGetMatchHistory(req, conn, clientID, connManager)
+	case "GetReplay":
+		HandleGetReplay(req, conn, clientID, connManager)
+	case "CancelQueue":
+		HandleCancelQueue(conn, clientID, connManager)
+	case "GetQueueStatus":
+		HandleGetQueueStatus(conn, clientID, connManager)
+	case "CreateRoom":
+		HandleCreateRoom(req, conn, clientID, connManager)
+	case "JoinRoom":
+		HandleJoinRoom(req, conn, clientID, connManager)
+	case "LeaveRoom":
+		HandleLeaveRoom(conn, clientID, connManager)
+	case "UpdateRoomSettings":
+		HandleUpdateRoomSettings(req, conn, clientID, connManager)
+	case "StartRoom":
+		HandleStartRoom(conn, clientID, connManager)
+	case "SpectateRoom":
+		HandleSpectateRoom(req, conn, clientID, connManager)
+	case "StopSpectating":
+		HandleStopSpectating(conn, clientID, connManager)
+	case "PreviewPlay":
+		HandlePreviewPlay(req, conn, clientID, connManager)
+	case "BondHints":
+		HandleBondHints(req, conn, clientID, connManager)
+	case "PauseGame":
+		HandlePauseGame(conn, clientID, connManager)
+	case "ResumeGame":
+		HandleResumeGame(conn, clientID, connManager)
+	case "OfferDraw":
+		HandleOfferDraw(conn, clientID, connManager)
+	case "RespondDraw":
+		HandleRespondDraw(req, conn, clientID, connManager)
+	case "ReloadGameData":
+		HandleReloadGameData(conn, clientID, connManager)
+	case "GetServerStats":
+		HandleGetServerStats(conn, clientID, connManager)
+	case "GetCollection":
+		HandleGetCollection(conn, clientID, connManager)
+	case "GetDecks":
+		HandleGetDecks(conn, clientID, connManager)
+	case "SaveDeck":
+		HandleSaveDeck(req, conn, clientID, connManager)
+	case "DeleteDeck":
+		HandleDeleteDeck(req, conn, clientID, connManager)
+	case "SelectDeck":
+		HandleSelectDeck(req, conn, clientID, connManager)
+	case "StartPractice":
+		HandleStartPractice(req, conn, clientID, connManager)
 	default:
 		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(9999))
 	}
 }
 
-// HandleNewConnection 处理新的TCP连接
-func HandleNewConnection(conn net.Conn) string {
+// HandleNewConnection 处理新的客户端连接（connectionType 为 tcp 或 websocket）
+func HandleNewConnection(conn protocol.Conn, connectionType string) string {
 	remoteAddr := conn.RemoteAddr().String()
 
 	// 生成客户端ID（包含时间戳和地址，确保唯一性）
@@ -78,7 +128,7 @@ func HandleNewConnection(conn net.Conn) string {
 
 	// 发布客户端连接事件
 	connectData := events.CreateConnectionEventData(events.EventClientConnect, clientID, remoteAddr)
-	connectData.AddData("connection_type", "tcp")
+	connectData.AddData("connection_type", connectionType)
 	connectData.AddData("user_agent", "game_client")
 	connectData.AddData("version", "1.0")
 	connectData.AddData("first_connect_time", time.Now().Unix())
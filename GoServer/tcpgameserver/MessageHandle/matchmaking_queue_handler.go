@@ -0,0 +1,48 @@
+package tcpserver
+
+import (
+	"time"
+
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// HandleCancelQueue 处理取消匹配请求
+func HandleCancelQueue(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	// 检查用户是否已登录
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1404))
+		return
+	}
+
+	// 移出匹配队列
+	if _, removed := service.GetMatchmakingQueue().Remove(clientInfo.Username); !removed {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1403))
+		return
+	}
+
+	// 恢复为已登录状态
+	connManager.SetPlayerStatus(clientID, types.StatusLoggedIn)
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1402, nil))
+}
+
+// HandleGetQueueStatus 处理查询排队状态请求（队列位置、评分窗口、预计等待时间）
+func HandleGetQueueStatus(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	// 检查用户是否已登录
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1404))
+		return
+	}
+
+	status, queued := service.GetMatchmakingQueue().Status(clientInfo.Username, time.Now())
+	if !queued {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1403))
+		return
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1401, status))
+}
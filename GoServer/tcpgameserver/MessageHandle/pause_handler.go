@@ -0,0 +1,56 @@
+package tcpserver
+
+import (
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// HandlePauseGame 处理暂停请求，暂停次数和累计时长受限，到期自动恢复
+func HandlePauseGame(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	publishInGameEvent(conn, clientID, connManager, events.EventGamePause, map[string]interface{}{
+		"reason": types.PauseReasonPlayer,
+	})
+}
+
+// HandleResumeGame 处理恢复请求，只有发起暂停的玩家可以提前恢复
+func HandleResumeGame(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	publishInGameEvent(conn, clientID, connManager, events.EventGameResume, map[string]interface{}{
+		"reason": types.PauseReasonPlayer,
+	})
+}
+
+// HandleOfferDraw 处理和棋提议，每名玩家每回合只能提议一次
+func HandleOfferDraw(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	publishInGameEvent(conn, clientID, connManager, events.EventDrawOffer, map[string]interface{}{})
+}
+
+// HandleRespondDraw 处理和棋回应，所有存活玩家同意后以平局结束对局
+func HandleRespondDraw(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	var request models.RespondDrawRequest
+	if !decodeRoomRequest(req, &request) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1817))
+		return
+	}
+
+	publishInGameEvent(conn, clientID, connManager, events.EventDrawResponse, map[string]interface{}{
+		"accept": request.Accept,
+	})
+}
+
+// publishInGameEvent 为游戏中的玩家发布对局事件，事件数据附带客户端ID和用户名
+func publishInGameEvent(conn protocol.Conn, clientID string, connManager *service.ConnectionManager, eventType string, data map[string]interface{}) {
+	clientInfo, ok := getInGameClient(conn, clientID, connManager, 1803)
+	if !ok {
+		return
+	}
+
+	data["client_id"] = clientID
+	data["username"] = clientInfo.Username
+	eventData := events.NewEventData(eventType, "pause_handler", data)
+	eventData.SetRoom(clientInfo.GetGameRoom())
+	events.Publish(eventType, eventData)
+}
@@ -0,0 +1,52 @@
+package tcpserver
+
+import (
+	"strings"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+)
+
+// HandleStartPractice 处理开始人机练习请求，空闲玩家与指定难度的机器人对局，练习对局不计入评分
+func HandleStartPractice(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getIdleRoomClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	request := models.PracticeRequest{Level: models.BotLevelNormal, RoomSize: models.MinRoomSize}
+	if !decodeRoomRequest(req, &request) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2402))
+		return
+	}
+	request.Level = strings.ToLower(strings.TrimSpace(request.Level))
+	if request.Level == "" {
+		request.Level = models.BotLevelNormal
+	}
+	if request.RoomSize == 0 {
+		request.RoomSize = models.MinRoomSize
+	}
+	if !models.ValidBotLevel(request.Level) || request.RoomSize < models.MinRoomSize || request.RoomSize > models.MaxRoomSize {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2402))
+		return
+	}
+	// 固定随机种子仅供管理员复现发牌
+	if request.Seed != 0 && !config.IsGameAdmin(clientInfo.Username) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2405))
+		return
+	}
+
+	// 发布游戏开始事件，由游戏开始处理器创建机器人并开始对局
+	practiceData := events.NewEventData(events.EventGameStart, "practice_handler", map[string]interface{}{
+		"trigger_source": "practice_handler",
+		"client_id":      clientID,
+		"level":          request.Level,
+		"room_size":      request.RoomSize,
+		"seed":           request.Seed,
+	})
+	events.Publish(events.EventGameStart, practiceData)
+}
@@ -0,0 +1,248 @@
+package tcpserver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// HandleCreateRoom 处理创建私人房间请求，房主按预设和自定义设置创建房间并获得邀请码
+func HandleCreateRoom(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getIdleRoomClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	var request models.CreateRoomRequest
+	if !decodeRoomRequest(req, &request) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1509))
+		return
+	}
+
+	// 以预设为基础，覆盖自定义设置
+	preset, exists := config.GetRoomPreset(request.Preset)
+	if !exists {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1509))
+		return
+	}
+	// 固定随机种子仅供管理员复现发牌
+	if request.Seed != 0 && !config.IsGameAdmin(clientInfo.Username) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1514))
+		return
+	}
+	settings := models.MergeRoomSettings(preset, request.Settings)
+	if settings.MaxPlayers == 0 {
+		settings.MaxPlayers = models.MaxRoomSize
+	}
+
+	roomName := request.RoomName
+	if roomName == "" {
+		roomName = fmt.Sprintf("Private Room %s", clientInfo.Username)
+	}
+
+	room, err := service.GetRoomManager().CreatePrivateRoom(roomName, clientInfo.Username, settings)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1509))
+		return
+	}
+	room.SetFixedSeed(request.Seed)
+
+	connManager.SetPlayerStatus(clientID, types.StatusInRoom)
+	connManager.SetPlayerGameRoom(clientID, room.RoomID)
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1501, room.LobbyInfo()))
+}
+
+// HandleJoinRoom 处理通过邀请码加入私人房间请求
+func HandleJoinRoom(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, ok := getIdleRoomClient(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	var request models.JoinRoomRequest
+	if !decodeRoomRequest(req, &request) || request.InviteCode == "" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1505))
+		return
+	}
+
+	roomManager := service.GetRoomManager()
+	room, err := roomManager.GetRoomByInviteCode(request.InviteCode)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1505))
+		return
+	}
+	if room.Status != "waiting" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1507))
+		return
+	}
+	if err := roomManager.JoinRoom(room.RoomID, clientInfo.Username); err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1506))
+		return
+	}
+
+	connManager.SetPlayerStatus(clientID, types.StatusInRoom)
+	connManager.SetPlayerGameRoom(clientID, room.RoomID)
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1502, room.LobbyInfo()))
+
+	// 通知房间内其他玩家
+	joinData := events.NewEventData(events.EventPlayerJoin, "private_room_handler", map[string]interface{}{
+		"username": clientInfo.Username,
+	})
+	joinData.SetRoom(room.RoomID)
+	events.Publish(events.EventPlayerJoin, joinData)
+}
+
+// HandleLeaveRoom 处理离开私人房间请求，房主离开时转交房主，房间为空时删除房间
+func HandleLeaveRoom(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, room, ok := getRoomMember(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+
+	_, removed, err := service.GetRoomManager().LeavePrivateRoom(room.RoomID, clientInfo.Username)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1510))
+		return
+	}
+
+	connManager.SetPlayerStatus(clientID, types.StatusLoggedIn)
+	connManager.SetPlayerGameRoom(clientID, "")
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1503, nil))
+
+	// 通知房间内其他玩家
+	if !removed {
+		leaveData := events.NewEventData(events.EventPlayerLeave, "private_room_handler", map[string]interface{}{
+			"username": clientInfo.Username,
+		})
+		leaveData.SetRoom(room.RoomID)
+		events.Publish(events.EventPlayerLeave, leaveData)
+	}
+}
+
+// HandleUpdateRoomSettings 处理房主修改房间设置请求
+func HandleUpdateRoomSettings(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, room, ok := getRoomMember(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+	if room.GetHost() != clientInfo.Username {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1508))
+		return
+	}
+
+	var request models.UpdateRoomSettingsRequest
+	if !decodeRoomRequest(req, &request) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1509))
+		return
+	}
+
+	// 指定预设时以预设为基础（保留当前人数上限），否则在当前设置上修改
+	settings := room.GetSettings()
+	if request.Preset != "" {
+		preset, exists := config.GetRoomPreset(request.Preset)
+		if !exists {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1509))
+			return
+		}
+		preset.MaxPlayers = settings.MaxPlayers
+		settings = preset
+	}
+	settings = models.MergeRoomSettings(settings, request.Settings)
+
+	if room.Status != "waiting" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1507))
+		return
+	}
+	if err := room.ApplySettings(settings); err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1509))
+		return
+	}
+
+	// 向房间内所有玩家同步新的设置
+	updateData := events.NewEventData(events.EventRoomUpdate, "private_room_handler", map[string]interface{}{
+		"username": clientInfo.Username,
+	})
+	updateData.SetRoom(room.RoomID)
+	events.Publish(events.EventRoomUpdate, updateData)
+}
+
+// HandleStartRoom 处理房主开始私人房间游戏请求
+func HandleStartRoom(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, room, ok := getRoomMember(conn, clientID, connManager)
+	if !ok {
+		return
+	}
+	if room.GetHost() != clientInfo.Username {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1508))
+		return
+	}
+	if room.Status != "waiting" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1507))
+		return
+	}
+	if len(room.GetTurnOrder()) < models.MinRoomSize {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1512))
+		return
+	}
+
+	// 发布游戏开始事件，由游戏开始处理器为房间内玩家开始游戏
+	startData := events.NewEventData(events.EventGameStart, "private_room_handler", map[string]interface{}{
+		"trigger_source": "private_room_handler",
+		"client_id":      clientID,
+	})
+	startData.SetRoom(room.RoomID)
+	events.Publish(events.EventGameStart, startData)
+}
+
+// getIdleRoomClient 获取已登录且不在匹配、房间或游戏中的客户端
+func getIdleRoomClient(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) (*types.ClientInfo, bool) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1513))
+		return nil, false
+	}
+	if clientInfo.GetStatus() != types.StatusLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1511))
+		return nil, false
+	}
+	return clientInfo, true
+}
+
+// getRoomMember 获取已登录且在私人房间中等待的客户端及其所在房间
+func getRoomMember(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) (*types.ClientInfo, *types.RoomInfo, bool) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1513))
+		return nil, nil, false
+	}
+	if clientInfo.GetStatus() != types.StatusInRoom || clientInfo.GetGameRoom() == "" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1510))
+		return nil, nil, false
+	}
+
+	room, err := service.GetRoomManager().GetRoom(clientInfo.GetGameRoom())
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1510))
+		return nil, nil, false
+	}
+	return clientInfo, room, true
+}
+
+// decodeRoomRequest 解析房间请求参数，未提供参数时保持默认值
+func decodeRoomRequest(req models.TcpRequest, v interface{}) bool {
+	if req.Data == nil {
+		return true
+	}
+	dataBytes, err := json.Marshal(req.Data)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(dataBytes, v) == nil
+}
@@ -0,0 +1,92 @@
+package tcpserver
+
+import (
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// HandleSpectateRoom 处理观战请求，观战者只接收公开信息，不加入玩家列表
+func HandleSpectateRoom(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1610))
+		return
+	}
+	if clientInfo.GetStatus() != types.StatusLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1611))
+		return
+	}
+
+	var request models.SpectateRoomRequest
+	if !decodeRoomRequest(req, &request) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1607))
+		return
+	}
+
+	// 通过房间ID或邀请码查找房间
+	roomManager := service.GetRoomManager()
+	var room *types.RoomInfo
+	var err error
+	if request.RoomID != "" {
+		room, err = roomManager.GetRoom(request.RoomID)
+	} else {
+		room, err = roomManager.GetRoomByInviteCode(request.InviteCode)
+	}
+	if err != nil || room.Status != "playing" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1607))
+		return
+	}
+
+	if err := room.AddSpectator(clientInfo.Username, config.GetMaxSpectators()); err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1608))
+		return
+	}
+
+	connManager.SetPlayerStatus(clientID, types.StatusSpectating)
+	connManager.SetPlayerGameRoom(clientID, room.RoomID)
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1601, map[string]interface{}{
+		"RoomID":       room.RoomID,
+		"DelaySeconds": int(config.GetSpectatorDelay().Seconds()),
+	}))
+
+	// 通知玩家并推送当前画面
+	joinData := events.NewEventData(events.EventSpectatorJoin, "spectate_handler", map[string]interface{}{
+		"username": clientInfo.Username,
+	})
+	joinData.SetRoom(room.RoomID)
+	events.Publish(events.EventSpectatorJoin, joinData)
+}
+
+// HandleStopSpectating 处理停止观战请求
+func HandleStopSpectating(conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1610))
+		return
+	}
+	if clientInfo.GetStatus() != types.StatusSpectating {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1609))
+		return
+	}
+
+	roomID := clientInfo.GetGameRoom()
+	connManager.SetPlayerStatus(clientID, types.StatusLoggedIn)
+	connManager.SetPlayerGameRoom(clientID, "")
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1603, nil))
+
+	// 房间仍在进行时通知玩家
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil || !room.RemoveSpectator(clientInfo.Username) {
+		return
+	}
+	leaveData := events.NewEventData(events.EventSpectatorLeave, "spectate_handler", map[string]interface{}{
+		"username": clientInfo.Username,
+	})
+	leaveData.SetRoom(roomID)
+	events.Publish(events.EventSpectatorLeave, leaveData)
+}
@@ -2,16 +2,16 @@ package tcpserver
 
 import (
 	"encoding/json"
-	"net"
 
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 )
 
 // HandleUserComposeCard 处理用户合成卡牌请求
-func HandleUserComposeCard(req models.TcpRequest, conn net.Conn, clientID string, connManager *service.ConnectionManager) {
+func HandleUserComposeCard(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
 
 	// 获取客户端信息
 	clientInfo, _ := connManager.GetConnectionByClientID(clientID)
@@ -41,12 +41,6 @@ func HandleUserComposeCard(req models.TcpRequest, conn net.Conn, clientID string
 		return
 	}
 
-	// 验证卡牌数量（必须是3的倍数）
-	if len(gameInfo.SelfCards)%3 != 0 {
-		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2003))
-		return
-	}
-
 	// 发布卡牌合成事件
 	composeEventData := events.NewEventData(events.EventCardCompose, "user_compose_card_handler", map[string]interface{}{
 		"room_id":   gameInfo.RoomId,
@@ -2,18 +2,19 @@ package tcpserver
 
 import (
 	"encoding/json"
-	"net"
+	"errors"
 
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
 )
 
-// HandleUserLogin 处理用户登录
-func HandleUserLogin(req models.TcpRequest, conn net.Conn, clientID string, connManager *service.ConnectionManager) {
-	var loginData models.UserAccount
+// HandleUserLogin 处理用户登录，支持用户名密码登录、会话令牌登录和Voyara访问令牌登录
+func HandleUserLogin(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	var loginData models.LoginRequest
 	dataBytes, err := json.Marshal(req.Data)
 	if err != nil {
 		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2002))
@@ -24,21 +25,47 @@ func HandleUserLogin(req models.TcpRequest, conn net.Conn, clientID string, conn
 		return
 	}
 
-	// 验证参数
-	if loginData.Username == "" || loginData.Password == "" {
-		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2003))
-		return
-	}
+	if loginData.SessionToken != "" {
+		// 使用会话令牌登录，无需重新发送密码
+		session, err := service.GetSessionManager().ValidateSession(loginData.SessionToken)
+		if err != nil {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(sessionErrorCode(err)))
+			return
+		}
+		if loginData.Username != "" && loginData.Username != session.Username {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2006))
+			return
+		}
+		loginData.Username = session.Username
+	} else if loginData.VoyaraToken != "" {
+		// 使用Voyara访问令牌登录，首次登录时创建关联的游戏账户
+		username, err := service.LoginWithVoyaraToken(loginData.VoyaraToken, loginData.Username)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidVoyaraToken) {
+				SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2008))
+				return
+			}
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2004))
+			return
+		}
+		loginData.Username = username
+	} else {
+		// 验证参数
+		if loginData.Username == "" || loginData.Password == "" {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2003))
+			return
+		}
 
-	// 使用数据库服务验证登录
-	isValid, err := service.ValidateUserLogin(loginData.Username, loginData.Password)
-	if err != nil {
-		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2004))
-		return
-	}
-	if !isValid {
-		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2005))
-		return
+		// 使用数据库服务验证登录
+		isValid, err := service.ValidateUserLogin(loginData.Username, loginData.Password)
+		if err != nil {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2004))
+			return
+		}
+		if !isValid {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2005))
+			return
+		}
 	}
 	// 检查用户是否已经登录
 	if existingClient, isLoggedIn := connManager.GetConnectionByUsername(loginData.Username); isLoggedIn {
@@ -77,7 +104,96 @@ func HandleUserLogin(req models.TcpRequest, conn net.Conn, clientID string, conn
 	// 设置玩家状态为已登录
 	connManager.SetPlayerStatus(clientID, types.StatusLoggedIn)
 
+	// 签发新的会话令牌，之前的令牌失效；断线后凭令牌登录或恢复对局
+	session, err := service.GetSessionManager().CreateSession(loginData.Username)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2004))
+		return
+	}
+
 	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2001, map[string]interface{}{
-		"username": loginData.Username,
+		"username":      loginData.Username,
+		"session_token": session.Token,
+		"expires_at":    session.ExpiresAt.Unix(),
+	}))
+}
+
+// HandleResumeSession 处理凭会话令牌恢复对局的请求，只有等待重连中的玩家可以恢复
+func HandleResumeSession(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	var request models.ResumeSessionRequest
+	dataBytes, err := json.Marshal(req.Data)
+	if err != nil || json.Unmarshal(dataBytes, &request) != nil || request.SessionToken == "" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(6003))
+		return
+	}
+
+	session, err := service.GetSessionManager().ValidateSession(request.SessionToken)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(6003))
+		return
+	}
+
+	existingClient, exists := connManager.GetConnectionByUsername(session.Username)
+	if !exists || existingClient.GetStatus() != types.StatusWaitingReconnect {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(6002))
+		return
+	}
+
+	// 发送重连事件
+	reconnectData := events.CreateUserConnectionEventData(
+		events.EventClientReconnect, clientID, session.Username, conn.RemoteAddr().String())
+	reconnectData.AddData("old_client_id", existingClient.ClientID)
+	events.Publish(events.EventClientReconnect, reconnectData)
+}
+
+// sessionErrorCode 会话令牌登录失败对应的响应码
+func sessionErrorCode(err error) int {
+	if errors.Is(err, service.ErrSessionExpired) {
+		return 2007
+	}
+	return 2006
+}
+
+// HandleLinkVoyara 将当前登录的游戏账户关联到Voyara用户，关联后可以使用Voyara访问令牌登录该账户
+func HandleLinkVoyara(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2011))
+		return
+	}
+
+	var request models.LinkVoyaraRequest
+	dataBytes, err := json.Marshal(req.Data)
+	if err != nil || json.Unmarshal(dataBytes, &request) != nil || request.VoyaraToken == "" {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2008))
+		return
+	}
+
+	voyaraUserID, err := service.ParseVoyaraToken(request.VoyaraToken)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2008))
+		return
+	}
+
+	linkedUsername, linked, err := service.GetUsernameByVoyaraUserID(voyaraUserID)
+	if err != nil {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2004))
+		return
+	}
+	// Voyara用户已关联其他游戏账户，或当前账户已关联其他Voyara用户
+	if linked && linkedUsername != clientInfo.Username {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2010))
+		return
+	}
+	if !linked {
+		if err := service.LinkVoyaraUser(clientInfo.Username, voyaraUserID); err != nil {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2010))
+			return
+		}
+	}
+
+	SendTCPResponse(conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2009, map[string]interface{}{
+		"username":       clientInfo.Username,
+		"voyara_user_id": voyaraUserID,
 	}))
 }
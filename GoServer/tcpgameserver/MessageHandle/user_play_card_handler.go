@@ -2,16 +2,16 @@ package tcpserver
 
 import (
 	"encoding/json"
-	"net"
 
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 )
 
 // HandleUserPlayCard 处理用户出牌请求（仅负责数据解析验证和事件发布）
-func HandleUserPlayCard(req models.TcpRequest, conn net.Conn, clientID string, connManager *service.ConnectionManager) {
+func HandleUserPlayCard(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
 	// 获取客户端信息
 	clientInfo, _ := connManager.GetConnectionByClientID(clientID)
 
@@ -37,6 +37,7 @@ func HandleUserPlayCard(req models.TcpRequest, conn net.Conn, clientID string, c
 	playCardEventData := events.NewEventData(events.EventCardPlay, "user_play_card_handler", map[string]interface{}{
 		"player":     clientInfo.Username,
 		"self_cards": playCardData.SelfCards,
+		"target":     playCardData.Target,
 		"room_id":    playCardData.RoomId,
 		"client_id":  clientID,
 		"connection": conn,
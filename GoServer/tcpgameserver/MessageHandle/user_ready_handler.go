@@ -1,16 +1,19 @@
 package tcpserver
 
 import (
-	"net"
+	"encoding/json"
+	"strings"
 
 	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
 )
 
 // HandleUserReady 处理用户准备
-func HandleUserReady(conn net.Conn, clientID string, connManager *service.ConnectionManager) {
+func HandleUserReady(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
 	// 获取客户端信息
 	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
 	if !exists {
@@ -24,6 +27,28 @@ func HandleUserReady(conn net.Conn, clientID string, connManager *service.Connec
 		return
 	}
 
+	// 只有空闲的玩家可以加入匹配，匹配中、私人房间中、观战中或游戏中（含等待重连）的玩家不能再次匹配
+	if clientInfo.GetStatus() != types.StatusLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1511))
+		return
+	}
+
+	// 解析期望的房间人数和使用的卡组
+	request, ok := parseReadyRequest(req)
+	if !ok {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1405))
+		return
+	}
+	roomSize := request.RoomSize
+
+	// 指定卡组时检查卡组后记录，未指定时沿用之前选择的卡组
+	if request.Deck != "" {
+		if code, ok := selectDeck(clientInfo, request.Deck); !ok {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(code))
+			return
+		}
+	}
+
 	// 设置玩家状态为准备就绪
 	connManager.SetPlayerStatus(clientID, types.StatusReady)
 	stats := connManager.GetConnectionStats()
@@ -35,15 +60,36 @@ func HandleUserReady(conn net.Conn, clientID string, connManager *service.Connec
 	// 发布羁绊数据，让事件监听器处理匹配逻辑
 	events.Publish(events.EventCardBonds, gameStartData)
 
-	if stats["ready"] >= 2 {
+	// 创建游戏开始事件数据，玩家将加入匹配队列
+	matchData := events.CreateRoomEventData(events.EventGameStart, "new_room", int(stats["ready"]))
+	matchData.AddData("message", "Player ready, joining matchmaking queue")
+	matchData.AddData("trigger_source", "user_ready_handler")
+	matchData.AddData("client_id", clientID)
+	matchData.AddData("room_size", roomSize)
 
-		// 创建游戏开始事件数据
-		gameStartData := events.CreateRoomEventData(events.EventGameStart, "new_room", int(stats["ready"]))
-		gameStartData.AddData("message", "Players ready, starting matchmaking")
-		gameStartData.AddData("trigger_source", "user_ready_handler")
+	// 发布游戏开始事件，让事件监听器处理匹配逻辑
+	events.Publish(events.EventGameStart, matchData)
+}
 
-		// 发布游戏开始事件，让事件监听器处理匹配逻辑
-		events.Publish(events.EventGameStart, gameStartData)
+// parseReadyRequest 解析准备请求，房间人数未提供时默认为2人
+func parseReadyRequest(req models.TcpRequest) (*models.ReadyRequest, bool) {
+	request := models.ReadyRequest{RoomSize: models.MinRoomSize}
+	if req.Data != nil {
+		dataBytes, err := json.Marshal(req.Data)
+		if err != nil {
+			return nil, false
+		}
+		if err := json.Unmarshal(dataBytes, &request); err != nil {
+			return nil, false
+		}
+	}
 
+	if request.RoomSize == 0 {
+		request.RoomSize = models.MinRoomSize
+	}
+	if request.RoomSize < models.MinRoomSize || request.RoomSize > models.MaxRoomSize {
+		return nil, false
 	}
+	request.Deck = strings.TrimSpace(request.Deck)
+	return &request, true
 }
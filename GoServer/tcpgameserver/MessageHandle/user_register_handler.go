@@ -2,16 +2,16 @@ package tcpserver
 
 import (
 	"encoding/json"
-	"net"
 	"strings"
 
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 )
 
 // HandleUserRegister 处理用户注册
-func HandleUserRegister(req models.TcpRequest, conn net.Conn, clientID string, connManager *service.ConnectionManager) {
+func HandleUserRegister(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
 	var registerData models.UserAccount
 	dataBytes, err := json.Marshal(req.Data)
 	if err != nil {
@@ -29,6 +29,12 @@ func HandleUserRegister(req models.TcpRequest, conn net.Conn, clientID string, c
 		return
 	}
 
+	// 机器人用户名前缀为保留前缀
+	if models.IsBotUsername(registerData.Username) {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2404))
+		return
+	}
+
 	// 使用数据库服务创建用户
 	err = service.CreateUserAccount(registerData.Username, registerData.Password)
 	if err != nil {
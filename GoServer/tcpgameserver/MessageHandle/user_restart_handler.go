@@ -1,16 +1,16 @@
 package tcpserver
 
 import (
-	"net"
-
 	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
 )
 
 // HandleUserReady 处理用户准备
-func HandleUserRestart(conn net.Conn, clientID string, connManager *service.ConnectionManager) {
+func HandleUserRestart(req models.TcpRequest, conn protocol.Conn, clientID string, connManager *service.ConnectionManager) {
 	// 获取客户端信息
 	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
 	if !exists {
@@ -24,18 +24,39 @@ func HandleUserRestart(conn net.Conn, clientID string, connManager *service.Conn
 		return
 	}
 
+	// 只有空闲的玩家可以加入匹配，匹配中、私人房间中、观战中或游戏中（含等待重连）的玩家不能再次匹配
+	if clientInfo.GetStatus() != types.StatusLoggedIn {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1511))
+		return
+	}
+
+	// 解析期望的房间人数和使用的卡组
+	request, ok := parseReadyRequest(req)
+	if !ok {
+		SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1405))
+		return
+	}
+	roomSize := request.RoomSize
+
+	// 指定卡组时检查卡组后记录，未指定时沿用之前选择的卡组
+	if request.Deck != "" {
+		if code, ok := selectDeck(clientInfo, request.Deck); !ok {
+			SendTCPResponse(conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(code))
+			return
+		}
+	}
+
 	// 设置玩家状态为准备就绪
 	connManager.SetPlayerStatus(clientID, types.StatusReady)
-	stats := connManager.GetConnectionStats() // 匹配逻辑 - 当有足够玩家准备就绪时发送游戏开始事件
-	if stats["ready"] >= 2 {
+	stats := connManager.GetConnectionStats() // 匹配逻辑 - 玩家重新加入匹配队列
 
-		// 创建游戏开始事件数据
-		gameStartData := events.CreateRoomEventData(events.EventGameStart, "new_room", int(stats["ready"]))
-		gameStartData.AddData("message", "Players ready, starting matchmaking")
-		gameStartData.AddData("trigger_source", "user_ready_handler")
+	// 创建游戏开始事件数据
+	gameStartData := events.CreateRoomEventData(events.EventGameStart, "new_room", int(stats["ready"]))
+	gameStartData.AddData("message", "Player ready, joining matchmaking queue")
+	gameStartData.AddData("trigger_source", "user_ready_handler")
+	gameStartData.AddData("client_id", clientID)
+	gameStartData.AddData("room_size", roomSize)
 
-		// 发布游戏开始事件，让事件监听器处理匹配逻辑
-		events.Publish(events.EventGameStart, gameStartData)
-
-	}
+	// 发布游戏开始事件，让事件监听器处理匹配逻辑
+	events.Publish(events.EventGameStart, gameStartData)
 }
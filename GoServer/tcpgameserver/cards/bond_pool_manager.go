@@ -2,8 +2,6 @@ package cards
 
 import (
 	"GoServer/tcpgameserver/models"
-	"GoServer/tcpgameserver/service"
-	"fmt"
 	"sync"
 )
 
@@ -28,27 +26,18 @@ func GetBondPoolManager() *BondPoolManager {
 	return bondPool
 }
 
-// InitBondPool 初始化羁绊池
+// InitBondPool 初始化羁绊池，与卡牌池一同从数据库加载为新的卡牌集快照
 func InitBondPool() error {
-	manager := GetBondPoolManager()
-	manager.mutex.Lock()
-	defer manager.mutex.Unlock()
-
-
-	// 从数据库获取所有羁绊数据
-	bonds, err := service.GetAllBonds()
-	if err != nil {
-		return fmt.Errorf("failed to load bonds from database: %v", err)
-	}
+	_, err := LoadCardSet()
+	return err
+}
 
-	// 清空现有羁绊池
-	manager.bonds = make(map[int]*models.BondModel)
+// apply 使用卡牌集快照替换羁绊池内容
+func (bpm *BondPoolManager) apply(cardSet *models.CardSet) {
+	bpm.mutex.Lock()
+	defer bpm.mutex.Unlock()
 
-	// 加载羁绊数据到池中
-	for _, bond := range bonds {
-		manager.bonds[bond.ID] = &bond
-	}
-	return nil
+	bpm.bonds = cardSet.GetAllBonds()
 }
 
 // GetBondByID 根据ID获取羁绊
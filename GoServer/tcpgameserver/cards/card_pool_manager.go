@@ -2,7 +2,6 @@ package cards
 
 import (
 	"GoServer/tcpgameserver/models"
-	"GoServer/tcpgameserver/service"
 	"fmt"
 	"sync"
 )
@@ -34,54 +33,19 @@ func GetCardPoolManager() *CardPoolManager {
 	return cardPool
 }
 
-// InitCardPool 初始化卡牌池
+// InitCardPool 初始化卡牌池，与羁绊池一同从数据库加载为新的卡牌集快照
 func InitCardPool() error {
-	manager := GetCardPoolManager()
-	manager.mutex.Lock()
-	defer manager.mutex.Unlock()
-
-	// 从数据库获取所有卡牌配置
-	cardDecks, err := service.GetAllCardDeck()
-	if err != nil {
-		return fmt.Errorf("failed to load card decks from database: %v", err)
-	} // 清空现有卡牌池
-	manager.ALLCards = make([]models.Card, 0)
-	manager.level1Cards = make([]models.Card, 0)
-	manager.level2Cards = make([]models.Card, 0)
-	manager.level3Cards = make([]models.Card, 0)
-
-	totalCards := 0
-
-	// 根据CardDeck配置创建卡牌实例
-	for _, deck := range cardDecks {
-		// 创建卡牌模板（只创建一个实例作为模板）
-		cardTemplate := models.NewCard(deck.ID, deck.Name, deck.Damage, deck.TargetName, deck.Level)
-
-		// 将卡牌模板添加到总卡牌模板池（每种卡牌类型只存储一个模板）
-		manager.ALLCards = append(manager.ALLCards, cardTemplate)
-
-		// 根据cards_num创建对应数量的卡牌实例到各等级池中
-		for i := 0; i < deck.CardsNum; i++ {
-			card := models.NewCard(deck.ID, deck.Name, deck.Damage, deck.TargetName, deck.Level)
-
-			// 根据等级分配到不同的卡牌池
-			switch deck.Level {
-			case 1:
-				manager.level1Cards = append(manager.level1Cards, card)
-			case 2:
-				manager.level2Cards = append(manager.level2Cards, card)
-			case 3:
-				manager.level3Cards = append(manager.level3Cards, card)
-			default:
-				continue
-			}
-
-			totalCards++
-		}
-	}
+	_, err := LoadCardSet()
+	return err
+}
 
+// apply 使用卡牌集快照替换卡牌池内容
+func (cpm *CardPoolManager) apply(cardSet *models.CardSet) {
+	cpm.mutex.Lock()
+	defer cpm.mutex.Unlock()
 
-	return nil
+	cpm.ALLCards = cardSet.Templates
+	cpm.level1Cards, cpm.level2Cards, cpm.level3Cards = cardSet.GetCardPools()
 }
 
 // GetLevel1Cards 获取1级卡牌池（副本）
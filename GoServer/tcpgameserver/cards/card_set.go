@@ -0,0 +1,125 @@
+package cards
+
+import (
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/service"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+var currentCardSet atomic.Pointer[models.CardSet] // 当前卡牌集快照
+
+// LoadCardSet 加载卡牌和羁绊定义，生成新的卡牌集快照并替换当前快照
+func LoadCardSet() (*models.CardSet, error) {
+	cardSet, err := FetchCardSet()
+	if err != nil {
+		return nil, err
+	}
+	ActivateCardSet(cardSet)
+	return cardSet, nil
+}
+
+// FetchCardSet 读取卡牌和羁绊定义（数据包文件或MySQL）并生成卡牌集快照，不影响当前快照
+func FetchCardSet() (*models.CardSet, error) {
+	pack, err := service.LoadGameData()
+	if err != nil {
+		return nil, err
+	}
+	return BuildCardSet(pack.Cards, pack.Bonds, pack.Recipes)
+}
+
+// ActivateCardSet 原子替换当前卡牌集快照，之后开局的房间使用新快照
+// 已开局的房间持有各自的快照，不受替换影响
+func ActivateCardSet(cardSet *models.CardSet) {
+	GetCardPoolManager().apply(cardSet)
+	GetBondPoolManager().apply(cardSet)
+	currentCardSet.Store(cardSet)
+}
+
+// CurrentCardSet 获取当前卡牌集快照，尚未加载时返回空快照
+func CurrentCardSet() *models.CardSet {
+	if cardSet := currentCardSet.Load(); cardSet != nil {
+		return cardSet
+	}
+	return &models.CardSet{Bonds: make(map[int]*models.BondModel)}
+}
+
+// BuildCardSet 根据卡组配置、羁绊数据和合成配方创建卡牌集快照
+func BuildCardSet(cardDecks []models.CardDeck, bonds []models.BondModel, recipes []models.ComposeRecipe) (*models.CardSet, error) {
+	version, err := cardSetVersion(cardDecks, bonds, recipes)
+	if err != nil {
+		return nil, err
+	}
+
+	cardSet := &models.CardSet{
+		Version:     version,
+		LoadedAt:    time.Now(),
+		Templates:   make([]models.Card, 0, len(cardDecks)),
+		Level1Cards: make([]models.Card, 0),
+		Level2Cards: make([]models.Card, 0),
+		Level3Cards: make([]models.Card, 0),
+		Bonds:       make(map[int]*models.BondModel, len(bonds)),
+		Recipes:     make([]models.ComposeRecipe, 0, len(recipes)+len(cardDecks)),
+	}
+
+	for _, deck := range cardDecks {
+		// 每种卡牌只创建一个模板
+		cardSet.Templates = append(cardSet.Templates, models.NewCard(deck.ID, deck.Name, deck.Damage, deck.TargetName, deck.Level))
+
+		// 根据cards_num创建对应数量的卡牌实例到各等级池中
+		for i := 0; i < deck.CardsNum; i++ {
+			card := models.NewCard(deck.ID, deck.Name, deck.Damage, deck.TargetName, deck.Level)
+			switch deck.Level {
+			case 1:
+				cardSet.Level1Cards = append(cardSet.Level1Cards, card)
+			case 2:
+				cardSet.Level2Cards = append(cardSet.Level2Cards, card)
+			case 3:
+				cardSet.Level3Cards = append(cardSet.Level3Cards, card)
+			}
+		}
+	}
+
+	for i := range bonds {
+		bond := bonds[i]
+		cardSet.Bonds[bond.ID] = &bond
+	}
+
+	// 配置的配方按ID顺序优先匹配，其后为按卡牌合成目标生成的默认配方
+	cardSet.Recipes = append(cardSet.Recipes, recipes...)
+	sort.SliceStable(cardSet.Recipes, func(i, j int) bool { return cardSet.Recipes[i].ID < cardSet.Recipes[j].ID })
+	for _, deck := range cardDecks {
+		if recipe, ok := models.TargetComposeRecipe(deck); ok {
+			cardSet.Recipes = append(cardSet.Recipes, recipe)
+		}
+	}
+
+	return cardSet, nil
+}
+
+// cardSetVersion 根据卡组配置、羁绊数据和合成配方计算版本号，内容相同的定义得到相同的版本号（与读取顺序无关）
+func cardSetVersion(cardDecks []models.CardDeck, bonds []models.BondModel, recipes []models.ComposeRecipe) (string, error) {
+	cardDecks = append([]models.CardDeck(nil), cardDecks...)
+	sort.Slice(cardDecks, func(i, j int) bool { return cardDecks[i].ID < cardDecks[j].ID })
+	bonds = append([]models.BondModel(nil), bonds...)
+	sort.Slice(bonds, func(i, j int) bool { return bonds[i].ID < bonds[j].ID })
+	recipes = append([]models.ComposeRecipe(nil), recipes...)
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].ID < recipes[j].ID })
+
+	content, err := json.Marshal(struct {
+		Cards   []models.CardDeck      `json:"cards"`
+		Bonds   []models.BondModel     `json:"bonds"`
+		Recipes []models.ComposeRecipe `json:"recipes,omitempty"`
+	}{cardDecks, bonds, recipes})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode card set: %v", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:6]), nil
+}
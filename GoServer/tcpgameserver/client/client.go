@@ -0,0 +1,269 @@
+// Package client 游戏服务器JSON协议的Go客户端，用于压力测试和模拟对局
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+)
+
+// 服务器推送和成功响应的消息码
+const (
+	CodeWelcome        = "1001" // 连接成功
+	CodeGameEnd        = "1101" // 游戏结束
+	CodeQueueStatus    = "1401" // 排队状态
+	CodeServerStats    = "1904" // 服务器运行状态
+	CodeLoginSuccess   = "2001" // 登录成功
+	CodeComposeSuccess = "2301" // 合成结果
+	CodeComposeFailed  = "2302" // 合成失败
+	CodeRegisterOK     = "3001" // 注册成功
+	CodeUserExists     = "3004" // 用户已存在
+	CodeGameStart      = "5001" // 游戏开始
+	CodeBonds          = "5002" // 羁绊数据
+	CodeTimeoutUpdate  = "7001" // 回合超时后的状态更新
+	CodePlayUpdate     = "8001" // 出牌后的状态更新
+	CodeComposeUpdate  = "9001" // 合成后的状态更新
+)
+
+// messageBufferSize 未读取消息的缓冲数量，缓冲满时暂停读取连接
+const messageBufferSize = 256
+
+// ErrTimeout 等待消息超时
+var ErrTimeout = errors.New("timed out waiting for server message")
+
+// Response 服务器发送的消息及接收时间
+type Response struct {
+	models.TcpResponse
+	ReceivedAt time.Time
+}
+
+// Decode 将消息数据解析到指定结构
+func (r *Response) Decode(v interface{}) error {
+	dataBytes, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dataBytes, v)
+}
+
+// Client 游戏服务器客户端：后台协程按帧读取服务器消息，调用方通过Next或WaitFor依次取出
+type Client struct {
+	conn     net.Conn
+	decoder  *protocol.Decoder
+	encoder  *protocol.Encoder
+	writeMu  sync.Mutex
+	messages chan *Response
+	done     chan struct{}
+	once     sync.Once
+	errMu    sync.Mutex
+	err      error // 读取结束的原因
+}
+
+// Dial 连接服务器并协商帧模式，timeout为连接和握手的超时时间
+// 握手应答之前收到的服务器消息按顺序保留，可通过Next取出
+func Dial(addr string, mode protocol.FrameMode, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// 服务器不等待握手，握手应答之前收到的消息（如欢迎消息）使用换行分隔模式
+	// 握手应答始终以换行结尾，之后的消息使用协商的帧模式
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write([]byte("FRAME " + string(mode) + "\n")); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send handshake: %v", err)
+	}
+
+	c := &Client{
+		conn:     conn,
+		encoder:  protocol.NewEncoder(mode),
+		messages: make(chan *Response, messageBufferSize),
+		done:     make(chan struct{}),
+	}
+
+	reader := bufio.NewReader(conn)
+	lineDecoder := protocol.NewDecoder(reader, protocol.FrameModeLine)
+	for {
+		line, err := lineDecoder.ReadFrame()
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to read handshake reply: %v", err)
+		}
+		reply := string(line)
+		if reply == "FRAME OK "+string(mode) {
+			break
+		}
+		if strings.HasPrefix(reply, "FRAME ") {
+			conn.Close()
+			return nil, fmt.Errorf("handshake rejected: %q", reply)
+		}
+
+		response := &Response{ReceivedAt: time.Now()}
+		if err := json.Unmarshal(line, &response.TcpResponse); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("invalid server message before handshake reply: %v", err)
+		}
+		c.messages <- response
+	}
+	conn.SetDeadline(time.Time{})
+
+	c.decoder = protocol.NewDecoder(reader, mode)
+	go c.readLoop()
+	return c, nil
+}
+
+// readLoop 读取服务器消息直到连接关闭
+func (c *Client) readLoop() {
+	defer close(c.messages)
+
+	for {
+		frame, err := c.decoder.ReadFrame()
+		if err != nil {
+			c.setErr(err)
+			return
+		}
+
+		response := &Response{ReceivedAt: time.Now()}
+		if err := json.Unmarshal(frame, &response.TcpResponse); err != nil {
+			c.setErr(fmt.Errorf("invalid server message: %v", err))
+			return
+		}
+
+		select {
+		case c.messages <- response:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// Send 发送请求消息
+func (c *Client) Send(message string, data interface{}) error {
+	payload, err := json.Marshal(models.TcpRequest{Message: message, Data: data})
+	if err != nil {
+		return err
+	}
+	frame, err := c.encoder.Encode(payload)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = c.conn.Write(frame)
+	return err
+}
+
+// Next 取出下一条服务器消息，连接已关闭时返回读取结束的原因
+func (c *Client) Next(timeout time.Duration) (*Response, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case response, ok := <-c.messages:
+		if !ok {
+			return nil, c.Err()
+		}
+		return response, nil
+	case <-timer.C:
+		return nil, ErrTimeout
+	}
+}
+
+// WaitFor 等待消息码为指定值之一的消息，期间收到的其他消息被丢弃
+func (c *Client) WaitFor(timeout time.Duration, codes ...string) (*Response, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, ErrTimeout
+		}
+		response, err := c.Next(remaining)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range codes {
+			if response.Code == code {
+				return response, nil
+			}
+		}
+	}
+}
+
+// Register 注册账户
+func (c *Client) Register(username, password string) error {
+	return c.Send("UserRegister", models.UserAccount{Username: username, Password: password})
+}
+
+// Login 使用用户名和密码登录
+func (c *Client) Login(username, password string) error {
+	return c.Send("UserLogin", models.LoginRequest{Username: username, Password: password})
+}
+
+// Ready 准备并加入指定人数的匹配队列
+func (c *Client) Ready(roomSize int) error {
+	return c.Send("UserReady", models.ReadyRequest{RoomSize: roomSize})
+}
+
+// Restart 游戏结束后重新加入匹配队列
+func (c *Client) Restart(roomSize int) error {
+	return c.Send("UserRestart", models.ReadyRequest{RoomSize: roomSize})
+}
+
+// PlayCard 打出手牌，target为玩家用户名、all或self，为空时攻击下一名存活玩家
+func (c *Client) PlayCard(roomID string, cards []models.Card, target string) error {
+	return c.Send("UserPlayCard", models.PlayerGameInfo{RoomId: roomID, SelfCards: cards, Target: target})
+}
+
+// ComposeCard 提交手牌进行合成
+func (c *Client) ComposeCard(roomID string, cards []models.Card) error {
+	return c.Send("UserComposeCard", models.PlayerGameInfo{RoomId: roomID, SelfCards: cards})
+}
+
+// GetServerStats 查询服务器运行状态（需要管理员账户）
+func (c *Client) GetServerStats() error {
+	return c.Send("GetServerStats", nil)
+}
+
+// Close 关闭连接并停止读取
+func (c *Client) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// Err 获取读取结束的原因，连接仍在读取时为nil
+func (c *Client) Err() error {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	if c.err == nil {
+		select {
+		case <-c.done:
+			return io.EOF
+		default:
+		}
+	}
+	return c.err
+}
+
+// setErr 记录读取结束的原因
+func (c *Client) setErr(err error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import "strings"
+
+// GetGameAdmins 获取可以执行管理操作（如热加载卡牌数据）的用户名列表
+// 环境变量 GAME_ADMINS，多个用户名以逗号分隔，默认没有管理员
+func GetGameAdmins() []string {
+	var admins []string
+	for _, username := range strings.Split(envOrDefault("GAME_ADMINS", ""), ",") {
+		if username = strings.TrimSpace(username); username != "" {
+			admins = append(admins, username)
+		}
+	}
+	return admins
+}
+
+// IsGameAdmin 判断用户是否为管理员
+func IsGameAdmin(username string) bool {
+	if username == "" {
+		return false
+	}
+	for _, admin := range GetGameAdmins() {
+		if admin == username {
+			return true
+		}
+	}
+	return false
+}
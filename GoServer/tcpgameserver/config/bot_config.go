@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"GoServer/tcpgameserver/models"
+)
+
+// GetBotFillWait 获取匹配队列中玩家等待多久后由机器人补足房间（环境变量 BOT_FILL_WAIT_SECONDS，默认0表示不补充机器人）
+func GetBotFillWait() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("BOT_FILL_WAIT_SECONDS", "0"))
+	if err != nil || value <= 0 {
+		return 0
+	}
+	return time.Duration(value) * time.Second
+}
+
+// GetBotFillLevel 获取补充到匹配房间的机器人难度（环境变量 BOT_FILL_LEVEL，默认normal）
+func GetBotFillLevel() string {
+	level := strings.ToLower(envOrDefault("BOT_FILL_LEVEL", models.BotLevelNormal))
+	if !models.ValidBotLevel(level) {
+		return models.BotLevelNormal
+	}
+	return level
+}
+
+// GetBotThinkDelay 获取机器人每次行动前的思考时间（环境变量 BOT_THINK_DELAY_MS，默认1500毫秒，0表示立即行动）
+func GetBotThinkDelay() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("BOT_THINK_DELAY_MS", "1500"))
+	if err != nil || value < 0 {
+		return 1500 * time.Millisecond
+	}
+	return time.Duration(value) * time.Millisecond
+}
+
+// GetMaxActiveBots 获取同时存在的机器人数量上限（环境变量 MAX_ACTIVE_BOTS，默认100）
+func GetMaxActiveBots() int {
+	return positiveIntOrDefault("MAX_ACTIVE_BOTS", 100)
+}
@@ -0,0 +1,7 @@
+package config
+
+// GetDataPackPath 获取卡牌、羁绊和响应码数据包文件路径（环境变量 GAME_DATA_PACK）
+// 支持 .json、.yaml 和 .yml 文件；为空时从MySQL读取
+func GetDataPackPath() string {
+	return envOrDefault("GAME_DATA_PACK", "")
+}
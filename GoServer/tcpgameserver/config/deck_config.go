@@ -0,0 +1,30 @@
+package config
+
+import (
+	"GoServer/tcpgameserver/models"
+	"strconv"
+)
+
+// GetDeckRules 获取组卡规则
+// 环境变量 DECK_MIN_SIZE（默认20）、DECK_MAX_SIZE（默认40）、DECK_MAX_COPIES（默认3）、MAX_DECKS_PER_PLAYER（默认10）
+func GetDeckRules() models.DeckRules {
+	rules := models.DeckRules{
+		MinSize:   positiveIntOrDefault("DECK_MIN_SIZE", 20),
+		MaxSize:   positiveIntOrDefault("DECK_MAX_SIZE", 40),
+		MaxCopies: positiveIntOrDefault("DECK_MAX_COPIES", 3),
+		MaxDecks:  positiveIntOrDefault("MAX_DECKS_PER_PLAYER", 10),
+	}
+	if rules.MaxSize < rules.MinSize {
+		rules.MaxSize = rules.MinSize
+	}
+	return rules
+}
+
+// positiveIntOrDefault 读取正整数环境变量，未设置或无效时使用默认值
+func positiveIntOrDefault(key string, fallback int) int {
+	value, err := strconv.Atoi(envOrDefault(key, strconv.Itoa(fallback)))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,13 @@
+package config
+
+import "strconv"
+
+// GetFatigueDamage 获取匹配房间的疲劳伤害：卡牌池和弃牌堆都为空时每少抽一张卡牌受到的伤害
+// （环境变量 FATIGUE_DAMAGE，默认0表示关闭），私人房间使用房间设置中的疲劳伤害
+func GetFatigueDamage() float64 {
+	value, err := strconv.ParseFloat(envOrDefault("FATIGUE_DAMAGE", "0"), 64)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,17 @@
+package config
+
+import "strconv"
+
+// GetFixedRoomSeed 获取固定的房间随机种子（环境变量 ROOM_SEED），用于测试环境复现发牌
+func GetFixedRoomSeed() (int64, bool) {
+	value := envOrDefault("ROOM_SEED", "")
+	if value == "" {
+		return 0, false
+	}
+
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seed, true
+}
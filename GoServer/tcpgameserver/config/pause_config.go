@@ -0,0 +1,33 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// GetMaxPausesPerPlayer 获取每名玩家每局可主动暂停的次数（环境变量 MAX_PAUSES_PER_PLAYER，默认2）
+func GetMaxPausesPerPlayer() int {
+	value, err := strconv.Atoi(envOrDefault("MAX_PAUSES_PER_PLAYER", "2"))
+	if err != nil || value < 0 {
+		return 2
+	}
+	return value
+}
+
+// GetPauseDuration 获取单次暂停的最长时长，到期自动恢复（环境变量 PAUSE_DURATION_SECONDS，默认60秒）
+func GetPauseDuration() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("PAUSE_DURATION_SECONDS", "60"))
+	if err != nil || value <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(value) * time.Second
+}
+
+// GetMaxTotalPause 获取每局累计可暂停的时长（环境变量 MAX_TOTAL_PAUSE_SECONDS，默认180秒）
+func GetMaxTotalPause() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("MAX_TOTAL_PAUSE_SECONDS", "180"))
+	if err != nil || value < 0 {
+		return 180 * time.Second
+	}
+	return time.Duration(value) * time.Second
+}
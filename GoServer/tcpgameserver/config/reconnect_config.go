@@ -0,0 +1,25 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// GetReconnectWindow 获取匹配房间的断线重连等待时间，超时未重连判负（环境变量 RECONNECT_WINDOW_SECONDS，默认60秒）
+// 私人房间使用房间设置中的等待时间
+func GetReconnectWindow() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("RECONNECT_WINDOW_SECONDS", "60"))
+	if err != nil || value <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(value) * time.Second
+}
+
+// GetReconnectCountdownInterval 获取向对手推送重连倒计时的间隔（环境变量 RECONNECT_COUNTDOWN_INTERVAL_SECONDS，默认5秒）
+func GetReconnectCountdownInterval() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("RECONNECT_COUNTDOWN_INTERVAL_SECONDS", "5"))
+	if err != nil || value <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(value) * time.Second
+}
@@ -0,0 +1,6 @@
+package config
+
+// GetReplayDir 获取回放日志目录
+func GetReplayDir() string {
+	return envOrDefault("REPLAY_DIR", "replays")
+}
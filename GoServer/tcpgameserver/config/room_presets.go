@@ -0,0 +1,36 @@
+package config
+
+import (
+	"strings"
+
+	"GoServer/tcpgameserver/models"
+)
+
+// DefaultRoomPreset 默认房间规则预设
+const DefaultRoomPreset = "standard"
+
+// roomPresets 私人房间规则预设，预设不限制玩家数量
+var roomPresets = map[string]models.RoomSettings{
+	// 标准规则，与匹配房间一致
+	"standard": {InitialHealth: 50, MaxHandCards: 10, TurnTimeoutSeconds: 30, OpeningHandSize: 6, ReconnectWindowSeconds: 60,
+		TimeoutPolicy: "pass", MaxConsecutiveTimeouts: intPtr(3)},
+	// 快节奏：血量低、回合短，超时自动出牌
+	"blitz": {InitialHealth: 30, MaxHandCards: 8, TurnTimeoutSeconds: 15, OpeningHandSize: 5, ReconnectWindowSeconds: 30,
+		TimeoutPolicy: "auto_play", MaxConsecutiveTimeouts: intPtr(2)},
+	// 持久战：血量高、手牌上限高
+	"marathon": {InitialHealth: 100, MaxHandCards: 12, TurnTimeoutSeconds: 45, OpeningHandSize: 8, ReconnectWindowSeconds: 120,
+		TimeoutPolicy: "pass", MaxConsecutiveTimeouts: intPtr(5)},
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// GetRoomPreset 获取指定名称的房间规则预设，名称为空时返回默认预设
+func GetRoomPreset(name string) (models.RoomSettings, bool) {
+	if name == "" {
+		name = DefaultRoomPreset
+	}
+	preset, exists := roomPresets[strings.ToLower(name)]
+	return preset, exists
+}
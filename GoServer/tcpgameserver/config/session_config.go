@@ -0,0 +1,23 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// MinSessionSecretLength 会话令牌签名密钥的最小长度
+const MinSessionSecretLength = 32
+
+// GetSessionSecret 获取会话令牌的签名密钥（环境变量 GAME_SESSION_SECRET），未设置时返回空字符串
+func GetSessionSecret() string {
+	return envOrDefault("GAME_SESSION_SECRET", "")
+}
+
+// GetSessionTokenTTL 获取会话令牌的有效期（环境变量 SESSION_TOKEN_TTL_SECONDS，默认24小时）
+func GetSessionTokenTTL() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("SESSION_TOKEN_TTL_SECONDS", "86400"))
+	if err != nil || value <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(value) * time.Second
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// GetMaxSpectators 获取每个房间的观战人数上限（环境变量 MAX_SPECTATORS，默认10）
+func GetMaxSpectators() int {
+	value, err := strconv.Atoi(envOrDefault("MAX_SPECTATORS", "10"))
+	if err != nil || value < 0 {
+		return 10
+	}
+	return value
+}
+
+// GetSpectatorDelay 获取观战画面延迟（环境变量 SPECTATOR_DELAY_SECONDS，默认不延迟）
+// 延迟推送防止观战者向玩家透露信息
+func GetSpectatorDelay() time.Duration {
+	value, err := strconv.Atoi(envOrDefault("SPECTATOR_DELAY_SECONDS", "0"))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return time.Duration(value) * time.Second
+}
@@ -2,12 +2,14 @@ package events
 
 // 预定义的事件类型常量
 const ( // 游戏相关事件
-	EventGameStart       = "game.start"        // 游戏开始
-	EventGameEnd         = "game.end"          // 游戏结束
-	EventGamePause       = "game.pause"        // 游戏暂停
-	EventGameResume      = "game.resume"       // 游戏恢复
-	EventGameReset       = "game.reset"        // 游戏重置
-	EventGameStateUpdate = "game.state_update" // 游戏状态更新
+	EventGameStart       = "game.start"         // 游戏开始
+	EventGameEnd         = "game.end"           // 游戏结束
+	EventGamePause       = "game.pause"         // 游戏暂停
+	EventGameResume      = "game.resume"        // 游戏恢复
+	EventGameReset       = "game.reset"         // 游戏重置
+	EventGameStateUpdate = "game.state_update"  // 游戏状态更新
+	EventDrawOffer       = "game.draw_offer"    // 提议和棋
+	EventDrawResponse    = "game.draw_response" // 回应和棋提议
 
 	// 玩家相关事件
 	EventPlayerJoin   = "player.join"   // 玩家加入
@@ -17,13 +19,15 @@ const ( // 游戏相关事件
 	EventPlayerDeath  = "player.death"  // 玩家死亡
 	EventPlayerRevive = "player.revive" // 玩家复活
 	// 卡牌相关事件
-	EventCardDraw    = "card.draw"    // 抽卡
-	EventCardBonds   = "card.bonds"   // 羁绊
-	EventCardPlay    = "card.play"    // 出牌
-	EventCardDiscard = "card.discard" // 弃牌
-	EventCardShuffle = "card.shuffle" // 洗牌
-	EventCardCompose = "card.compose" // 卡牌合成
-	EventDeckEmpty   = "deck.empty"   // 牌库为空
+	EventCardDraw    = "card.draw"         // 抽卡
+	EventCardBonds   = "card.bonds"        // 羁绊
+	EventCardPlay    = "card.play"         // 出牌
+	EventCardDiscard = "card.discard"      // 弃牌
+	EventCardShuffle = "card.shuffle"      // 洗牌
+	EventCardCompose = "card.compose"      // 卡牌合成
+	EventDeckEmpty   = "deck.empty"        // 牌库为空
+	EventBondPreview = "card.bond_preview" // 预览出牌的羁绊结果
+	EventBondHints   = "card.bond_hints"   // 查询接近完成的羁绊
 
 	// 战斗相关事件
 	EventBattleStart = "battle.start"   // 战斗开始
@@ -34,10 +38,13 @@ const ( // 游戏相关事件
 	EventHeal        = "battle.heal"    // 治疗
 
 	// 房间相关事件
-	EventRoomCreate  = "room.create"  // 房间创建
-	EventRoomDestroy = "room.destroy" // 房间销毁
-	EventRoomFull    = "room.full"    // 房间已满
-	EventRoomEmpty   = "room.empty"   // 房间为空
+	EventRoomCreate     = "room.create"          // 房间创建
+	EventRoomDestroy    = "room.destroy"         // 房间销毁
+	EventRoomFull       = "room.full"            // 房间已满
+	EventRoomEmpty      = "room.empty"           // 房间为空
+	EventRoomUpdate     = "room.update"          // 房间信息更新（玩家进出、设置修改）
+	EventSpectatorJoin  = "room.spectator_join"  // 观战者加入
+	EventSpectatorLeave = "room.spectator_leave" // 观战者离开
 	// 连接相关事件
 	EventClientConnect     = "client.connect"     // 客户端连接
 	EventClientDisconnect  = "client.disconnect"  // 客户端断开
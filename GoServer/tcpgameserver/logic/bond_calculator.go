@@ -3,6 +3,8 @@ package logic
 import (
 	"GoServer/tcpgameserver/cards"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/types"
+	"encoding/binary"
 	"sort"
 )
 
@@ -23,14 +25,27 @@ type TriggeredBond struct {
 
 // BondCalculator 羁绊计算器
 type BondCalculator struct {
-	bondPoolManager *cards.BondPoolManager
+	cardSet *models.CardSet // 使用的卡牌集快照，为空时使用当前卡牌集
 }
 
-// NewBondCalculator 创建新的羁绊计算器
+// NewBondCalculator 创建使用当前卡牌集的羁绊计算器
 func NewBondCalculator() *BondCalculator {
+	return &BondCalculator{}
+}
+
+// NewRoomBondCalculator 创建使用房间开局卡牌集的羁绊计算器
+func NewRoomBondCalculator(room *types.RoomInfo) *BondCalculator {
 	return &BondCalculator{
-		bondPoolManager: cards.GetBondPoolManager(),
+		cardSet: room.GetCardSet(),
+	}
+}
+
+// getCardSet 获取计算使用的卡牌集快照
+func (bc *BondCalculator) getCardSet() *models.CardSet {
+	if bc.cardSet != nil {
+		return bc.cardSet
 	}
+	return cards.CurrentCardSet()
 }
 
 // CalculateBondDamage 计算羁绊伤害
@@ -47,185 +62,230 @@ func (bc *BondCalculator) CalculateBondDamage(cards []models.Card) BondCalculati
 	}
 
 	// 获取所有可用的羁绊
-	allBonds := bc.bondPoolManager.GetAllBonds()
-
-	// 找出所有可能的羁绊组合
-	possibleBonds := bc.findPossibleBonds(cards, allBonds)
-
-	// 如果没有可能的羁绊，直接计算基础伤害
-	if len(possibleBonds) == 0 {
-		totalDamage := bc.calculateBaseDamage(cards)
-		return BondCalculationResult{
-			TotalDamage:    totalDamage,
-			TriggeredBonds: []TriggeredBond{},
-			UnusedCards:    cards,
-			UsedCards:      []models.Card{},
-		}
-	}
+	allBonds := bc.getCardSet().GetAllBonds()
 
-	// 计算最优羁绊组合
-	bestCombination := bc.findOptimalBondCombination(cards, possibleBonds)
-
-	return bestCombination
+	// 精确求解总伤害最高的羁绊组合（不触发任何羁绊也是候选方案）
+	return bc.findOptimalBondCombination(cards, allBonds)
 }
 
-// findPossibleBonds 找出所有可能触发的羁绊
-func (bc *BondCalculator) findPossibleBonds(cards []models.Card, allBonds map[int]*models.BondModel) []PossibleBond {
-	var possibleBonds []PossibleBond
+// findOptimalBondCombination 找出总伤害最高的羁绊组合
+// 同名卡牌可以互换，因此按卡牌名称计数对"剩余卡牌 + 当前考虑的羁绊"做记忆化搜索：
+// 每个羁绊可以触发多次，也可以跳过，保证在卡牌被多个羁绊争用时仍能得到最优解
+func (bc *BondCalculator) findOptimalBondCombination(cards []models.Card, allBonds map[int]*models.BondModel) BondCalculationResult {
+	solver := newBondSolver(cards, allBonds)
+	chosenBonds := solver.solve()
 
-	// 创建卡牌名称映射，用于快速查找
-	cardNameCount := make(map[string][]models.Card)
-	for _, card := range cards {
-		cardNameCount[card.Name] = append(cardNameCount[card.Name], card)
-	}
+	// 每个羁绊优先使用同名卡牌中伤害最低的卡牌，保留高伤害卡牌作为基础伤害
+	nextCard := make([]int, len(solver.names))
+	usedCardUIDs := make(map[string]bool)
+	triggeredBonds := []TriggeredBond{}
+	allUsedCards := []models.Card{}
+	totalBondDamage := 0.0
 
-	// 检查每个羁绊是否可以触发，包括多次触发
-	for _, bond := range allBonds {
-		// 复制卡牌映射用于多次检查
-		tempCardCount := make(map[string][]models.Card)
-		for name, cards := range cardNameCount {
-			tempCardCount[name] = make([]models.Card, len(cards))
-			copy(tempCardCount[name], cards)
+	for _, bondIndex := range chosenBonds {
+		bond := solver.bonds[bondIndex]
+		usedCards := make([]models.Card, 0, len(bond.CardNames))
+		for _, cardName := range bond.CardNames {
+			nameIndex := solver.nameIndex[cardName]
+			card := solver.cards[nameIndex][nextCard[nameIndex]]
+			nextCard[nameIndex]++
+
+			usedCards = append(usedCards, card)
+			usedCardUIDs[card.UID] = true
 		}
 
-		// 持续检查该羁绊是否可以被多次触发
-		for {
-			usedCards := bc.checkBondRequirement(bond, tempCardCount)
-			if len(usedCards) == 0 {
-				break // 无法再次触发该羁绊
-			}
+		triggeredBonds = append(triggeredBonds, TriggeredBond{
+			Bond:       bond,
+			UsedCards:  usedCards,
+			BondDamage: bond.Damage,
+		})
+		allUsedCards = append(allUsedCards, usedCards...)
+		totalBondDamage += bond.Damage
+	}
 
-			// 添加一次羁绊触发
-			possibleBonds = append(possibleBonds, PossibleBond{
-				Bond:      bond,
-				UsedCards: usedCards,
-				Damage:    bond.Damage,
-			})
-
-			// 从临时卡牌映射中移除已使用的卡牌
-			for _, usedCard := range usedCards {
-				for cardName, availableCards := range tempCardCount {
-					for i, card := range availableCards {
-						if card.UID == usedCard.UID {
-							tempCardCount[cardName] = append(availableCards[:i], availableCards[i+1:]...)
-							break
-						}
-					}
-				}
-			}
+	// 计算未使用卡牌的基础伤害
+	unusedCards := []models.Card{}
+	unusedBaseDamage := 0.0
+	for _, card := range cards {
+		if !usedCardUIDs[card.UID] {
+			unusedCards = append(unusedCards, card)
+			unusedBaseDamage += card.Damage
 		}
 	}
 
-	// 按伤害值降序排序，优先选择高伤害羁绊
-	sort.Slice(possibleBonds, func(i, j int) bool {
-		return possibleBonds[i].Damage > possibleBonds[j].Damage
-	})
-
-	return possibleBonds
+	return BondCalculationResult{
+		TotalDamage:    totalBondDamage + unusedBaseDamage,
+		TriggeredBonds: triggeredBonds,
+		UnusedCards:    unusedCards,
+		UsedCards:      allUsedCards,
+	}
 }
 
-// PossibleBond 可能触发的羁绊
-type PossibleBond struct {
-	Bond      *models.BondModel `json:"bond"`
-	UsedCards []models.Card     `json:"used_cards"`
-	Damage    float64           `json:"damage"`
+// bondSolver 羁绊组合精确求解器
+type bondSolver struct {
+	names     []string            // 出牌中出现的卡牌名称
+	nameIndex map[string]int      // 卡牌名称 -> names中的下标
+	cards     [][]models.Card     // 每种名称的卡牌，按伤害升序排列
+	prefix    [][]float64         // 每种名称卡牌伤害的前缀和，用于计算羁绊占用卡牌损失的基础伤害
+	bonds     []*models.BondModel // 出牌可能触发的羁绊，按ID排序保证结果稳定
+	needs     [][]int             // 每个羁绊对每种名称卡牌的需求数量
+	memo      map[string]bondSolverState
 }
 
-// checkBondRequirement 检查是否满足羁绊要求
-func (bc *BondCalculator) checkBondRequirement(bond *models.BondModel, cardNameCount map[string][]models.Card) []models.Card {
-	var usedCards []models.Card
-	tempCardCount := make(map[string][]models.Card)
+// bondSolverState 搜索状态的最优结果
+type bondSolverState struct {
+	gain float64 // 相比不触发羁绊增加的伤害
+	take bool    // 最优方案是否在该状态触发当前羁绊
+}
 
-	// 复制卡牌映射
-	for name, cards := range cardNameCount {
-		tempCardCount[name] = make([]models.Card, len(cards))
-		copy(tempCardCount[name], cards)
+// newBondSolver 根据出牌和羁绊数据创建求解器，只保留所需卡牌都在出牌中的羁绊
+func newBondSolver(cards []models.Card, allBonds map[int]*models.BondModel) *bondSolver {
+	solver := &bondSolver{
+		nameIndex: make(map[string]int),
+		memo:      make(map[string]bondSolverState),
 	}
 
-	// 检查羁绊所需的每张卡牌
-	for _, requiredCardName := range bond.CardNames {
-		availableCards, exists := tempCardCount[requiredCardName]
-		if !exists || len(availableCards) == 0 {
-			// 缺少必需的卡牌，无法触发羁绊
-			return []models.Card{}
+	for _, card := range cards {
+		index, exists := solver.nameIndex[card.Name]
+		if !exists {
+			index = len(solver.names)
+			solver.nameIndex[card.Name] = index
+			solver.names = append(solver.names, card.Name)
+			solver.cards = append(solver.cards, nil)
 		}
-
-		// 使用一张该名称的卡牌
-		usedCard := availableCards[0]
-		usedCards = append(usedCards, usedCard)
-		tempCardCount[requiredCardName] = availableCards[1:]
+		solver.cards[index] = append(solver.cards[index], card)
 	}
 
-	return usedCards
-}
+	solver.prefix = make([][]float64, len(solver.cards))
+	for i, sameNameCards := range solver.cards {
+		sort.SliceStable(sameNameCards, func(a, b int) bool {
+			return sameNameCards[a].Damage < sameNameCards[b].Damage
+		})
+		solver.prefix[i] = make([]float64, len(sameNameCards)+1)
+		for j, card := range sameNameCards {
+			solver.prefix[i][j+1] = solver.prefix[i][j] + card.Damage
+		}
+	}
 
-// findOptimalBondCombination 找出最优的羁绊组合
-func (bc *BondCalculator) findOptimalBondCombination(cards []models.Card, possibleBonds []PossibleBond) BondCalculationResult {
-	bestResult := BondCalculationResult{
-		TotalDamage:    bc.calculateBaseDamage(cards),
-		TriggeredBonds: []TriggeredBond{},
-		UnusedCards:    cards,
-		UsedCards:      []models.Card{},
+	bondIDs := make([]int, 0, len(allBonds))
+	for id := range allBonds {
+		bondIDs = append(bondIDs, id)
 	}
-	// 使用贪心算法，优先选择高伤害羁绊
-	usedCardUIDs := make(map[string]bool)
-	var triggeredBonds []TriggeredBond
-	var allUsedCards []models.Card
-	totalBondDamage := 0.0
+	sort.Ints(bondIDs)
 
-	for _, possibleBond := range possibleBonds {
-		// 检查此羁绊的卡牌是否已被使用
-		canUse := true
-		for _, card := range possibleBond.UsedCards {
-			if usedCardUIDs[card.UID] {
-				canUse = false
+	for _, id := range bondIDs {
+		bond := allBonds[id]
+		if len(bond.CardNames) == 0 {
+			continue
+		}
+
+		need := make([]int, len(solver.names))
+		available := true
+		for _, cardName := range bond.CardNames {
+			index, exists := solver.nameIndex[cardName]
+			if !exists {
+				available = false
 				break
 			}
+			need[index]++
+		}
+		if !available {
+			continue
 		}
 
-		if canUse {
-			// 标记这些卡牌为已使用
-			for _, card := range possibleBond.UsedCards {
-				usedCardUIDs[card.UID] = true
-				allUsedCards = append(allUsedCards, card)
+		fits := true
+		for i, count := range need {
+			if count > len(solver.cards[i]) {
+				fits = false
+				break
 			}
+		}
+		if fits {
+			solver.bonds = append(solver.bonds, bond)
+			solver.needs = append(solver.needs, need)
+		}
+	}
+
+	return solver
+}
+
+// solve 求解最优组合，返回依次触发的羁绊下标（同一羁绊可出现多次）
+func (s *bondSolver) solve() []int {
+	remaining := make([]int, len(s.cards))
+	for i, sameNameCards := range s.cards {
+		remaining[i] = len(sameNameCards)
+	}
 
-			// 添加触发的羁绊
-			triggeredBonds = append(triggeredBonds, TriggeredBond{
-				Bond:       possibleBond.Bond,
-				UsedCards:  possibleBond.UsedCards,
-				BondDamage: possibleBond.Damage,
-			})
+	s.search(remaining, 0)
 
-			totalBondDamage += possibleBond.Damage
+	// 沿记忆化结果还原最优方案
+	var chosen []int
+	bondIndex := 0
+	for bondIndex < len(s.bonds) {
+		if s.memo[s.stateKey(remaining, bondIndex)].take {
+			chosen = append(chosen, bondIndex)
+			for i, count := range s.needs[bondIndex] {
+				remaining[i] -= count
+			}
+		} else {
+			bondIndex++
 		}
 	}
+	return chosen
+}
 
-	// 计算未使用卡牌的基础伤害
-	var unusedCards []models.Card
-	unusedBaseDamage := 0.0
+// search 计算在剩余卡牌下从第bondIndex个羁绊开始选择能获得的最大伤害增量
+func (s *bondSolver) search(remaining []int, bondIndex int) float64 {
+	if bondIndex >= len(s.bonds) {
+		return 0
+	}
 
-	for _, card := range cards {
-		if !usedCardUIDs[card.UID] {
-			unusedCards = append(unusedCards, card)
-			unusedBaseDamage += card.Damage
+	key := s.stateKey(remaining, bondIndex)
+	if state, exists := s.memo[key]; exists {
+		return state.gain
+	}
+
+	// 跳过当前羁绊
+	best := bondSolverState{gain: s.search(remaining, bondIndex+1)}
+
+	// 触发一次当前羁绊，之后仍可继续触发同一羁绊
+	if next, ok := s.consume(remaining, bondIndex); ok {
+		lostBaseDamage := 0.0
+		for i := range remaining {
+			used := len(s.cards[i]) - remaining[i]
+			lostBaseDamage += s.prefix[i][used+s.needs[bondIndex][i]] - s.prefix[i][used]
+		}
+
+		gain := s.bonds[bondIndex].Damage - lostBaseDamage + s.search(next, bondIndex)
+		if gain > best.gain {
+			best = bondSolverState{gain: gain, take: true}
 		}
 	}
 
-	// 计算总伤害
-	totalDamage := totalBondDamage + unusedBaseDamage
+	s.memo[key] = best
+	return best.gain
+}
 
-	// 如果羁绊组合的总伤害更高，则使用羁绊组合
-	if totalDamage > bestResult.TotalDamage {
-		bestResult = BondCalculationResult{
-			TotalDamage:    totalDamage,
-			TriggeredBonds: triggeredBonds,
-			UnusedCards:    unusedCards,
-			UsedCards:      allUsedCards,
+// consume 从剩余卡牌中扣除羁绊所需的卡牌，卡牌不足时返回false
+func (s *bondSolver) consume(remaining []int, bondIndex int) ([]int, bool) {
+	next := make([]int, len(remaining))
+	for i, count := range s.needs[bondIndex] {
+		if remaining[i] < count {
+			return nil, false
 		}
+		next[i] = remaining[i] - count
 	}
+	return next, true
+}
 
-	return bestResult
+// stateKey 生成搜索状态的记忆化键
+func (s *bondSolver) stateKey(remaining []int, bondIndex int) string {
+	key := make([]byte, 0, len(remaining)+1)
+	for _, count := range remaining {
+		key = append(key, byte(count))
+	}
+	key = binary.AppendUvarint(key, uint64(bondIndex))
+	return string(key)
 }
 
 // calculateBaseDamage 计算基础伤害（所有卡牌伤害之和）
@@ -248,7 +308,7 @@ func (bc *BondCalculator) GetBondsByCards(cards []models.Card) []*models.BondMod
 	}
 
 	// 找出包含这些卡牌的羁绊
-	allBonds := bc.bondPoolManager.GetAllBonds()
+	allBonds := bc.getCardSet().GetAllBonds()
 	for _, bond := range allBonds {
 		hasRelatedCard := false
 		for _, cardName := range bond.CardNames {
@@ -267,7 +327,7 @@ func (bc *BondCalculator) GetBondsByCards(cards []models.Card) []*models.BondMod
 
 // ValidateBondRequirements 验证羁绊要求（调试用）
 func (bc *BondCalculator) ValidateBondRequirements(cards []models.Card, bondID int) (bool, []string) {
-	bond, exists := bc.bondPoolManager.GetBondByID(bondID)
+	bond, exists := bc.getCardSet().GetBondByID(bondID)
 	if !exists {
 		return false, []string{"羁绊不存在"}
 	}
@@ -0,0 +1,254 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"GoServer/tcpgameserver/models"
+)
+
+// testCards 按名称和伤害创建卡牌，names与damages一一对应
+func testCards(names []string, damages map[string]float64) []models.Card {
+	cardList := make([]models.Card, 0, len(names))
+	for i, name := range names {
+		cardList = append(cardList, models.NewCard(i+1, name, damages[name], nil, 1))
+	}
+	return cardList
+}
+
+// testBonds 创建以ID为键的羁绊集合
+func testBonds(bonds ...models.BondModel) map[int]*models.BondModel {
+	result := make(map[int]*models.BondModel, len(bonds))
+	for i := range bonds {
+		bond := bonds[i]
+		result[bond.ID] = &bond
+	}
+	return result
+}
+
+// greedyBondDamage 原贪心算法的参考实现：每个羁绊在完整出牌上按名称取第一张卡牌尽量多次触发，
+// 按羁绊伤害降序选择卡牌互不重叠的触发，未参与羁绊的卡牌计算基础伤害，结果不低于全部卡牌的基础伤害
+func greedyBondDamage(cardList []models.Card, bonds map[int]*models.BondModel) float64 {
+	type possibleBond struct {
+		damage float64
+		cards  []models.Card
+	}
+
+	byName := make(map[string][]models.Card)
+	for _, card := range cardList {
+		byName[card.Name] = append(byName[card.Name], card)
+	}
+
+	bondIDs := make([]int, 0, len(bonds))
+	for id := range bonds {
+		bondIDs = append(bondIDs, id)
+	}
+	sort.Ints(bondIDs)
+
+	var possible []possibleBond
+	for _, id := range bondIDs {
+		bond := bonds[id]
+		remaining := make(map[string][]models.Card, len(byName))
+		for name, sameName := range byName {
+			remaining[name] = sameName
+		}
+		for {
+			var used []models.Card
+			taken := make(map[string]int)
+			for _, name := range bond.CardNames {
+				if taken[name] >= len(remaining[name]) {
+					used = nil
+					break
+				}
+				used = append(used, remaining[name][taken[name]])
+				taken[name]++
+			}
+			if len(used) == 0 {
+				break
+			}
+			possible = append(possible, possibleBond{damage: bond.Damage, cards: used})
+			for name, count := range taken {
+				remaining[name] = remaining[name][count:]
+			}
+		}
+	}
+	sort.SliceStable(possible, func(i, j int) bool { return possible[i].damage > possible[j].damage })
+
+	usedUIDs := make(map[string]bool)
+	total := 0.0
+	for _, bond := range possible {
+		overlaps := false
+		for _, card := range bond.cards {
+			if usedUIDs[card.UID] {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		for _, card := range bond.cards {
+			usedUIDs[card.UID] = true
+		}
+		total += bond.damage
+	}
+	baseDamage := 0.0
+	for _, card := range cardList {
+		baseDamage += card.Damage
+		if !usedUIDs[card.UID] {
+			total += card.Damage
+		}
+	}
+	if baseDamage > total {
+		return baseDamage
+	}
+	return total
+}
+
+func triggeredBondNames(result BondCalculationResult) []string {
+	names := make([]string, 0, len(result.TriggeredBonds))
+	for _, triggered := range result.TriggeredBonds {
+		names = append(names, triggered.Bond.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFindOptimalBondCombination(t *testing.T) {
+	unit := map[string]float64{"A": 1, "B": 1, "C": 1, "D": 1}
+
+	tests := []struct {
+		name        string
+		cards       []string
+		damages     map[string]float64
+		bonds       map[int]*models.BondModel
+		wantDamage  float64
+		wantBonds   []string
+		greedyTotal float64
+	}{
+		{
+			// 贪心先选伤害最高的羁绊，占用了两个小羁绊共用的卡牌
+			name:    "two smaller bonds beat one overlapping big bond",
+			cards:   []string{"A", "B", "C", "D"},
+			damages: unit,
+			bonds: testBonds(
+				models.BondModel{ID: 1, Name: "Big", CardNames: []string{"A", "B"}, Damage: 10},
+				models.BondModel{ID: 2, Name: "LeftPair", CardNames: []string{"A", "C"}, Damage: 8},
+				models.BondModel{ID: 3, Name: "RightPair", CardNames: []string{"B", "D"}, Damage: 8},
+			),
+			wantDamage:  16,
+			wantBonds:   []string{"LeftPair", "RightPair"},
+			greedyTotal: 12,
+		},
+		{
+			// 贪心只比较羁绊伤害，不考虑羁绊占用的高伤害卡牌损失的基础伤害
+			name:    "big bond consumes a high base damage card",
+			cards:   []string{"A", "B", "C"},
+			damages: map[string]float64{"A": 1, "B": 1, "C": 6},
+			bonds: testBonds(
+				models.BondModel{ID: 1, Name: "Big", CardNames: []string{"B", "C"}, Damage: 10},
+				models.BondModel{ID: 2, Name: "Pair", CardNames: []string{"A", "B"}, Damage: 9},
+			),
+			wantDamage:  15,
+			wantBonds:   []string{"Pair"},
+			greedyTotal: 11,
+		},
+		{
+			// 两个羁绊都需要A，贪心各自取第一张A导致冲突，实际有两张A可以同时触发
+			name:    "duplicate card names shared between bonds",
+			cards:   []string{"A", "A", "B", "C"},
+			damages: unit,
+			bonds: testBonds(
+				models.BondModel{ID: 1, Name: "AB", CardNames: []string{"A", "B"}, Damage: 10},
+				models.BondModel{ID: 2, Name: "AC", CardNames: []string{"A", "C"}, Damage: 9},
+			),
+			wantDamage:  19,
+			wantBonds:   []string{"AB", "AC"},
+			greedyTotal: 12,
+		},
+		{
+			// 三个羁绊两两共用卡牌，只能触发其中互不重叠的组合
+			name:    "chain of overlapping bonds",
+			cards:   []string{"A", "B", "C", "D"},
+			damages: unit,
+			bonds: testBonds(
+				models.BondModel{ID: 1, Name: "AB", CardNames: []string{"A", "B"}, Damage: 7},
+				models.BondModel{ID: 2, Name: "BC", CardNames: []string{"B", "C"}, Damage: 9},
+				models.BondModel{ID: 3, Name: "CD", CardNames: []string{"C", "D"}, Damage: 7},
+			),
+			wantDamage:  14,
+			wantBonds:   []string{"AB", "CD"},
+			greedyTotal: 11,
+		},
+		{
+			// 没有争用时与贪心结果一致，同一羁绊可以多次触发
+			name:    "repeated bond without contention",
+			cards:   []string{"A", "B", "A", "B", "C"},
+			damages: unit,
+			bonds: testBonds(
+				models.BondModel{ID: 1, Name: "AB", CardNames: []string{"A", "B"}, Damage: 6},
+			),
+			wantDamage:  13,
+			wantBonds:   []string{"AB", "AB"},
+			greedyTotal: 13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cardList := testCards(tt.cards, tt.damages)
+
+			if got := greedyBondDamage(cardList, tt.bonds); got != tt.greedyTotal {
+				t.Fatalf("greedy damage = %.1f, want %.1f", got, tt.greedyTotal)
+			}
+
+			result := NewBondCalculator().findOptimalBondCombination(cardList, tt.bonds)
+			if result.TotalDamage != tt.wantDamage {
+				t.Errorf("total damage = %.1f, want %.1f", result.TotalDamage, tt.wantDamage)
+			}
+			if result.TotalDamage < tt.greedyTotal {
+				t.Errorf("exact damage %.1f is lower than greedy %.1f", result.TotalDamage, tt.greedyTotal)
+			}
+			if got := triggeredBondNames(result); fmt.Sprint(got) != fmt.Sprint(tt.wantBonds) {
+				t.Errorf("triggered bonds = %v, want %v", got, tt.wantBonds)
+			}
+			if len(result.UsedCards)+len(result.UnusedCards) != len(cardList) {
+				t.Errorf("used %d + unused %d cards, want %d", len(result.UsedCards), len(result.UnusedCards), len(cardList))
+			}
+		})
+	}
+}
+
+// BenchmarkFindOptimalBondCombination 10张手牌、5种卡牌名称、互相争用卡牌的羁绊
+// 所有名称组合都是羁绊属于极端情况，单次约2ms；出牌、出牌预览和困难机器人的整手牌计算每次只求解一次，
+// bestPlay 只枚举最多3张卡牌的组合，每个组合的求解开销很小
+func BenchmarkFindOptimalBondCombination(b *testing.B) {
+	names := []string{"A", "B", "C", "D", "E"}
+	damages := map[string]float64{"A": 2, "B": 3, "C": 4, "D": 5, "E": 6}
+	cardList := testCards([]string{"A", "A", "B", "B", "C", "C", "D", "D", "E", "E"}, damages)
+
+	// 所有两张和三张卡牌的名称组合都是羁绊
+	var bondList []models.BondModel
+	id := 1
+	for i := range names {
+		for j := i; j < len(names); j++ {
+			bondList = append(bondList, models.BondModel{ID: id, Name: fmt.Sprintf("Bond%d", id),
+				CardNames: []string{names[i], names[j]}, Damage: float64(8 + id%5)})
+			id++
+			for k := j; k < len(names); k++ {
+				bondList = append(bondList, models.BondModel{ID: id, Name: fmt.Sprintf("Bond%d", id),
+					CardNames: []string{names[i], names[j], names[k]}, Damage: float64(14 + id%7)})
+				id++
+			}
+		}
+	}
+	bonds := testBonds(bondList...)
+	calculator := NewBondCalculator()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calculator.findOptimalBondCombination(cardList, bonds)
+	}
+}
@@ -0,0 +1,154 @@
+package logic
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// BondPreviewProcessor 羁绊预览处理器，计算出牌预览和羁绊提示，不改变游戏状态
+type BondPreviewProcessor struct {
+	Name string
+}
+
+// NewBondPreviewProcessor 创建新的羁绊预览处理器
+func NewBondPreviewProcessor() *BondPreviewProcessor {
+	return &BondPreviewProcessor{
+		Name: "BondPreviewProcessor",
+	}
+}
+
+// 全局羁绊预览处理器实例
+var GlobalBondPreviewProcessor = NewBondPreviewProcessor()
+
+// ProcessPreviewPlay 计算玩家选择的手牌出牌时的羁绊结果 (消息码1701)，与实际出牌使用相同的计算
+func (bp *BondPreviewProcessor) ProcessPreviewPlay(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	clientID, _ := eventData.GetString("client_id")
+	player, _ := eventData.GetString("player")
+	cardsData, _ := eventData.GetData("cards")
+	selectedCards, _ := cardsData.([]models.Card)
+
+	room, handCards, err := bp.getRoomHand(eventData.RoomID, player)
+	if err != nil {
+		bp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1703))
+		return
+	}
+
+	validatedCards, err := bp.validateSelection(handCards, selectedCards)
+	if err != nil {
+		bp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1704))
+		return
+	}
+
+	result := NewRoomBondCalculator(room).CalculateBondDamage(validatedCards)
+	bp.reply(clientID, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1701, result))
+}
+
+// ProcessBondHints 列出与玩家手牌相关、最接近完成的羁绊及缺少的卡牌 (消息码1702)
+// 按缺少的卡牌数量升序、羁绊伤害降序排列
+func (bp *BondPreviewProcessor) ProcessBondHints(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	clientID, _ := eventData.GetString("client_id")
+	player, _ := eventData.GetString("player")
+	limit, exists := eventData.GetInt("limit")
+	if !exists || limit <= 0 {
+		limit = models.DefaultBondHintLimit
+	}
+
+	room, handCards, err := bp.getRoomHand(eventData.RoomID, player)
+	if err != nil {
+		bp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1703))
+		return
+	}
+
+	bondCalculator := NewRoomBondCalculator(room)
+	hints := make([]models.BondHint, 0)
+	for _, bond := range bondCalculator.GetBondsByCards(handCards) {
+		complete, missingCards := bondCalculator.ValidateBondRequirements(handCards, bond.ID)
+		if missingCards == nil {
+			missingCards = []string{}
+		}
+		hints = append(hints, models.BondHint{
+			Bond:         *bond,
+			MissingCards: missingCards,
+			Complete:     complete,
+		})
+	}
+
+	sort.Slice(hints, func(i, j int) bool {
+		if len(hints[i].MissingCards) != len(hints[j].MissingCards) {
+			return len(hints[i].MissingCards) < len(hints[j].MissingCards)
+		}
+		if hints[i].Bond.Damage != hints[j].Bond.Damage {
+			return hints[i].Bond.Damage > hints[j].Bond.Damage
+		}
+		return hints[i].Bond.ID < hints[j].Bond.ID
+	})
+	if len(hints) > limit {
+		hints = hints[:limit]
+	}
+
+	bp.reply(clientID, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1702, hints))
+}
+
+// getRoomHand 获取进行中的房间及房间内玩家的手牌副本
+func (bp *BondPreviewProcessor) getRoomHand(roomID, player string) (*types.RoomInfo, []models.Card, error) {
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if room.Status != "playing" {
+		return nil, nil, fmt.Errorf("room %s is not in playing state: %s", roomID, room.Status)
+	}
+	handCards, err := room.GetPlayerHandCards(player)
+	return room, handCards, err
+}
+
+// validateSelection 验证选择的卡牌都在手牌中且没有重复，返回手牌中的卡牌数据
+func (bp *BondPreviewProcessor) validateSelection(handCards, selectedCards []models.Card) ([]models.Card, error) {
+	if len(selectedCards) == 0 {
+		return nil, fmt.Errorf("no cards selected")
+	}
+
+	handCardMap := make(map[string]models.Card, len(handCards))
+	for _, card := range handCards {
+		handCardMap[card.UID] = card
+	}
+
+	validatedCards := make([]models.Card, 0, len(selectedCards))
+	for _, card := range selectedCards {
+		handCard, exists := handCardMap[card.UID]
+		if !exists {
+			return nil, fmt.Errorf("card UID %s not found in hand", card.UID)
+		}
+		validatedCards = append(validatedCards, handCard)
+		delete(handCardMap, card.UID)
+	}
+
+	return validatedCards, nil
+}
+
+// reply 向请求的客户端发送消息
+func (bp *BondPreviewProcessor) reply(clientID string, response *models.TcpResponse) {
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByClientID(clientID)
+	if !exists || clientInfo.Conn == nil {
+		return
+	}
+	if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+		log.Printf("Failed to send bond preview message %s to client %s: %v", response.Code, clientID, err)
+	}
+}
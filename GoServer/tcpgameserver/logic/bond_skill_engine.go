@@ -0,0 +1,277 @@
+package logic
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/types"
+)
+
+// 羁绊技能效果名称
+const (
+	SkillEffectHeal      = "heal"       // 治疗出牌方，heal:数值
+	SkillEffectShield    = "shield"     // 为出牌方增加护盾，shield:数值[:回合数]
+	SkillEffectDraw      = "draw"       // 出牌方额外抽牌，draw:数量
+	SkillEffectDiscard   = "discard"    // 目标随机弃牌，discard:数量
+	SkillEffectDot       = "dot"        // 目标中毒，回合开始时受到伤害，dot:每回合伤害[:回合数]
+	SkillEffectPoison    = "poison"     // 同dot
+	SkillEffectStun      = "stun"       // 目标眩晕，跳过回合，stun[:数值[:回合数]]
+	SkillEffectExtraTurn = "extra_turn" // 出牌方获得额外回合，extra_turn
+)
+
+// defaultSkillTurns 状态类技能未指定回合数时的默认持续回合
+var defaultSkillTurns = map[string]int{
+	SkillEffectShield: 2,
+	SkillEffectDot:    2,
+	SkillEffectPoison: 2,
+	SkillEffectStun:   1,
+}
+
+// SkillEffect 从羁绊技能字符串解析出的单个效果
+type SkillEffect struct {
+	Name  string  // 效果名称
+	Value float64 // 效果数值
+	Turns int     // 持续回合数
+}
+
+// SkillContext 技能效果执行上下文
+type SkillContext struct {
+	Processor *PlayCardProcessor
+	Room      *types.RoomInfo
+	Bond      *models.BondModel
+	Player    string   // 出牌方
+	Targets   []string // 出牌影响的存活对手
+	ExtraTurn bool     // 出牌方是否获得额外回合
+}
+
+// SkillEffectHandler 技能效果执行函数，返回产生的效果记录
+type SkillEffectHandler func(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error)
+
+// skillEffectRegistry 技能效果注册表，key为效果名称
+var skillEffectRegistry = map[string]SkillEffectHandler{
+	SkillEffectHeal:      applyHealEffect,
+	SkillEffectShield:    applyShieldEffect,
+	SkillEffectDraw:      applyDrawEffect,
+	SkillEffectDiscard:   applyDiscardEffect,
+	SkillEffectDot:       applyPoisonEffect,
+	SkillEffectPoison:    applyPoisonEffect,
+	SkillEffectStun:      applyStunEffect,
+	SkillEffectExtraTurn: applyExtraTurnEffect,
+}
+
+// RegisterSkillEffect 注册技能效果，同名效果会被覆盖
+func RegisterSkillEffect(name string, handler SkillEffectHandler) {
+	skillEffectRegistry[strings.ToLower(name)] = handler
+}
+
+// ParseBondSkill 解析羁绊技能字符串，多个效果以分号或逗号分隔，未指定数值时为1
+// 例如 "heal:5;shield:8"、"dot:3:2,extra_turn"，空字符串表示没有技能
+func ParseBondSkill(skill string) ([]SkillEffect, error) {
+	var effects []SkillEffect
+
+	parts := strings.FieldsFunc(skill, func(r rune) bool {
+		return r == ';' || r == ','
+	})
+	for _, part := range parts {
+		fields := strings.Split(strings.TrimSpace(part), ":")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name == "" {
+			continue
+		}
+		if _, exists := skillEffectRegistry[name]; !exists {
+			return nil, fmt.Errorf("unknown skill effect %s", name)
+		}
+		if len(fields) > 3 {
+			return nil, fmt.Errorf("too many parameters for skill effect %s", name)
+		}
+
+		effect := SkillEffect{Name: name, Value: 1}
+		if len(fields) > 1 {
+			value, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+			if err != nil || value < 0 {
+				return nil, fmt.Errorf("invalid value %q for skill effect %s", fields[1], name)
+			}
+			effect.Value = value
+		}
+		if len(fields) > 2 {
+			turns, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+			if err != nil || turns <= 0 {
+				return nil, fmt.Errorf("invalid turns %q for skill effect %s", fields[2], name)
+			}
+			effect.Turns = turns
+		} else {
+			effect.Turns = defaultSkillTurns[name]
+		}
+		effects = append(effects, effect)
+	}
+
+	return effects, nil
+}
+
+// applyBondSkills 依次执行触发羁绊的技能效果，技能格式错误的羁绊只记录日志并跳过
+// 返回产生的效果记录以及出牌方是否获得额外回合
+func (p *PlayCardProcessor) applyBondSkills(room *types.RoomInfo, playerName string, targets []string, bondResult *BondCalculationResult) ([]models.SkillEffectInfo, bool) {
+	var effectInfos []models.SkillEffectInfo
+	extraTurn := false
+
+	for _, triggeredBond := range bondResult.TriggeredBonds {
+		if triggeredBond.Bond == nil || triggeredBond.Bond.Skill == "" {
+			continue
+		}
+
+		effects, err := ParseBondSkill(triggeredBond.Bond.Skill)
+		if err != nil {
+			log.Printf("Skipping skill of bond %s: %v", triggeredBond.Bond.Name, err)
+			continue
+		}
+
+		ctx := &SkillContext{
+			Processor: p,
+			Room:      room,
+			Bond:      triggeredBond.Bond,
+			Player:    playerName,
+			Targets:   targets,
+		}
+		for _, effect := range effects {
+			infos, err := skillEffectRegistry[effect.Name](ctx, effect)
+			if err != nil {
+				log.Printf("Failed to apply skill effect %s of bond %s: %v", effect.Name, triggeredBond.Bond.Name, err)
+				continue
+			}
+			effectInfos = append(effectInfos, infos...)
+		}
+		extraTurn = extraTurn || ctx.ExtraTurn
+	}
+
+	return effectInfos, extraTurn
+}
+
+// skillTargets 获取技能效果影响的存活对手：单体出牌为目标玩家，AOE为所有存活对手，治疗出牌为下一名存活玩家
+func (p *PlayCardProcessor) skillTargets(room *types.RoomInfo, playerName, targetType, targetPlayer string) []string {
+	var targets []string
+	switch targetType {
+	case "all":
+		for _, username := range room.GetAlivePlayers() {
+			if username != playerName {
+				targets = append(targets, username)
+			}
+		}
+	case "self":
+		if nextPlayer, exists := room.GetNextAlivePlayer(playerName); exists {
+			targets = append(targets, nextPlayer)
+		}
+	default:
+		if targetPlayer != "" && room.IsPlayerAlive(targetPlayer) {
+			targets = append(targets, targetPlayer)
+		}
+	}
+	return targets
+}
+
+// newSkillEffectInfo 创建技能效果记录
+func newSkillEffectInfo(ctx *SkillContext, effect SkillEffect, target string, value float64) models.SkillEffectInfo {
+	return models.SkillEffectInfo{
+		Bond:   ctx.Bond.Name,
+		Effect: effect.Name,
+		Source: ctx.Player,
+		Target: target,
+		Value:  value,
+		Turns:  effect.Turns,
+	}
+}
+
+// applyHealEffect 治疗出牌方，不超过最大血量
+func applyHealEffect(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error) {
+	before, err := ctx.Room.GetPlayerCurrentHealth(ctx.Player)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Processor.applySelfHeal(ctx.Room, ctx.Player, effect.Value); err != nil {
+		return nil, err
+	}
+	after, _ := ctx.Room.GetPlayerCurrentHealth(ctx.Player)
+
+	return []models.SkillEffectInfo{newSkillEffectInfo(ctx, effect, ctx.Player, after-before)}, nil
+}
+
+// applyShieldEffect 为出牌方附加护盾状态
+func applyShieldEffect(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error) {
+	if err := ctx.Room.AddStatusEffect(ctx.Player, newStatusEffect(ctx, effect, models.StatusEffectShield)); err != nil {
+		return nil, err
+	}
+	return []models.SkillEffectInfo{newSkillEffectInfo(ctx, effect, ctx.Player, effect.Value)}, nil
+}
+
+// applyDrawEffect 出牌方从1级卡牌池额外抽牌，受手牌上限限制
+func applyDrawEffect(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error) {
+	if int(effect.Value) <= 0 {
+		return nil, nil
+	}
+
+	handBefore, err := ctx.Room.GetPlayerHandCards(ctx.Player)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Processor.drawCardsForPlayer(ctx.Room, ctx.Player, int(effect.Value)); err != nil {
+		return nil, err
+	}
+	handAfter, _ := ctx.Room.GetPlayerHandCards(ctx.Player)
+
+	drawn := float64(len(handAfter) - len(handBefore))
+	return []models.SkillEffectInfo{newSkillEffectInfo(ctx, effect, ctx.Player, drawn)}, nil
+}
+
+// applyDiscardEffect 每名目标随机弃置指定数量的手牌
+func applyDiscardEffect(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error) {
+	infos := make([]models.SkillEffectInfo, 0, len(ctx.Targets))
+	for _, target := range ctx.Targets {
+		discarded, err := ctx.Room.DiscardRandomCards(target, int(effect.Value))
+		if err != nil {
+			return infos, err
+		}
+		infos = append(infos, newSkillEffectInfo(ctx, effect, target, float64(len(discarded))))
+	}
+	return infos, nil
+}
+
+// applyPoisonEffect 为每名目标附加中毒状态，在目标回合开始时结算伤害
+func applyPoisonEffect(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error) {
+	return applyTargetStatus(ctx, effect, models.StatusEffectPoison)
+}
+
+// applyStunEffect 为每名目标附加眩晕状态，眩晕期间跳过回合
+func applyStunEffect(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error) {
+	return applyTargetStatus(ctx, effect, models.StatusEffectStun)
+}
+
+// applyTargetStatus 为每名目标附加指定类型的状态效果
+func applyTargetStatus(ctx *SkillContext, effect SkillEffect, statusType string) ([]models.SkillEffectInfo, error) {
+	infos := make([]models.SkillEffectInfo, 0, len(ctx.Targets))
+	for _, target := range ctx.Targets {
+		if err := ctx.Room.AddStatusEffect(target, newStatusEffect(ctx, effect, statusType)); err != nil {
+			return infos, err
+		}
+		infos = append(infos, newSkillEffectInfo(ctx, effect, target, effect.Value))
+	}
+	return infos, nil
+}
+
+// newStatusEffect 根据技能效果创建状态效果
+func newStatusEffect(ctx *SkillContext, effect SkillEffect, statusType string) models.StatusEffect {
+	return models.StatusEffect{
+		Type:      statusType,
+		Source:    ctx.Player,
+		Bond:      ctx.Bond.Name,
+		Value:     effect.Value,
+		TurnsLeft: effect.Turns,
+	}
+}
+
+// applyExtraTurnEffect 出牌方本回合结束后继续行动
+func applyExtraTurnEffect(ctx *SkillContext, effect SkillEffect) ([]models.SkillEffectInfo, error) {
+	ctx.ExtraTurn = true
+	return []models.SkillEffectInfo{newSkillEffectInfo(ctx, effect, ctx.Player, 0)}, nil
+}
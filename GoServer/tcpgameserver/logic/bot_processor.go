@@ -0,0 +1,416 @@
+package logic
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/types"
+)
+
+// 机器人行动配置
+const (
+	botCheckInterval = time.Second // 没有收到消息时检查是否轮到机器人行动的间隔
+	botMaxPlayCards  = 3           // 普通和困难机器人不能斩杀时每回合最多打出的卡牌数量（与每回合抽牌数量相同）
+)
+
+// BotProcessor 机器人处理器：创建没有真实连接的机器人玩家，用于人机练习和匹配队列补位
+// 机器人与真实玩家一样注册在连接管理器中，轮到自己时通过合成和出牌处理器行动，对局结束后自动移除
+type BotProcessor struct {
+	Name    string
+	bots    map[string]*botPlayer // 客户端ID -> 机器人
+	counter int                   // 已创建的机器人数量，用于生成客户端ID和用户名
+	mutex   sync.Mutex
+}
+
+// botPlayer 机器人玩家
+type botPlayer struct {
+	clientID string
+	username string
+	level    string
+	conn     *botConn
+	rng      *rand.Rand // 机器人自身的决策随机源，不影响房间的随机序列
+}
+
+// NewBotProcessor 创建新的机器人处理器
+func NewBotProcessor() *BotProcessor {
+	return &BotProcessor{
+		Name: "BotProcessor",
+		bots: make(map[string]*botPlayer),
+	}
+}
+
+// 全局机器人处理器实例
+var GlobalBotProcessor = NewBotProcessor()
+
+// StartPractice 为空闲玩家开始人机练习，房间内其余座位均为指定难度的机器人，seed不为0时使用固定随机种子开局
+func (bp *BotProcessor) StartPractice(clientID, level string, roomSize int, seed int64) (*models.PracticeInfo, error) {
+	connManager := service.GetConnectionManager()
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		return nil, fmt.Errorf("client %s is not logged in", clientID)
+	}
+	if status := clientInfo.GetStatus(); status != types.StatusLoggedIn {
+		return nil, fmt.Errorf("player %s cannot start practice while %s", clientInfo.Username, status)
+	}
+
+	room, bots, err := bp.StartBotMatch([]*types.ClientInfo{clientInfo}, level, roomSize, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PracticeInfo{
+		RoomID: room.RoomID,
+		Level:  level,
+		Bots:   bots,
+	}, nil
+}
+
+// StartBotMatch 创建机器人补足房间人数后开始游戏，真实玩家按顺序先行动；开始失败时移除创建的机器人
+// seed不为0时使用固定随机种子开局
+func (bp *BotProcessor) StartBotMatch(players []*types.ClientInfo, level string, roomSize int, seed int64) (*types.RoomInfo, []string, error) {
+	if !models.ValidBotLevel(level) {
+		return nil, nil, fmt.Errorf("invalid bot level: %s", level)
+	}
+	if roomSize < models.MinRoomSize || roomSize > models.MaxRoomSize {
+		return nil, nil, fmt.Errorf("invalid room size: %d", roomSize)
+	}
+	if len(players) == 0 || len(players) >= roomSize {
+		return nil, nil, fmt.Errorf("%d players cannot be filled with bots to room size %d", len(players), roomSize)
+	}
+
+	bots, err := bp.spawnBots(level, roomSize-len(players))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connManager := service.GetConnectionManager()
+	selectedPlayers := append([]*types.ClientInfo(nil), players...)
+	usernames := make([]string, 0, len(bots))
+	for _, bot := range bots {
+		clientInfo, _ := connManager.GetConnectionByClientID(bot.clientID)
+		selectedPlayers = append(selectedPlayers, clientInfo)
+		usernames = append(usernames, bot.username)
+	}
+
+	var room *types.RoomInfo
+	if seed != 0 {
+		room, err = NewGameStartProcessor().StartMatchWithSeed(selectedPlayers, seed)
+	} else {
+		room, err = NewGameStartProcessor().startMatch(selectedPlayers, nil)
+	}
+	if err != nil {
+		for _, bot := range bots {
+			bp.release(bot)
+		}
+		return nil, nil, err
+	}
+
+	// 游戏开始通知已唤醒机器人，先行动的机器人会立即开始思考
+	for _, bot := range bots {
+		go bp.run(bot)
+	}
+	return room, usernames, nil
+}
+
+// spawnBots 创建指定数量的机器人并登录为准备就绪状态，超过机器人数量上限时不创建
+func (bp *BotProcessor) spawnBots(level string, count int) ([]*botPlayer, error) {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+
+	if limit := config.GetMaxActiveBots(); len(bp.bots)+count > limit {
+		return nil, fmt.Errorf("active bot limit %d reached", limit)
+	}
+
+	connManager := service.GetConnectionManager()
+	bots := make([]*botPlayer, 0, count)
+	for i := 0; i < count; i++ {
+		bp.counter++
+		bot := &botPlayer{
+			clientID: fmt.Sprintf("bot_%d", bp.counter),
+			username: fmt.Sprintf("%s%s%s_%d", models.BotUsernamePrefix, strings.ToUpper(level[:1]), level[1:], bp.counter),
+			level:    level,
+			rng:      rand.New(rand.NewSource(time.Now().UnixNano() + int64(bp.counter))),
+		}
+		bot.conn = newBotConn(bot.clientID)
+
+		clientInfo := connManager.AddConnection(bot.conn, bot.clientID)
+		if err := connManager.BindUser(bot.clientID, bot.username); err != nil {
+			connManager.RemoveConnection(bot.clientID)
+			for _, created := range bots {
+				delete(bp.bots, created.clientID)
+				connManager.RemoveConnection(created.clientID)
+			}
+			return nil, fmt.Errorf("failed to log in bot %s: %v", bot.username, err)
+		}
+		clientInfo.SetMetadata("bot_level", level)
+		connManager.SetPlayerStatus(bot.clientID, types.StatusReady)
+
+		bp.bots[bot.clientID] = bot
+		bots = append(bots, bot)
+	}
+
+	return bots, nil
+}
+
+// release 移除机器人及其连接
+func (bp *BotProcessor) release(bot *botPlayer) {
+	bp.mutex.Lock()
+	delete(bp.bots, bot.clientID)
+	bp.mutex.Unlock()
+
+	service.GetConnectionManager().RemoveConnection(bot.clientID)
+}
+
+// run 机器人主循环：收到消息或定时检查时，轮到自己则行动，对局结束后移除机器人
+func (bp *BotProcessor) run(bot *botPlayer) {
+	ticker := time.NewTicker(botCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bot.conn.done:
+			return
+		case <-bot.conn.notify:
+		case <-ticker.C:
+		}
+
+		if !bot.step() {
+			bp.release(bot)
+			return
+		}
+	}
+}
+
+// step 检查机器人所在的对局，轮到自己时思考后行动；返回false表示对局已结束
+func (b *botPlayer) step() bool {
+	connManager := service.GetConnectionManager()
+	clientInfo, exists := connManager.GetConnectionByClientID(b.clientID)
+	if !exists {
+		return false
+	}
+	connManager.UpdateActivity(b.clientID)
+
+	switch clientInfo.GetStatus() {
+	case types.StatusReady:
+		return true // 等待游戏开始
+	case types.StatusInGame:
+	default:
+		return false
+	}
+
+	room, err := service.GetRoomManager().GetRoom(clientInfo.GetGameRoom())
+	if err != nil {
+		return false
+	}
+	if !b.isTurn(room) {
+		return true
+	}
+
+	// 思考时间内对局可能暂停或结束，思考后重新检查
+	select {
+	case <-b.conn.done:
+		return false
+	case <-time.After(config.GetBotThinkDelay()):
+	}
+	if b.isTurn(room) {
+		b.takeTurn(room)
+	}
+	return true
+}
+
+// isTurn 判断是否轮到机器人行动
+func (b *botPlayer) isTurn(room *types.RoomInfo) bool {
+	if room.Status != "playing" || room.IsPaused() || !room.IsPlayerAlive(b.username) {
+		return false
+	}
+	currentPlayer, exists := room.GetCurrentPlayer()
+	return exists && currentPlayer == b.username
+}
+
+// takeTurn 执行机器人的回合：简单以外的难度先合成，再按难度选择出牌，没有手牌时跳过回合
+func (b *botPlayer) takeTurn(room *types.RoomInfo) {
+	if b.level != models.BotLevelEasy {
+		b.compose(room)
+	}
+
+	handCards, err := room.GetPlayerHandCards(b.username)
+	if err != nil {
+		return
+	}
+
+	processor := NewPlayCardProcessor()
+	if len(handCards) == 0 {
+		gameEnded, err := processor.PassTurn(room, b.username)
+		if err != nil {
+			log.Printf("Bot %s failed to pass turn in room %s: %v", b.username, room.RoomID, err)
+			return
+		}
+		if !gameEnded {
+			processor.publishGameStateUpdateWithBonds(room)
+		}
+		return
+	}
+
+	cardsToPlay, target := b.choosePlay(room, handCards)
+	playData := events.NewEventData(events.EventCardPlay, "bot_processor", map[string]interface{}{
+		"room_id":    room.RoomID,
+		"player":     b.username,
+		"self_cards": cardsToPlay,
+		"target":     target,
+		"source":     "bot",
+	})
+	playData.SetRoom(room.RoomID)
+	processor.ProcessPlayCard(playData)
+}
+
+// compose 贪心合成：手牌满足任意配方时提交全部手牌，由合成处理器连锁结算所有可完成的配方
+func (b *botPlayer) compose(room *types.RoomInfo) {
+	handCards, err := room.GetPlayerHandCards(b.username)
+	if err != nil {
+		return
+	}
+	if index, _ := matchRecipe(roomCardSet(room).Recipes, handCards, nil); index < 0 {
+		return
+	}
+
+	composeData := events.NewEventData(events.EventCardCompose, "bot_processor", map[string]interface{}{
+		"room_id":   room.RoomID,
+		"player":    b.username,
+		"cards":     handCards,
+		"client_id": b.clientID,
+	})
+	composeData.SetRoom(room.RoomID)
+	NewCardComposeProcessor().ProcessCardCompose(composeData)
+}
+
+// choosePlay 按难度选择要打出的卡牌和出牌目标，目标为空时攻击下一名存活玩家
+//   - easy: 随机打出一张卡牌
+//   - normal: 打出羁绊计算后伤害最高的最多3张卡牌
+//   - hard: 攻击血量最低的对手，整手牌可以斩杀时全部打出，否则优先打出整手牌最优组合中触发羁绊的卡牌
+func (b *botPlayer) choosePlay(room *types.RoomInfo, handCards []models.Card) ([]models.Card, string) {
+	calculator := NewRoomBondCalculator(room)
+
+	switch b.level {
+	case models.BotLevelEasy:
+		return []models.Card{handCards[b.rng.Intn(len(handCards))]}, ""
+	case models.BotLevelHard:
+		target, targetHealth := b.weakestOpponent(room)
+		fullHand := calculator.CalculateBondDamage(handCards)
+		if target != "" && fullHand.TotalDamage >= targetHealth {
+			return handCards, target
+		}
+		if len(fullHand.UsedCards) > 0 {
+			return fullHand.UsedCards, target
+		}
+		return bestPlay(calculator, handCards, botMaxPlayCards), target
+	default:
+		return bestPlay(calculator, handCards, botMaxPlayCards), ""
+	}
+}
+
+// weakestOpponent 获取血量最低的存活对手及其血量，血量相同时按座位顺序
+func (b *botPlayer) weakestOpponent(room *types.RoomInfo) (string, float64) {
+	target, targetHealth := "", 0.0
+	for _, username := range room.GetAlivePlayers() {
+		if username == b.username {
+			continue
+		}
+		health, err := room.GetPlayerCurrentHealth(username)
+		if err != nil {
+			continue
+		}
+		if target == "" || health < targetHealth {
+			target, targetHealth = username, health
+		}
+	}
+	return target, targetHealth
+}
+
+// bestPlay 在最多maxCards张卡牌的组合中找出羁绊计算后总伤害最高的组合，伤害相同时使用更少的卡牌
+func bestPlay(calculator *BondCalculator, handCards []models.Card, maxCards int) []models.Card {
+	var best []models.Card
+	bestDamage := -1.0
+	combination := make([]models.Card, 0, maxCards)
+
+	var search func(start int)
+	search = func(start int) {
+		if len(combination) > 0 {
+			damage := calculator.CalculateBondDamage(combination).TotalDamage
+			if damage > bestDamage || (damage == bestDamage && len(combination) < len(best)) {
+				best = append([]models.Card(nil), combination...)
+				bestDamage = damage
+			}
+		}
+		if len(combination) == maxCards {
+			return
+		}
+		for i := start; i < len(handCards); i++ {
+			combination = append(combination, handCards[i])
+			search(i + 1)
+			combination = combination[:len(combination)-1]
+		}
+	}
+
+	search(0)
+	return best
+}
+
+// botConn 机器人的虚拟连接：不写出任何数据，收到消息时唤醒机器人检查是否轮到自己行动
+type botConn struct {
+	addr   botAddr
+	notify chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// botAddr 机器人连接的虚拟地址，保证每个机器人在连接管理器中的地址唯一
+type botAddr string
+
+func (a botAddr) Network() string { return "bot" }
+func (a botAddr) String() string  { return string(a) }
+
+// newBotConn 创建机器人的虚拟连接
+func newBotConn(clientID string) *botConn {
+	return &botConn{
+		addr:   botAddr("bot/" + clientID),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// WriteFrame 丢弃消息内容并唤醒机器人，已有未处理的唤醒时合并
+func (c *botConn) WriteFrame(payload []byte) error {
+	select {
+	case <-c.done:
+		return net.ErrClosed
+	default:
+	}
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// RemoteAddr 获取虚拟地址
+func (c *botConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// Close 关闭连接并停止机器人主循环
+func (c *botConn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+	})
+	return nil
+}
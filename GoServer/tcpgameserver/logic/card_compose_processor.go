@@ -2,25 +2,26 @@ package logic
 
 import (
 	"fmt"
+	"log"
 
 	"GoServer/tcpgameserver/cards"
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
 )
 
 // CardComposeProcessor 卡牌合成处理器
 type CardComposeProcessor struct {
-	Name            string
-	cardPoolManager *cards.CardPoolManager
+	Name string
 }
 
 // NewCardComposeProcessor 创建新的卡牌合成处理器
 func NewCardComposeProcessor() *CardComposeProcessor {
 	return &CardComposeProcessor{
-		Name:            "CardComposeProcessor",
-		cardPoolManager: cards.GetCardPoolManager(),
+		Name: "CardComposeProcessor",
 	}
 }
 
@@ -32,13 +33,34 @@ type CardComposeData struct {
 	ClientID string        `json:"client_id"`
 }
 
+// maxComposeSteps 一次合成请求最多结算的配方次数，防止配方互相产出时无限连锁
+const maxComposeSteps = 50
+
+// 配方结算结果
+const (
+	ComposeOutcomeSuccess = "success" // 产出结果卡牌
+	ComposeOutcomeBonus   = "bonus"   // 产出结果卡牌和奖励卡牌
+	ComposeOutcomeFailed  = "failed"  // 消耗材料但没有产出
+)
+
 // ComposeResult 合成结果
 type ComposeResult struct {
-	Success       bool          `json:"success"`
-	Message       string        `json:"message"`
-	ComposedCards []models.Card `json:"composed_cards"`
-	RemovedCards  []models.Card `json:"removed_cards"`
-	NewCards      []models.Card `json:"new_cards"`
+	Success           bool          `json:"success"`
+	Message           string        `json:"message"`
+	ComposedCards     []models.Card `json:"composed_cards"`
+	RemovedCards      []models.Card `json:"removed_cards"`      // 从手牌消耗的卡牌
+	NewCards          []models.Card `json:"new_cards"`          // 加入手牌的卡牌
+	IntermediateCards []models.Card `json:"intermediate_cards"` // 连锁合成中产出后又被消耗的卡牌
+	Steps             []ComposeStep `json:"steps"`              // 按结算顺序排列的每次配方合成
+}
+
+// ComposeStep 一次配方合成的结算结果
+type ComposeStep struct {
+	RecipeID   int           `json:"recipe_id"`   // 配方ID，由卡牌合成目标生成的默认配方为0
+	RecipeName string        `json:"recipe_name"` // 配方名称
+	Outcome    string        `json:"outcome"`     // 结算结果：success, bonus, failed
+	Consumed   []models.Card `json:"consumed"`    // 消耗的卡牌
+	Produced   []models.Card `json:"produced"`    // 产出的卡牌
 }
 
 // ProcessCardCompose 处理卡牌合成逻辑
@@ -68,92 +90,217 @@ func (ccp *CardComposeProcessor) ProcessCardCompose(eventData *events.EventData)
 	}
 
 
-	// 步骤1: 验证卡牌信息
-	room, validatedCardGroups, err := ccp.validateComposeRequest(data)
+	// 获取房间信息
+	roomManager := service.GetRoomManager()
+	room, err := roomManager.GetRoom(data.RoomID)
 	if err != nil {
 		return
 	}
 
-	// 步骤2: 进行合成
-	composeResult := ccp.performComposition(room, validatedCardGroups)
-	if !composeResult.Success {
+	// 步骤1-3: 验证卡牌信息、进行合成并更新房间内玩家信息
+	composeResult, err := ccp.composeOnRoom(room, data)
+	if err != nil {
+		log.Printf("Compose failed in room %s: %v", data.RoomID, err)
+		ccp.reply(data.ClientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(2302))
 		return
 	}
 
+	// 写入回放日志
+	GlobalReplayRecorder.RecordComposeCard(room, data.Player, composeResult)
+
+	// 向合成的玩家发送详细的合成结果 (消息码2301)
+	ccp.reply(data.ClientID, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2301, composeResult))
+
+	// 步骤4: 发布游戏状态更新事件
+	ccp.publishComposeResult(room)
+}
+
+// composeOnRoom 在指定房间上执行合成：验证卡牌信息、进行合成并更新玩家信息
+func (ccp *CardComposeProcessor) composeOnRoom(room *types.RoomInfo, data *CardComposeData) (*ComposeResult, error) {
+	// 步骤1: 验证卡牌信息
+	validatedCards, err := ccp.validateComposeRequest(room, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 步骤2: 按配方进行合成
+	composeResult := ccp.performComposition(room, validatedCards)
+	if !composeResult.Success {
+		return nil, fmt.Errorf("compose failed for player %s: %s", data.Player, composeResult.Message)
+	}
+
 	// 步骤3: 更新房间内玩家信息
 	err = ccp.updatePlayerInfo(room, data.Player, &composeResult)
 	if err != nil {
-		return
+		return nil, err
 	}
-	// 步骤4: 发布游戏状态更新事件
-	ccp.publishComposeResult(room)
-}
 
-// performComposition 执行卡牌合成逻辑
-func (ccp *CardComposeProcessor) performComposition(room *types.RoomInfo, cardGroups map[string][]models.Card) ComposeResult {
-	var removedCards []models.Card
-	var newCards []models.Card
-	composedCount := 0
+	return &composeResult, nil
+}
 
-	for _, cards := range cardGroups {
-		// 检查是否有足够的卡牌合成（每3张合成1张）
-		groupSize := len(cards)
-		canCompose := groupSize / 3
+// performComposition 按配方执行合成：依次找到第一个材料足够的配方，消耗材料并从房间卡牌池抽取产出的卡牌
+// 产出的卡牌可以继续作为之后配方的材料，一次请求即可完成2级到3级的连锁合成
+func (ccp *CardComposeProcessor) performComposition(room *types.RoomInfo, submitted []models.Card) ComposeResult {
+	cardSet := roomCardSet(room)
+	available := append([]models.Card(nil), submitted...) // 可作为材料的卡牌：提交的手牌和之前产出的卡牌
+	exhausted := make(map[int]bool)                        // 结果卡牌已被抽空的配方
+	steps := make([]ComposeStep, 0)
+
+	for len(steps) < maxComposeSteps {
+		index, consumed := matchRecipe(cardSet.Recipes, available, exhausted)
+		if index < 0 {
+			break
+		}
 
-		if canCompose == 0 {
+		step, ok := ccp.resolveRecipe(room, cardSet, &cardSet.Recipes[index], consumed)
+		if !ok {
+			exhausted[index] = true
 			continue
 		}
+		available = append(removeCardsByUID(available, consumed), step.Produced...)
+		steps = append(steps, step)
+	}
 
-		// 获取第一张卡牌作为模板
-		templateCard := cards[0]
-
-		// 检查TargetName是否为空（不可合成卡牌）
-		if templateCard.TargetName == nil || *templateCard.TargetName == "" {
-			continue
+	if len(steps) == 0 {
+		return ComposeResult{
+			Success: false,
+			Message: "No valid compositions found",
 		}
+	}
 
-		// 获取目标卡牌信息
-		targetCard, err := ccp.cardPoolManager.GetCardByName(*templateCard.TargetName)
-		if err != nil {
-			continue
+	// 区分从手牌消耗的卡牌、最终产出的卡牌和连锁合成中被再次消耗的中间卡牌
+	consumedUIDs := make(map[string]bool)
+	for _, step := range steps {
+		for _, card := range step.Consumed {
+			consumedUIDs[card.UID] = true
+		}
+	}
+	removedCards := make([]models.Card, 0)
+	for _, card := range submitted {
+		if consumedUIDs[card.UID] {
+			removedCards = append(removedCards, card)
 		}
-		// 合成指定数量的新卡牌
-		for i := 0; i < canCompose; i++ {
-			// 选择3张要移除的卡牌
-			startIdx := i * 3
-			endIdx := startIdx + 3
-			cardsToRemove := cards[startIdx:endIdx]
-			removedCards = append(removedCards, cardsToRemove...)
-
-			// 从对应等级的卡牌池中抽取目标卡牌
-			drawnCard, err := room.DrawCardByNameFromPool(*templateCard.TargetName, targetCard.Level)
-			if err != nil {
-
-				// 如果抽取失败，将已移除的卡牌放回原组
-				// 这里简化处理，可以根据需要实现更复杂的回滚逻辑
-				continue
+	}
+	newCards := make([]models.Card, 0)
+	intermediateCards := make([]models.Card, 0)
+	for _, step := range steps {
+		for _, card := range step.Produced {
+			if consumedUIDs[card.UID] {
+				intermediateCards = append(intermediateCards, card)
+			} else {
+				newCards = append(newCards, card)
+			}
+		}
+	}
+
+	composedCards := make([]models.Card, 0, len(removedCards)+len(newCards))
+	composedCards = append(append(composedCards, removedCards...), newCards...)
+	return ComposeResult{
+		Success:           true,
+		Message:           fmt.Sprintf("Resolved %d compose recipes, produced %d new cards", len(steps), len(newCards)),
+		ComposedCards:     composedCards,
+		RemovedCards:      removedCards,
+		NewCards:          newCards,
+		IntermediateCards: intermediateCards,
+		Steps:             steps,
+	}
+}
+
+// resolveRecipe 结算一次配方：按成功率判定，成功时从房间卡牌池抽取结果卡牌，并按奖励概率额外抽取奖励卡牌
+// 卡牌池中已没有结果卡牌时返回false，此时不消耗材料
+func (ccp *CardComposeProcessor) resolveRecipe(room *types.RoomInfo, cardSet *models.CardSet, recipe *models.ComposeRecipe, consumed []models.Card) (ComposeStep, bool) {
+	step := ComposeStep{
+		RecipeID:   recipe.ID,
+		RecipeName: recipe.Name,
+		Outcome:    ComposeOutcomeFailed,
+		Consumed:   consumed,
+		Produced:   make([]models.Card, 0),
+	}
+
+	resultCard, err := cardSet.GetCardByName(recipe.Result)
+	if err != nil || !room.HasCardInPool(recipe.Result, resultCard.Level) {
+		return step, false
+	}
+	if !room.RollChance(recipe.SuccessRate) {
+		return step, true
+	}
+
+	drawnCard, err := room.DrawCardByNameFromPool(recipe.Result, resultCard.Level)
+	if err != nil {
+		return step, false
+	}
+	step.Outcome = ComposeOutcomeSuccess
+	step.Produced = append(step.Produced, *drawnCard)
+
+	// 奖励卡牌已被抽空时只产出结果卡牌
+	if recipe.BonusRate > 0 && room.RollChance(recipe.BonusRate) {
+		if bonusCard, err := cardSet.GetCardByName(recipe.GetBonusResult()); err == nil {
+			if drawnBonus, err := room.DrawCardByNameFromPool(bonusCard.Name, bonusCard.Level); err == nil {
+				step.Outcome = ComposeOutcomeBonus
+				step.Produced = append(step.Produced, *drawnBonus)
 			}
+		}
+	}
+	return step, true
+}
 
-			newCards = append(newCards, *drawnCard)
-			composedCount++
+// matchRecipe 按配方顺序查找第一个材料足够的配方，返回配方下标和消耗的卡牌，没有时返回-1
+func matchRecipe(recipes []models.ComposeRecipe, available []models.Card, exhausted map[int]bool) (int, []models.Card) {
+	for i := range recipes {
+		if exhausted[i] {
+			continue
+		}
+		if consumed, ok := takeIngredients(recipes[i].Ingredients, available); ok {
+			return i, consumed
+		}
+	}
+	return -1, nil
+}
 
+// takeIngredients 按卡牌顺序选取配方需要的材料，材料不足时返回false
+func takeIngredients(ingredients []models.RecipeIngredient, available []models.Card) ([]models.Card, bool) {
+	used := make(map[string]bool)
+	consumed := make([]models.Card, 0)
+	for _, ingredient := range ingredients {
+		needed := ingredient.Count
+		for _, card := range available {
+			if needed == 0 {
+				break
+			}
+			if card.Name == ingredient.CardName && !used[card.UID] {
+				used[card.UID] = true
+				consumed = append(consumed, card)
+				needed--
 			}
+		}
+		if needed > 0 {
+			return nil, false
+		}
 	}
+	return consumed, true
+}
 
-	if composedCount == 0 {
-		return ComposeResult{
-			Success: false,
-			Message: "No valid compositions found",
+// removeCardsByUID 返回移除指定卡牌后的卡牌列表
+func removeCardsByUID(cards []models.Card, removed []models.Card) []models.Card {
+	removedUIDs := make(map[string]bool, len(removed))
+	for _, card := range removed {
+		removedUIDs[card.UID] = true
+	}
+	remaining := make([]models.Card, 0, len(cards))
+	for _, card := range cards {
+		if !removedUIDs[card.UID] {
+			remaining = append(remaining, card)
 		}
 	}
+	return remaining
+}
 
-	return ComposeResult{
-		Success:       true,
-		Message:       fmt.Sprintf("Successfully composed %d new cards", composedCount),
-		ComposedCards: append(removedCards, newCards...),
-		RemovedCards:  removedCards,
-		NewCards:      newCards,
+// roomCardSet 获取房间开局时的卡牌集快照，未设置时（如回放模拟房间）使用当前卡牌集
+func roomCardSet(room *types.RoomInfo) *models.CardSet {
+	if cardSet := room.GetCardSet(); cardSet != nil {
+		return cardSet
 	}
+	return cards.CurrentCardSet()
 }
 
 // publishComposeResult 发布合成结果事件
@@ -165,31 +312,38 @@ func (ccp *CardComposeProcessor) publishComposeResult(room *types.RoomInfo) {
 	events.Publish(events.EventGameStateUpdate, stateUpdateData)
 }
 
-// validateComposeRequest 验证合成请求信息
-func (ccp *CardComposeProcessor) validateComposeRequest(data *CardComposeData) (*types.RoomInfo, map[string][]models.Card, error) {
-	// 获取房间管理器
-	roomManager := service.GetRoomManager()
-
-	// 获取房间信息
-	room, err := roomManager.GetRoom(data.RoomID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get room %s: %v", data.RoomID, err)
+// reply 向发起合成的客户端发送消息
+func (ccp *CardComposeProcessor) reply(clientID string, response *models.TcpResponse) {
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByClientID(clientID)
+	if !exists || clientInfo.Conn == nil {
+		return
 	}
+	if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+		log.Printf("Failed to send compose message %s to client %s: %v", response.Code, clientID, err)
+	}
+}
 
+// validateComposeRequest 验证合成请求信息，返回按提交顺序排列的手牌
+func (ccp *CardComposeProcessor) validateComposeRequest(room *types.RoomInfo, data *CardComposeData) ([]models.Card, error) {
 	// 验证房间状态
 	if room.Status != "playing" {
-		return nil, nil, fmt.Errorf("room %s is not in playing state: %s", data.RoomID, room.Status)
+		return nil, fmt.Errorf("room %s is not in playing state: %s", data.RoomID, room.Status)
+	}
+	if room.IsPaused() {
+		return nil, fmt.Errorf("room %s is paused", data.RoomID)
 	}
 
 	// 获取玩家信息
 	playerInfo, err := room.GetPlayerInfo(data.Player)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get player info for %s: %v", data.Player, err)
+		return nil, fmt.Errorf("failed to get player info for %s: %v", data.Player, err)
+	}
+	if playerInfo.CurrentHealth <= 0 {
+		return nil, fmt.Errorf("player %s has been eliminated", data.Player)
 	}
 
-	// 验证卡牌数量（必须是3的倍数）
-	if len(data.Cards)%3 != 0 {
-		return nil, nil, fmt.Errorf("invalid card count for composition: %d (must be multiple of 3)", len(data.Cards))
+	if len(data.Cards) == 0 {
+		return nil, fmt.Errorf("no cards to compose")
 	}
 
 	// 构建手牌UID映射用于快速查找和验证
@@ -198,48 +352,56 @@ func (ccp *CardComposeProcessor) validateComposeRequest(data *CardComposeData) (
 		handCardMap[handCard.UID] = handCard
 	}
 
-	// 验证所有要合成的卡牌都在玩家手牌中
+	// 验证所有要合成的卡牌都在玩家手牌中且没有重复
 	var validatedCards []models.Card
+	submittedUIDs := make(map[string]bool, len(data.Cards))
 	for _, cardToCompose := range data.Cards {
+		if submittedUIDs[cardToCompose.UID] {
+			return nil, fmt.Errorf("card UID %s submitted more than once", cardToCompose.UID)
+		}
+		submittedUIDs[cardToCompose.UID] = true
+
 		if handCard, exists := handCardMap[cardToCompose.UID]; exists {
 			// 验证卡牌详细信息匹配
 			if handCard.Name != cardToCompose.Name || handCard.ID != cardToCompose.ID {
-				return nil, nil, fmt.Errorf("card information mismatch for UID %s: expected %s (ID: %d), got %s (ID: %d)",
+				return nil, fmt.Errorf("card information mismatch for UID %s: expected %s (ID: %d), got %s (ID: %d)",
 					cardToCompose.UID, handCard.Name, handCard.ID, cardToCompose.Name, cardToCompose.ID)
 			}
-			validatedCards = append(validatedCards, cardToCompose)
+			validatedCards = append(validatedCards, handCard)
 		} else {
-			return nil, nil, fmt.Errorf("card UID %s not found in player %s's hand", cardToCompose.UID, data.Player)
+			return nil, fmt.Errorf("card UID %s not found in player %s's hand", cardToCompose.UID, data.Player)
 		}
 	}
 
-	// 按照卡牌名称分组
-	cardGroups := make(map[string][]models.Card)
-	for _, card := range validatedCards {
-		cardGroups[card.Name] = append(cardGroups[card.Name], card)
-	}
-
-	return room, cardGroups, nil
+	return validatedCards, nil
 }
 
 // updatePlayerInfo 更新玩家信息（移除旧卡牌，添加新卡牌）
 func (ccp *CardComposeProcessor) updatePlayerInfo(room *types.RoomInfo, playerName string, result *ComposeResult) error {
-	// 1. 从玩家手牌中移除已合成的卡牌
+	// 1. 从玩家手牌中移除已合成的卡牌并放入弃牌堆
 	var cardUIDs []string
 	for _, card := range result.RemovedCards {
 		cardUIDs = append(cardUIDs, card.UID)
 	}
 
-	err := room.RemoveCardsFromPlayerByUID(playerName, cardUIDs)
+	err := room.DiscardCardsFromPlayerByUID(playerName, cardUIDs)
 	if err != nil {
 		return fmt.Errorf("failed to remove composed cards from player %s: %v", playerName, err)
 	}
 
-	// 2. 将新合成的卡牌添加到玩家手牌
-	for _, newCard := range result.NewCards {
+	// 2. 连锁合成中被再次消耗的中间卡牌同样放入弃牌堆
+	if len(result.IntermediateCards) > 0 {
+		if err := room.DiscardCards(playerName, result.IntermediateCards); err != nil {
+			return fmt.Errorf("failed to discard intermediate cards of player %s: %v", playerName, err)
+		}
+	}
+
+	// 3. 将新合成的卡牌添加到玩家手牌
+	for i, newCard := range result.NewCards {
 		err = room.AddCardToPlayer(playerName, newCard)
 		if err != nil {
-			// 如果添加失败，需要考虑回滚已移除的卡牌
+			// 手牌已满，未能加入手牌的卡牌放回卡牌池
+			room.DiscardCards(playerName, result.NewCards[i:])
 			return fmt.Errorf("failed to add new card %s to player %s: %v", newCard.Name, playerName, err)
 		}
 	}
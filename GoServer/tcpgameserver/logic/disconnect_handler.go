@@ -1,8 +1,10 @@
 package logic
 
 import (
-	"encoding/json"
+	"time"
 
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
@@ -44,6 +46,17 @@ func (d *DisconnectHandler) handleLoggedInUserDisconnect(clientID, username, rea
 
 	roomID := clientInfo.GetGameRoom()
 	status := clientInfo.GetStatus()
+
+	// 离开匹配队列
+	service.GetMatchmakingQueue().Remove(username)
+	// 离开尚未开始的私人房间
+	if status == types.StatusInRoom && roomID != "" {
+		GlobalPrivateRoomProcessor.LeaveRoom(clientInfo)
+	}
+	// 停止观战
+	if status == types.StatusSpectating && roomID != "" {
+		GlobalSpectatorProcessor.StopSpectating(clientInfo)
+	}
 	// 如果玩家在游戏中，设置为等待重连状态
 	if status == types.StatusInGame && roomID != "" {
 		clientInfo.SetStatus(types.StatusWaitingReconnect)
@@ -59,6 +72,13 @@ func (d *DisconnectHandler) handleInGamePlayerDisconnect(clientID, username, rea
 	// 获取连接管理器
 	connManager := service.GetConnectionManager()
 
+	// 开始等待重连，超时未重连判负
+	reconnectSeconds := 0
+	if room, err := roomManager.GetRoom(roomID); err == nil && room.Status == "playing" {
+		window := GlobalReconnectGraceProcessor.StartGrace(room, username, clientID)
+		reconnectSeconds = int(window.Round(time.Second).Seconds())
+	}
+
 	// 获取房间内的所有玩家连接
 	allConnections := connManager.GetAllConnections()
 	var roomPlayers []*types.ClientInfo
@@ -72,11 +92,12 @@ func (d *DisconnectHandler) handleInGamePlayerDisconnect(clientID, username, rea
 
 	// 创建断开连接通知消息 (消息类型 7001)
 	disconnectNotification := map[string]interface{}{
-		"message_type": "player_disconnect",
-		"username":     username,
-		"status":       "waiting_reconnect",
-		"reason":       reason,
-		"room_id":      roomID,
+		"message_type":      "player_disconnect",
+		"username":          username,
+		"status":            "waiting_reconnect",
+		"reason":            reason,
+		"room_id":           roomID,
+		"reconnect_seconds": reconnectSeconds,
 	}
 
 	// 向房间内其他玩家发送断开连接通知
@@ -84,16 +105,18 @@ func (d *DisconnectHandler) handleInGamePlayerDisconnect(clientID, username, rea
 		if player.Conn != nil {
 
 			response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(7001, disconnectNotification)
-
-			if messageData, err := json.Marshal(response); err == nil {
-				messageData = append(messageData, '\n')
-				if _, writeErr := player.Conn.Write(messageData); writeErr != nil {
-				} else {
-				}
-			}
+			protocol.WriteJSON(player.Conn, response)
 		}
 	}
 
+	// 对局中断线自动暂停，等待玩家重连
+	pauseData := events.NewEventData(events.EventGamePause, "disconnect_handler", map[string]interface{}{
+		"username": username,
+		"reason":   types.PauseReasonDisconnect,
+	})
+	pauseData.SetRoom(roomID)
+	events.Publish(events.EventGamePause, pauseData)
+
 	return nil
 }
 
@@ -0,0 +1,134 @@
+package logic
+
+import (
+	"log"
+
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// DrawOfferProcessor 和棋处理器：所有存活玩家同意和棋后以平局结束对局
+type DrawOfferProcessor struct {
+	Name string
+}
+
+// NewDrawOfferProcessor 创建新的和棋处理器
+func NewDrawOfferProcessor() *DrawOfferProcessor {
+	return &DrawOfferProcessor{
+		Name: "DrawOfferProcessor",
+	}
+}
+
+// 全局和棋处理器实例
+var GlobalDrawOfferProcessor = NewDrawOfferProcessor()
+
+// ProcessDrawOffer 处理和棋提议，向房间内玩家发送提议通知 (消息码1811)
+func (dp *DrawOfferProcessor) ProcessDrawOffer(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	clientID, _ := eventData.GetString("client_id")
+	username, _ := eventData.GetString("username")
+
+	room, ok := dp.getPlayingRoom(eventData.RoomID, username)
+	if !ok {
+		dp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1803))
+		return
+	}
+
+	if err := room.OfferDraw(username); err != nil {
+		dp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(drawErrorCode(err)))
+		return
+	}
+
+	broadcastToRoomPlayers(room, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1811, map[string]interface{}{
+		"RoomID":    room.RoomID,
+		"OfferedBy": username,
+	}))
+}
+
+// ProcessDrawResponse 处理和棋回应：拒绝时撤销提议 (消息码1813)，同意时通知进度 (消息码1812)，全部同意后以平局结束对局
+func (dp *DrawOfferProcessor) ProcessDrawResponse(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	clientID, _ := eventData.GetString("client_id")
+	username, _ := eventData.GetString("username")
+	accept, _ := eventData.GetBool("accept")
+
+	room, ok := dp.getPlayingRoom(eventData.RoomID, username)
+	if !ok {
+		dp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1803))
+		return
+	}
+
+	agreed, err := room.RespondDraw(username, accept)
+	if err != nil {
+		dp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(drawErrorCode(err)))
+		return
+	}
+
+	if !accept {
+		broadcastToRoomPlayers(room, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1813, map[string]interface{}{
+			"RoomID":     room.RoomID,
+			"DeclinedBy": username,
+		}))
+		return
+	}
+
+	broadcastToRoomPlayers(room, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1812, map[string]interface{}{
+		"RoomID":     room.RoomID,
+		"AcceptedBy": username,
+		"Agreed":     agreed,
+	}))
+	if !agreed {
+		return
+	}
+
+	// 所有存活玩家同意，通过游戏结束流程以平局结算
+	gameEndData := events.NewEventData(events.EventGameEnd, "draw_offer_processor", map[string]interface{}{
+		"reason": "draw",
+	})
+	gameEndData.SetRoom(room.RoomID)
+	events.Publish(events.EventGameEnd, gameEndData)
+}
+
+// getPlayingRoom 获取进行中的房间，并确认玩家仍存活
+func (dp *DrawOfferProcessor) getPlayingRoom(roomID, username string) (*types.RoomInfo, bool) {
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil || room.Status != "playing" || !room.IsPlayerAlive(username) {
+		return nil, false
+	}
+	return room, true
+}
+
+// reply 向请求的客户端发送消息
+func (dp *DrawOfferProcessor) reply(clientID string, response *models.TcpResponse) {
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByClientID(clientID)
+	if !exists || clientInfo.Conn == nil {
+		return
+	}
+	if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+		log.Printf("Failed to send draw offer message %s to client %s: %v", response.Code, clientID, err)
+	}
+}
+
+// drawErrorCode 和棋错误对应的响应码
+func drawErrorCode(err error) int {
+	switch err {
+	case types.ErrDrawOfferPending:
+		return 1814
+	case types.ErrDrawOfferTooSoon:
+		return 1815
+	case types.ErrNoDrawOffer:
+		return 1816
+	default:
+		return 1803
+	}
+}
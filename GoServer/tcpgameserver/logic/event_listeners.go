@@ -1,13 +1,13 @@
 package logic
 
 import (
-	"encoding/json"
-	"net"
+	"log"
 	"time"
 
 	"GoServer/tcpgameserver/cards"
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
@@ -55,6 +55,8 @@ func NewGameEventListener() *GameEventListener {
 				events.EventGamePause,
 				events.EventGameResume,
 				events.EventGameStateUpdate,
+				events.EventDrawOffer,
+				events.EventDrawResponse,
 			},
 			Priority: 10, // 高优先级
 		},
@@ -73,6 +75,10 @@ func (g *GameEventListener) HandleEvent(eventType string, data interface{}) {
 		g.handleGameResume(data)
 	case events.EventGameStateUpdate:
 		g.handleGameStateUpdate(data)
+	case events.EventDrawOffer:
+		GlobalDrawOfferProcessor.ProcessDrawOffer(data)
+	case events.EventDrawResponse:
+		GlobalDrawOfferProcessor.ProcessDrawResponse(data)
 	default:
 	}
 }
@@ -95,15 +101,13 @@ func (g *GameEventListener) handleGameEnd(data interface{}) {
 }
 
 func (g *GameEventListener) handleGamePause(data interface{}) {
-	if _, ok := data.(*events.EventData); ok {
-		// 暂停游戏逻辑
-	}
+	// 暂停游戏：冻结回合计时，到期自动恢复
+	GlobalPauseProcessor.ProcessPause(data)
 }
 
 func (g *GameEventListener) handleGameResume(data interface{}) {
-	if _, ok := data.(*events.EventData); ok {
-		// 恢复游戏逻辑
-	}
+	// 恢复游戏：以暂停时剩余的回合时间继续计时
+	GlobalPauseProcessor.ProcessResume(data)
 }
 
 func (g *GameEventListener) handleGameStateUpdate(data interface{}) {
@@ -132,6 +136,8 @@ func NewCardEventListener() *CardEventListener {
 				events.EventCardCompose,
 				events.EventDeckEmpty,
 				events.EventCardBonds,
+				events.EventBondPreview,
+				events.EventBondHints,
 			},
 			Priority: 30,
 		},
@@ -148,8 +154,14 @@ func (c *CardEventListener) HandleEvent(eventType string, data interface{}) {
 		c.handleCardCompose(data)
 	case events.EventDeckEmpty:
 		c.handleDeckEmpty(data)
+	case events.EventCardShuffle:
+		c.handleCardShuffle(data)
 	case events.EventCardBonds:
 		c.handleCardBonds(data)
+	case events.EventBondPreview:
+		GlobalBondPreviewProcessor.ProcessPreviewPlay(data)
+	case events.EventBondHints:
+		GlobalBondPreviewProcessor.ProcessBondHints(data)
 	default:
 	}
 }
@@ -183,13 +195,41 @@ func (c *CardEventListener) handleCardCompose(data interface{}) {
 	}
 }
 
+// handleDeckEmpty 通知房间内玩家卡牌池已抽空（消息码2201），弃牌堆已在抽牌时洗回，两者都为空时附带未能抽到的卡牌数量
 func (c *CardEventListener) handleDeckEmpty(data interface{}) {
-	if _, ok := data.(*events.EventData); ok {
+	if eventData, ok := data.(*events.EventData); ok {
+		room, err := service.GetRoomManager().GetRoom(eventData.RoomID)
+		if err != nil {
+			return
+		}
+		player, _ := eventData.GetString("player")
+		reshuffled, _ := eventData.GetInt("reshuffled")
+		shortfall, _ := eventData.GetInt("shortfall")
+
+		broadcastToRoomPlayers(room, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2201, map[string]interface{}{
+			"Player":        player,
+			"Reshuffled":    reshuffled,
+			"Shortfall":     shortfall,
+			"FatigueDamage": room.GetFatigueDamage(),
+		}))
+	}
+}
 
-		// 处理牌库为空
-		// 将弃牌堆洗入牌库
-		// 或者触发特殊规则
+// handleCardShuffle 通知房间内玩家弃牌堆已洗回卡牌池（消息码2202）
+func (c *CardEventListener) handleCardShuffle(data interface{}) {
+	if eventData, ok := data.(*events.EventData); ok {
+		room, err := service.GetRoomManager().GetRoom(eventData.RoomID)
+		if err != nil {
+			return
+		}
+		player, _ := eventData.GetString("player")
+		cardCount, _ := eventData.GetInt("cards")
 
+		broadcastToRoomPlayers(room, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2202, map[string]interface{}{
+			"Player":        player,
+			"Cards":         cardCount,
+			"DrawPileCount": room.GetDrawPileCount(player),
+		}))
 	}
 }
 
@@ -198,9 +238,21 @@ func (c *CardEventListener) handleCardBonds(data interface{}) {
 
 		connManager := service.GetConnectionManager()
 		ClientID, _ := eventData.GetString("client_id")
+
+		// 获取玩家连接
+		clientInfo, exists := connManager.GetConnectionByClientID(ClientID)
+		if !exists || clientInfo == nil || clientInfo.Conn == nil {
+			return
+		}
+
+		// 对局中的玩家使用房间开局时的卡牌集，否则使用当前卡牌集
+		cardSet := cards.CurrentCardSet()
+		if room, err := service.GetRoomManager().GetRoom(clientInfo.GetGameRoom()); err == nil {
+			cardSet = roomCardSet(room)
+		}
+
 		// 将羁绊数据转换为切片格式，便于序列化
-		bondPoolManager := cards.GetBondPoolManager()
-		allBonds := bondPoolManager.GetAllBonds()
+		allBonds := cardSet.GetAllBonds()
 		bondList := make([]*models.BondModel, 0, len(allBonds))
 		for _, bond := range allBonds {
 			bondList = append(bondList, bond)
@@ -209,12 +261,6 @@ func (c *CardEventListener) handleCardBonds(data interface{}) {
 		// 发送羁绊数据消息 (5002)
 		response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(5002, bondList)
 
-		// 获取玩家连接并发送消息
-		clientInfo, exists := connManager.GetConnectionByClientID(ClientID)
-		if !exists || clientInfo == nil || clientInfo.Conn == nil {
-			return
-		}
-
 		sendTCPResponse(clientInfo.Conn, response)
 
 	}
@@ -337,6 +383,7 @@ func NewSystemEventListener() *SystemEventListener {
 				events.EventSystemShutdown,
 				events.EventSystemError,
 				events.EventServerMaintenance,
+				events.EventDataLoad,
 			},
 			Priority: 5, // 最高优先级
 		},
@@ -353,6 +400,8 @@ func (s *SystemEventListener) HandleEvent(eventType string, data interface{}) {
 		s.handleSystemError(data)
 	case events.EventServerMaintenance:
 		s.handleServerMaintenance(data)
+	case events.EventDataLoad:
+		GlobalGameDataReloadProcessor.ProcessReload(data)
 	default:
 	}
 }
@@ -365,20 +414,13 @@ func (s *SystemEventListener) handleSystemStart(data interface{}) {
 		// 加载配置
 		// 启动服务
 
-		// 加载响应码配置文件
-		if err := tools.LoadResponseCodes(); err != nil {
-		} else {
+		// 加载响应码、卡牌池和羁绊池（数据包文件或MySQL）
+		if _, err := GlobalGameDataReloadProcessor.Reload(); err != nil {
+			log.Printf("Failed to load game data: %v", err)
 		}
 
-		// 初始化卡牌池
-		if err := cards.InitCardPool(); err != nil {
-		} else {
-		}
-
-		// 初始化羁绊池
-		if err := cards.InitBondPool(); err != nil {
-		} else {
-		}
+		// 启动匹配处理器
+		GlobalMatchmakingProcessor.Start()
 
 	}
 }
@@ -390,6 +432,7 @@ func (s *SystemEventListener) handleSystemShutdown(data interface{}) {
 		// 保存数据
 		// 断开连接
 		// 清理资源
+		GlobalMatchmakingProcessor.Stop()
 	}
 }
 
@@ -431,6 +474,11 @@ func NewRoomEventListener() *RoomEventListener {
 				events.EventRoomDestroy,
 				events.EventRoomFull,
 				events.EventRoomEmpty,
+				events.EventRoomUpdate,
+				events.EventPlayerJoin,
+				events.EventPlayerLeave,
+				events.EventSpectatorJoin,
+				events.EventSpectatorLeave,
 			},
 			Priority: 15, // 中等优先级
 		},
@@ -447,6 +495,12 @@ func (r *RoomEventListener) HandleEvent(eventType string, data interface{}) {
 		r.handleRoomFull(data)
 	case events.EventRoomEmpty:
 		r.handleRoomEmpty(data)
+	case events.EventRoomUpdate, events.EventPlayerJoin, events.EventPlayerLeave:
+		r.handleRoomUpdate(data)
+	case events.EventSpectatorJoin:
+		GlobalSpectatorProcessor.ProcessSpectatorJoin(data)
+	case events.EventSpectatorLeave:
+		GlobalSpectatorProcessor.ProcessSpectatorLeave(data)
 	default:
 	}
 }
@@ -457,6 +511,11 @@ func (r *RoomEventListener) handleRoomCreate(data interface{}) {
 	}
 }
 
+func (r *RoomEventListener) handleRoomUpdate(data interface{}) {
+	// 玩家进出私人房间或房主修改设置时，同步房间等待信息
+	GlobalPrivateRoomProcessor.ProcessRoomUpdate(data)
+}
+
 func (r *RoomEventListener) handleRoomDestroy(data interface{}) {
 	if _, ok := data.(*events.EventData); ok {
 
@@ -559,11 +618,7 @@ func (c *ConnectionEventListener) handleClientConnect(data interface{}) {
 			})
 
 			// 通过连接发送欢迎消息
-			if welcomeData, err := json.Marshal(welcomeResponse); err == nil {
-				welcomeData = append(welcomeData, '\n')
-				if _, writeErr := clientInfo.Conn.Write(welcomeData); writeErr != nil {
-				}
-			}
+			sendTCPResponse(clientInfo.Conn, welcomeResponse)
 		}
 
 		// 处理客户端连接逻辑
@@ -709,15 +764,8 @@ func (c *ConnectionEventListener) handleConnectionCleanup(data interface{}) {
 }
 
 // sendTCPResponse 发送TCP响应消息
-func sendTCPResponse(conn net.Conn, resp *models.TcpResponse) {
-	jsonBytes, err := json.Marshal(resp)
-	if err != nil {
-		return
-	}
-	jsonBytes = append(jsonBytes, '\n')
-	_, err = conn.Write(jsonBytes)
-	if err != nil {
-	}
+func sendTCPResponse(conn protocol.Conn, resp *models.TcpResponse) {
+	protocol.WriteJSON(conn, resp)
 }
 
 // ListenerManager 监听器管理器
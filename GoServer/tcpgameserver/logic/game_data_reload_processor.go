@@ -0,0 +1,94 @@
+package logic
+
+import (
+	"log"
+	"sync"
+
+	"GoServer/tcpgameserver/cards"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+)
+
+// GameDataReloadProcessor 游戏数据热加载处理器：不重启服务器替换卡牌、羁绊和响应码定义
+// 新定义全部读取成功后才一起替换，任一读取失败时保留原有定义；进行中的房间继续使用开局时的卡牌集
+type GameDataReloadProcessor struct {
+	Name  string
+	mutex sync.Mutex // 串行化热加载
+}
+
+// NewGameDataReloadProcessor 创建新的游戏数据热加载处理器
+func NewGameDataReloadProcessor() *GameDataReloadProcessor {
+	return &GameDataReloadProcessor{
+		Name: "GameDataReloadProcessor",
+	}
+}
+
+// 全局游戏数据热加载处理器实例
+var GlobalGameDataReloadProcessor = NewGameDataReloadProcessor()
+
+// ProcessReload 处理热加载请求，成功时返回新的卡牌集版本 (消息码1901)，失败时返回1903
+func (rp *GameDataReloadProcessor) ProcessReload(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	clientID, _ := eventData.GetString("client_id")
+	username, _ := eventData.GetString("username")
+
+	result, err := rp.Reload()
+	if err != nil {
+		log.Printf("Game data reload requested by %s failed: %v", username, err)
+		rp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1903))
+		return
+	}
+
+	log.Printf("Game data reloaded by %s: card set %s -> %s", username, result.PreviousVersion, result.CardSetVersion)
+	rp.reply(clientID, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1901, result))
+}
+
+// Reload 从数据包文件或MySQL重新读取卡牌、羁绊和响应码定义，全部读取成功后一起替换
+func (rp *GameDataReloadProcessor) Reload() (*models.GameDataReloadResult, error) {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+
+	pack, err := service.LoadGameData()
+	if err != nil {
+		return nil, err
+	}
+	cardSet, err := cards.BuildCardSet(pack.Cards, pack.Bonds, pack.Recipes)
+	if err != nil {
+		return nil, err
+	}
+	responseCodes := tools.BuildResponseCodes(pack.ResponseCodes)
+
+	previousVersion := cards.CurrentCardSet().Version
+	cards.ActivateCardSet(cardSet)
+	tools.ApplyResponseCodes(responseCodes)
+
+	return &models.GameDataReloadResult{
+		CardSetVersion:  cardSet.Version,
+		PreviousVersion: previousVersion,
+		PackVersion:     pack.Version,
+		Cards:           len(cardSet.Templates),
+		Bonds:           len(cardSet.Bonds),
+		Recipes:         len(cardSet.Recipes),
+		ResponseCodes:   len(responseCodes),
+	}, nil
+}
+
+// reply 向请求的客户端发送消息
+func (rp *GameDataReloadProcessor) reply(clientID string, response *models.TcpResponse) {
+	if clientID == "" {
+		return
+	}
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByClientID(clientID)
+	if !exists || clientInfo.Conn == nil {
+		return
+	}
+	if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+		log.Printf("Failed to send reload message %s to client %s: %v", response.Code, clientID, err)
+	}
+}
@@ -1,6 +1,8 @@
 package logic
 
 import (
+	"log"
+	"time"
 
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
@@ -35,13 +37,28 @@ func (gep *GameEndProcessor) ProcessGameEnd(data interface{}) error {
 		return err
 	}
 
+	// 游戏结束事件可能从多处并发发布，只有将房间从游戏中切换为结束的事件继续处理
+	if !room.TransitionStatus("playing", "finished") {
+		return nil
+	}
+
 	// 步骤2: 为房间内玩家发送各自的玩家信息 (消息码1101)
 	err = gep.sendPlayerInfoToAll(room)
 	if err != nil {
 		return err
 	}
 
-	room.Status = "finished"
+	// 保存对局记录和回放日志，并更新玩家评分（需在清理房间信息前完成）
+	// 双方同意和棋时不论血量均为平局
+	winner := ""
+	if reason, _ := eventData.GetString("reason"); reason != "draw" {
+		winner = gep.determineWinner(room)
+	}
+	gep.saveMatchRecord(room, eventData, winner)
+	gep.updatePlayerRatings(room, winner)
+
+	// 通知观战者游戏结果
+	GlobalSpectatorProcessor.EndSpectating(room, winner)
 
 	// 步骤3: 清理房间信息
 	err = gep.cleanupRoomInfo(room)
@@ -60,8 +77,10 @@ func (gep *GameEndProcessor) ProcessGameEnd(data interface{}) error {
 		return err
 	}
 
-	// 步骤6: 清理全局计时器
-	GlobalRoomTimerProcessor.StopRoomTimer(room.RoomID)
+	// 步骤6: 清理全局计时器、暂停计时和断线重连等待
+	StopTimer(room.RoomID)
+	GlobalPauseProcessor.ClearRoom(room.RoomID)
+	GlobalReconnectGraceProcessor.ClearRoom(room.RoomID)
 	return nil
 }
 
@@ -135,6 +154,7 @@ func (gep *GameEndProcessor) cleanupRoomInfo(room *types.RoomInfo) error {
 		player.Round = ""
 		player.OtherPlayers = []models.OtherPlayerGameInfo{}
 		player.DamageInfo = []models.DamageInfo{}
+		player.StatusEffects = nil
 	}
 
 	return nil
@@ -174,3 +194,52 @@ func (gep *GameEndProcessor) deleteRoom(room *types.RoomInfo) error {
 
 	return nil
 }
+
+// saveMatchRecord 写入回放日志并持久化对局记录
+func (gep *GameEndProcessor) saveMatchRecord(room *types.RoomInfo, eventData *events.EventData, winner string) {
+	reason, _ := eventData.GetString("reason")
+	if reason == "" {
+		reason = "health_depleted"
+	}
+	loser, _ := eventData.GetString("loser")
+
+	GlobalReplayRecorder.RecordGameEnd(room, winner, reason, loser)
+
+	record := room.BuildMatchRecord(winner, reason, time.Now())
+	if err := service.SaveMatchRecord(record); err != nil {
+		log.Printf("Failed to save match record for room %s: %v", room.RoomID, err)
+	}
+}
+
+// updatePlayerRatings 根据对局结果更新玩家评分
+func (gep *GameEndProcessor) updatePlayerRatings(room *types.RoomInfo, winner string) {
+	usernames := make([]string, 0, len(room.Players))
+	for username := range room.Players {
+		// 有机器人参与的对局（人机练习和机器人补位）不计入评分
+		if models.IsBotUsername(username) {
+			return
+		}
+		usernames = append(usernames, username)
+	}
+
+	if err := GlobalMatchmakingProcessor.UpdateRatings(usernames, winner); err != nil {
+		log.Printf("Failed to update ratings for room %s: %v", room.RoomID, err)
+	}
+}
+
+// determineWinner 确定胜者：唯一存活的玩家获胜，否则为平局（返回空字符串）
+func (gep *GameEndProcessor) determineWinner(room *types.RoomInfo) string {
+	winner := ""
+	aliveCount := 0
+	for username, player := range room.Players {
+		if player.CurrentHealth > 0 {
+			winner = username
+			aliveCount++
+		}
+	}
+
+	if aliveCount != 1 {
+		return ""
+	}
+	return winner
+}
@@ -2,14 +2,16 @@ package logic
 
 import (
 	"GoServer/tcpgameserver/cards"
+	"GoServer/tcpgameserver/config"
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
-	"encoding/json"
 	"fmt"
-	"net"
+	"log"
+	"sort"
 	"time"
 )
 
@@ -25,16 +27,36 @@ func NewGameStartProcessor() *GameStartProcessor {
 func (g *GameStartProcessor) ProcessGameStart(eventData interface{}) error {
 	// 类型断言获取事件数据
 	if data, ok := eventData.(*events.EventData); ok {
-		// 检查是否是匹配触发的游戏开始事件
+		// 检查是否是玩家准备触发的游戏开始事件
 		if triggerSource, exists := data.GetString("trigger_source"); exists && triggerSource == "user_ready_handler" {
 
-			// 执行匹配逻辑
-			err := g.performMatchmaking()
-			if err != nil {
-				return err
-			} else {
+			// 加入匹配队列，由匹配处理器按评分和房间人数组建房间
+			clientID, _ := data.GetString("client_id")
+			roomSize, exists := data.GetInt("room_size")
+			if !exists {
+				roomSize = models.MinRoomSize
 			}
-			return nil
+			return GlobalMatchmakingProcessor.Enqueue(clientID, roomSize)
+		}
+
+		// 玩家开始人机练习，开始失败时通知玩家
+		if triggerSource, exists := data.GetString("trigger_source"); exists && triggerSource == "practice_handler" {
+			clientID, _ := data.GetString("client_id")
+			level, _ := data.GetString("level")
+			roomSize, exists := data.GetInt("room_size")
+			if !exists {
+				roomSize = models.MinRoomSize
+			}
+			var seed int64
+			if value, exists := data.GetData("seed"); exists {
+				seed, _ = value.(int64)
+			}
+			return g.startPractice(clientID, level, roomSize, seed)
+		}
+
+		// 房主开始私人房间
+		if triggerSource, exists := data.GetString("trigger_source"); exists && triggerSource == "private_room_handler" {
+			return g.StartPrivateRoom(data.RoomID)
 		}
 
 		return nil
@@ -50,6 +72,16 @@ func (g *GameStartProcessor) CreateGameRoom(roomName string, maxPlayers int) (*t
 	if err != nil {
 		return nil, fmt.Errorf("failed to create room: %v", err)
 	}
+	room.ReconnectWindow = config.GetReconnectWindow()
+	room.FatigueDamage = config.GetFatigueDamage()
+
+	// 匹配房间的回合超时规则与标准预设一致
+	standard, _ := config.GetRoomPreset(config.DefaultRoomPreset)
+	turnTimeout := time.Duration(standard.TurnTimeoutSeconds) * time.Second
+	if err := room.SetTimeoutPolicy(turnTimeout, standard.TimeoutPolicy, *standard.MaxConsecutiveTimeouts); err != nil {
+		roomManager.RemoveRoom(room.RoomID)
+		return nil, fmt.Errorf("failed to set timeout policy: %v", err)
+	}
 
 	return room, nil
 }
@@ -60,12 +92,26 @@ func (g *GameStartProcessor) CleanupRoom(roomID string) {
 	roomManager.RemoveRoom(roomID)
 }
 
-// InitializeRoomCardPools 初始化房间卡牌池
+// InitializeRoomCardPools 初始化房间卡牌池，并为房间创建随机源
+// 设置了 ROOM_SEED 环境变量时使用固定种子，否则以当前时间作为种子
 func (g *GameStartProcessor) InitializeRoomCardPools(room *types.RoomInfo) error {
-	// 获取卡牌池
-	level1Cards := cards.GetLevel1Cards()
-	level2Cards := cards.GetLevel2Cards()
-	level3Cards := cards.GetLevel3Cards()
+	seed, fixed := config.GetFixedRoomSeed()
+	if !fixed {
+		seed = time.Now().UnixNano()
+	}
+
+	return g.InitializeRoomCardPoolsWithSeed(room, seed)
+}
+
+// InitializeRoomCardPoolsWithSeed 使用指定随机种子初始化房间卡牌池，相同种子和卡牌数据得到相同的发牌结果
+func (g *GameStartProcessor) InitializeRoomCardPoolsWithSeed(room *types.RoomInfo, seed int64) error {
+	// 创建房间随机源
+	room.SetRandomSeed(seed)
+
+	// 房间持有开局时的卡牌集快照，之后的热加载不影响本局
+	cardSet := cards.CurrentCardSet()
+	room.SetCardSet(cardSet)
+	level1Cards, level2Cards, level3Cards := cardSet.GetCardPools()
 
 	roomManager := service.GetRoomManager()
 	err := roomManager.InitCardPool(room.RoomID, level1Cards, level2Cards, level3Cards)
@@ -73,9 +119,53 @@ func (g *GameStartProcessor) InitializeRoomCardPools(room *types.RoomInfo) error
 		return fmt.Errorf("failed to initialize card pools: %v", err)
 	}
 
+	// 选择了卡组的玩家使用自己的抽牌堆
+	g.InitializePlayerDrawPiles(room, cardSet)
+
 	return nil
 }
 
+// InitializePlayerDrawPiles 为选择了卡组的玩家按卡组创建抽牌堆，按用户名顺序创建，保证相同种子结果一致
+// 卡组不存在或不再符合规则（如卡牌数据已热加载）时该玩家改用共享1级卡牌池
+func (g *GameStartProcessor) InitializePlayerDrawPiles(room *types.RoomInfo, cardSet *models.CardSet) {
+	connManager := service.GetConnectionManager()
+	rules := config.GetDeckRules()
+
+	usernames := room.GetTurnOrder()
+	sort.Strings(usernames)
+	for _, username := range usernames {
+		clientInfo, exists := connManager.GetConnectionByUsername(username)
+		if !exists || clientInfo.GetDeck() == "" {
+			continue
+		}
+		deckName := clientInfo.GetDeck()
+
+		deck, err := service.GetPlayerDeck(username, deckName)
+		if err != nil {
+			log.Printf("Player %s uses the shared pool: failed to load deck %s: %v", username, deckName, err)
+			continue
+		}
+		owned, err := service.GetPlayerCollection(username, nil)
+		if err != nil {
+			log.Printf("Player %s uses the shared pool: failed to load collection: %v", username, err)
+			continue
+		}
+		if err := rules.Validate(deck.Cards, owned, cardSet); err != nil {
+			log.Printf("Player %s uses the shared pool: deck %s is no longer valid: %v", username, deckName, err)
+			continue
+		}
+
+		pile := make([]models.Card, 0, deck.Size)
+		for _, deckCard := range deck.Cards {
+			template, _ := cardSet.GetCardByID(deckCard.CardID)
+			for i := 0; i < deckCard.Quantity; i++ {
+				pile = append(pile, models.NewCard(template.ID, template.Name, template.Damage, template.TargetName, template.Level))
+			}
+		}
+		room.SetPlayerDrawPile(username, pile)
+	}
+}
+
 // AddPlayersToRoom 添加玩家到房间
 func (g *GameStartProcessor) AddPlayersToRoom(room *types.RoomInfo, players []*types.ClientInfo, connManager *service.ConnectionManager) error {
 	roomManager := service.GetRoomManager()
@@ -97,9 +187,15 @@ func (g *GameStartProcessor) AddPlayersToRoom(room *types.RoomInfo, players []*t
 	return nil
 }
 
-// DealInitialCardsToAllPlayers 为房间内所有玩家分发初始手牌
+// DealInitialCardsToAllPlayers 为房间内所有玩家分发初始手牌（按用户名顺序发牌，保证相同种子发牌结果一致）
 func (g *GameStartProcessor) DealInitialCardsToAllPlayers(room *types.RoomInfo) error {
+	usernames := make([]string, 0, len(room.Players))
 	for username := range room.Players {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
 		err := g.DealInitialCardsToPlayer(room, username)
 		if err != nil {
 			return fmt.Errorf("failed to deal cards to player %s: %v", username, err)
@@ -119,11 +215,16 @@ func (g *GameStartProcessor) DealInitialCardsToPlayer(room *types.RoomInfo, user
 	}
 
 	// 初始化玩家手牌
-	// 从房间的1级卡牌池中抽取6张卡牌
-	var initCards []models.Card
-	initCards, _ = roomManager.InitPlayerHandCard(room.RoomID, 6)
+	// 从玩家的抽牌堆（未使用卡组时为房间的1级卡牌池）中抽取房间设置的初始手牌数量
+	initCards, err := roomManager.InitPlayerHandCard(room.RoomID, username, room.OpeningHandSize)
+	if err != nil {
+		return err
+	}
 	player.HandCards = initCards
 
+	// 记录初始手牌
+	GlobalReplayRecorder.RecordDeal(room, username, initCards)
+
 	return nil
 }
 
@@ -139,6 +240,7 @@ func (g *GameStartProcessor) InitializePlayersHealthAndNotify(room *types.RoomIn
 
 	// 设置房间状态为进行中
 	room.Status = "playing"
+	room.MarkMatchStarted()
 
 	// 获取所有羁绊数据
 	// bondPoolManager := cards.GetBondPoolManager()
@@ -169,6 +271,11 @@ func (g *GameStartProcessor) InitializePlayersHealthAndNotify(room *types.RoomIn
 		}
 	}
 
+	// 记录先手玩家
+	if len(playerUsernames) > 0 {
+		GlobalReplayRecorder.RecordFirstTurn(room, playerUsernames[0])
+	}
+
 	GlobalRoomTimerProcessor.StartRoomTimer(room.RoomID)
 
 	return nil
@@ -197,48 +304,131 @@ func (g *GameStartProcessor) SendGameStartNotification(room *types.RoomInfo, pla
 	return nil
 }
 
-// performMatchmaking 执行匹配逻辑
-func (g *GameStartProcessor) performMatchmaking() error {
-	// 获取连接管理器
+// StartMatchWithSeed 使用固定随机种子为指定玩家开始一局游戏，用于测试环境复现发牌和合成结果
+func (g *GameStartProcessor) StartMatchWithSeed(players []*types.ClientInfo, seed int64) (*types.RoomInfo, error) {
+	return g.startMatch(players, &seed)
+}
+
+// startMatch 为指定玩家创建房间并开始游戏，seed为空时按默认规则生成随机种子
+func (g *GameStartProcessor) startMatch(selectedPlayers []*types.ClientInfo, seed *int64) (*types.RoomInfo, error) {
+	connManager := service.GetConnectionManager()
+
+	// 创建新房间
+	room, err := g.CreateGameRoom(fmt.Sprintf("Game Room %d", time.Now().Unix()), len(selectedPlayers))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create room: %v", err)
+	}
+
+	// 添加玩家到房间
+	if err := g.AddPlayersToRoom(room, selectedPlayers, connManager); err != nil {
+		g.CleanupRoom(room.RoomID)
+		return nil, fmt.Errorf("failed to add players to room: %v", err)
+	}
+
+	if err := g.startRoomMatch(room, selectedPlayers, seed); err != nil {
+		g.CleanupRoom(room.RoomID)
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// StartPrivateRoom 按座位顺序为私人房间内的玩家开始游戏，开始失败时房间恢复为等待状态
+func (g *GameStartProcessor) StartPrivateRoom(roomID string) error {
 	connManager := service.GetConnectionManager()
+	roomManager := service.GetRoomManager()
 
-	// 获取准备就绪的玩家
-	readyPlayers := connManager.GetConnectionsByStatus(types.StatusReady)
-	if len(readyPlayers) < 2 {
-		return fmt.Errorf("insufficient ready players: %d", len(readyPlayers))
+	room, err := roomManager.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+	if !room.TransitionStatus("waiting", "ready") {
+		return fmt.Errorf("room %s has already started", roomID)
 	}
 
+	// 收集房间内玩家的连接
+	players := make([]*types.ClientInfo, 0, len(room.Players))
+	for _, username := range room.GetTurnOrder() {
+		clientInfo, exists := connManager.GetConnectionByUsername(username)
+		if !exists || clientInfo.GetGameRoom() != roomID || clientInfo.GetStatus() != types.StatusInRoom {
+			room.UpdateRoomStatus("waiting")
+			return fmt.Errorf("player %s is not waiting in room %s", username, roomID)
+		}
+		players = append(players, clientInfo)
+	}
+	if len(players) < models.MinRoomSize {
+		room.UpdateRoomStatus("waiting")
+		return fmt.Errorf("room %s needs at least %d players", roomID, models.MinRoomSize)
+	}
+
+	for _, player := range players {
+		connManager.SetPlayerStatus(player.ClientID, types.StatusInGame)
+	}
 
-	// 选择前两位玩家进行匹配
-	selectedPlayers := readyPlayers[:2]
+	// 管理员创建房间时指定的固定随机种子
+	var seed *int64
+	if fixedSeed := room.GetFixedSeed(); fixedSeed != 0 {
+		seed = &fixedSeed
+	}
 
-	// 创建新房间
-	room, err := g.CreateGameRoom(fmt.Sprintf("Game Room %d", time.Now().Unix()), 2)
+	if err := g.startRoomMatch(room, players, seed); err != nil {
+		room.UpdateRoomStatus("waiting")
+		for _, player := range players {
+			connManager.SetPlayerStatus(player.ClientID, types.StatusInRoom)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// startPractice 开始人机练习并向玩家发送练习信息 (消息码2401)，失败时发送消息码2403
+func (g *GameStartProcessor) startPractice(clientID, level string, roomSize int, seed int64) error {
+	practice, err := GlobalBotProcessor.StartPractice(clientID, level, roomSize, seed)
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByClientID(clientID)
+	if !exists || clientInfo.Conn == nil {
+		return err
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create room: %v", err)
+		g.sendTCPResponse(clientInfo.Conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(2403))
+		return err
 	}
 
+	g.sendTCPResponse(clientInfo.Conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(2401, practice))
+	return nil
+}
+
+// startRoomMatch 在已加入玩家的房间中开始游戏：初始化卡牌池、发牌并通知玩家
+func (g *GameStartProcessor) startRoomMatch(room *types.RoomInfo, selectedPlayers []*types.ClientInfo, seed *int64) error {
+	connManager := service.GetConnectionManager()
+
 	// 初始化房间卡牌池
-	if err := g.InitializeRoomCardPools(room); err != nil {
-		g.CleanupRoom(room.RoomID)
+	var err error
+	if seed != nil {
+		err = g.InitializeRoomCardPoolsWithSeed(room, *seed)
+	} else {
+		err = g.InitializeRoomCardPools(room)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to initialize room card pools: %v", err)
 	}
 
-	// 添加玩家到房间
-	if err := g.AddPlayersToRoom(room, selectedPlayers, connManager); err != nil {
-		g.CleanupRoom(room.RoomID)
-		return fmt.Errorf("failed to add players to room: %v", err)
+	// 记录房间初始数据（发牌前的完整卡牌池）
+	playerUsernames := make([]string, 0, len(selectedPlayers))
+	for _, player := range selectedPlayers {
+		if player.Username != "" {
+			playerUsernames = append(playerUsernames, player.Username)
+		}
 	}
+	GlobalReplayRecorder.RecordGameStart(room, playerUsernames)
 
 	// 为所有玩家分发初始手牌
 	if err := g.DealInitialCardsToAllPlayers(room); err != nil {
-		g.CleanupRoom(room.RoomID)
 		return fmt.Errorf("failed to deal initial cards: %v", err)
 	}
 
 	// 设置玩家初始信息并发送游戏开始通知
 	if err := g.InitializePlayersHealthAndNotify(room, selectedPlayers, connManager); err != nil {
-		g.CleanupRoom(room.RoomID)
 		return fmt.Errorf("failed to initialize players and send notifications: %v", err)
 	}
 
@@ -246,13 +436,6 @@ func (g *GameStartProcessor) performMatchmaking() error {
 }
 
 // sendTCPResponse 发送TCP响应消息
-func (g *GameStartProcessor) sendTCPResponse(conn net.Conn, resp *models.TcpResponse) {
-	jsonBytes, err := json.Marshal(resp)
-	if err != nil {
-		return
-	}
-	jsonBytes = append(jsonBytes, '\n')
-	_, err = conn.Write(jsonBytes)
-	if err != nil {
-	}
+func (g *GameStartProcessor) sendTCPResponse(conn protocol.Conn, resp *models.TcpResponse) {
+	protocol.WriteJSON(conn, resp)
 }
@@ -0,0 +1,116 @@
+package logic
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"GoServer/tcpgameserver/cards"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/service"
+)
+
+// seededDeal 固定种子开局后的发牌结果（卡牌ID）
+type seededDeal struct {
+	Hands map[string][]int
+	Pool  []int
+}
+
+// activateTestCardSet 激活16张不同ID的1级卡牌组成的卡牌集
+func activateTestCardSet(t *testing.T) {
+	t.Helper()
+
+	decks := make([]models.CardDeck, 0, 16)
+	for id := 1; id <= 16; id++ {
+		decks = append(decks, models.CardDeck{ID: id, Name: fmt.Sprintf("Card%02d", id), CardsNum: 1, Damage: float64(id), Level: 1})
+	}
+	cardSet, err := cards.BuildCardSet(decks, nil, nil)
+	if err != nil {
+		t.Fatalf("BuildCardSet: %v", err)
+	}
+	cards.ActivateCardSet(cardSet)
+}
+
+// dealWithSeed 使用固定种子初始化房间卡牌池并为两名玩家发放初始手牌
+func dealWithSeed(t *testing.T, seed int64) seededDeal {
+	t.Helper()
+
+	g := NewGameStartProcessor()
+	room, err := g.CreateGameRoom("Seed Test", 2)
+	if err != nil {
+		t.Fatalf("CreateGameRoom: %v", err)
+	}
+	defer g.CleanupRoom(room.RoomID)
+
+	for _, username := range []string{"bob", "alice"} {
+		if err := service.GetRoomManager().JoinRoom(room.RoomID, username); err != nil {
+			t.Fatalf("JoinRoom %s: %v", username, err)
+		}
+	}
+	if err := g.InitializeRoomCardPoolsWithSeed(room, seed); err != nil {
+		t.Fatalf("InitializeRoomCardPoolsWithSeed: %v", err)
+	}
+	if err := g.DealInitialCardsToAllPlayers(room); err != nil {
+		t.Fatalf("DealInitialCardsToAllPlayers: %v", err)
+	}
+
+	deal := seededDeal{Hands: make(map[string][]int)}
+	for username, player := range room.Players {
+		deal.Hands[username] = cardIDs(player.HandCards)
+	}
+	deal.Pool = cardIDs(room.Level1CardPool)
+	return deal
+}
+
+func cardIDs(cardList []models.Card) []int {
+	ids := make([]int, 0, len(cardList))
+	for _, card := range cardList {
+		ids = append(ids, card.ID)
+	}
+	return ids
+}
+
+func TestFixedSeedDeal(t *testing.T) {
+	t.Setenv("REPLAY_DIR", t.TempDir())
+	activateTestCardSet(t)
+
+	tests := []struct {
+		seed int64
+		want seededDeal
+	}{
+		{
+			seed: 42,
+			want: seededDeal{
+				Hands: map[string][]int{
+					"alice": {2, 4, 7, 14, 11, 5},
+					"bob":   {13, 16, 1, 9, 6, 10},
+				},
+				Pool: []int{3, 8, 12, 15},
+			},
+		},
+		{
+			seed: 7,
+			want: seededDeal{
+				Hands: map[string][]int{
+					"alice": {15, 1, 11, 9, 12, 14},
+					"bob":   {4, 7, 2, 6, 8, 5},
+				},
+				Pool: []int{3, 10, 13, 16},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("seed_%d", tt.seed), func(t *testing.T) {
+			got := dealWithSeed(t, tt.seed)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("deal with seed %d = %+v, want %+v", tt.seed, got, tt.want)
+			}
+
+			// 相同种子再次开局得到相同的手牌和卡牌池顺序
+			if again := dealWithSeed(t, tt.seed); !reflect.DeepEqual(again, got) {
+				t.Errorf("second deal with seed %d = %+v, want %+v", tt.seed, again, got)
+			}
+		})
+	}
+}
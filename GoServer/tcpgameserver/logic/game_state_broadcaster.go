@@ -1,11 +1,9 @@
 package logic
 
 import (
-	"encoding/json"
-	"net"
-
 	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
@@ -20,21 +18,9 @@ func NewGameStateBroadcaster() *GameStateBroadcaster {
 }
 
 // SendTCPMessage 发送TCP消息到连接
-func (gsb *GameStateBroadcaster) SendTCPMessage(conn net.Conn, response *models.TcpResponse) error {
-	jsonBytes, err := json.Marshal(response)
-	if err != nil {
-		return err
-	}
-
-	// 添加换行符作为消息结束标识
-	jsonBytes = append(jsonBytes, '\n')
-
-	_, err = conn.Write(jsonBytes)
-	if err != nil {
-		return err
-	}
-
-	return nil
+func (gsb *GameStateBroadcaster) SendTCPMessage(conn protocol.Conn, response *models.TcpResponse) error {
+	// 按连接协商的帧模式编码并发送
+	return protocol.WriteJSON(conn, response)
 }
 
 // BroadcastGameStateToRoom 向房间内所有玩家广播游戏状态（统一方法）
@@ -101,6 +87,9 @@ func (gsb *GameStateBroadcaster) BroadcastGameStateToRoom(eventData *events.Even
 		}
 	}
 
+	// 向观战者推送公开信息（需在重置伤害信息前生成）
+	GlobalSpectatorProcessor.BroadcastView(room)
+
 	// 在广播完成后重置所有玩家的战斗统计信息
 	gsb.resetPlayerBattleStats(room)
 
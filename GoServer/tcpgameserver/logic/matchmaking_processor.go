@@ -0,0 +1,273 @@
+package logic
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// 匹配与评分配置
+const (
+	MatchmakingTickInterval = time.Second // 匹配检查间隔
+	EloKFactorProvisional   = 40.0        // 定级期（前30局）的K值
+	EloKFactor              = 20.0        // 定级后的K值
+	eloProvisionalGames     = 30          // 定级局数
+)
+
+// MatchmakingProcessor 匹配处理器，定时从匹配队列中配对玩家并开始游戏
+type MatchmakingProcessor struct {
+	Name       string
+	queue      *service.MatchmakingQueue
+	matchMutex sync.Mutex // 保证同一时间只有一次配对
+	stopChan   chan struct{}
+	running    bool
+	stateMutex sync.Mutex
+}
+
+// NewMatchmakingProcessor 创建新的匹配处理器
+func NewMatchmakingProcessor() *MatchmakingProcessor {
+	return &MatchmakingProcessor{
+		Name:  "MatchmakingProcessor",
+		queue: service.GetMatchmakingQueue(),
+	}
+}
+
+// 全局匹配处理器实例
+var GlobalMatchmakingProcessor = NewMatchmakingProcessor()
+
+// Start 启动定时匹配
+func (mp *MatchmakingProcessor) Start() {
+	mp.stateMutex.Lock()
+	defer mp.stateMutex.Unlock()
+
+	if mp.running {
+		return
+	}
+	mp.running = true
+	mp.stopChan = make(chan struct{})
+
+	go func(stopChan chan struct{}) {
+		ticker := time.NewTicker(MatchmakingTickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				mp.TryMatch()
+			case <-stopChan:
+				return
+			}
+		}
+	}(mp.stopChan)
+}
+
+// Stop 停止定时匹配
+func (mp *MatchmakingProcessor) Stop() {
+	mp.stateMutex.Lock()
+	defer mp.stateMutex.Unlock()
+
+	if !mp.running {
+		return
+	}
+	mp.running = false
+	close(mp.stopChan)
+}
+
+// Enqueue 将准备就绪的玩家加入指定人数的匹配队列并推送排队状态
+func (mp *MatchmakingProcessor) Enqueue(clientID string, roomSize int) error {
+	if roomSize < models.MinRoomSize || roomSize > models.MaxRoomSize {
+		return fmt.Errorf("invalid room size: %d", roomSize)
+	}
+
+	connManager := service.GetConnectionManager()
+	clientInfo, exists := connManager.GetConnectionByClientID(clientID)
+	if !exists || !clientInfo.IsLoggedIn {
+		return fmt.Errorf("client %s is not logged in", clientID)
+	}
+
+	// 读取评分失败时使用初始评分，不阻塞排队
+	rating := models.DefaultPlayerRating
+	if playerRating, err := service.GetPlayerRating(clientInfo.Username); err == nil {
+		rating = playerRating.Rating
+	}
+
+	// 已在队列中时保留原入队时间，通知玩家入队失败 (消息码1406)
+	err := mp.queue.Enqueue(&service.QueueEntry{
+		ClientID:   clientID,
+		Username:   clientInfo.Username,
+		Rating:     rating,
+		RoomSize:   roomSize,
+		EnqueuedAt: time.Now(),
+	})
+	if err != nil {
+		if clientInfo.Conn != nil {
+			protocol.WriteJSON(clientInfo.Conn, tools.GlobalResponseHelper.CreateErrorTcpResponse(1406))
+		}
+		return fmt.Errorf("failed to enqueue player %s: %v", clientInfo.Username, err)
+	}
+	mp.sendQueueStatus(clientInfo)
+
+	mp.TryMatch()
+	return nil
+}
+
+// TryMatch 从队列中组建房间并开始游戏，无法开始的玩家放回队列
+func (mp *MatchmakingProcessor) TryMatch() {
+	mp.matchMutex.Lock()
+	defer mp.matchMutex.Unlock()
+
+	connManager := service.GetConnectionManager()
+	for _, group := range mp.queue.FindMatches(time.Now()) {
+		players := make([]*types.ClientInfo, 0, len(group))
+		usernames := make([]string, 0, len(group))
+		for _, entry := range group {
+			usernames = append(usernames, entry.Username)
+			clientInfo, exists := connManager.GetConnectionByClientID(entry.ClientID)
+			if exists && clientInfo.Username == entry.Username && clientInfo.GetStatus() == types.StatusReady {
+				players = append(players, clientInfo)
+			}
+		}
+
+		if len(players) != len(group) {
+			// 有玩家已离开，其余玩家放回队列
+			for _, entry := range group {
+				if clientInfo, exists := connManager.GetConnectionByClientID(entry.ClientID); exists && clientInfo.GetStatus() == types.StatusReady {
+					mp.queue.Requeue(entry)
+				}
+			}
+			continue
+		}
+
+		processor := NewGameStartProcessor()
+		if _, err := processor.startMatch(players, nil); err != nil {
+			log.Printf("Failed to start match for %s: %v", strings.Join(usernames, ", "), err)
+			for _, entry := range group {
+				mp.queue.Requeue(entry)
+			}
+		}
+	}
+
+	// 等待过久仍未匹配的玩家由机器人补足房间
+	if wait := config.GetBotFillWait(); wait > 0 {
+		mp.fillWithBots(mp.queue.TakeWaiting(time.Now(), wait))
+	}
+}
+
+// fillWithBots 将等待过久的玩家按房间人数分组，每组至少留一个座位由机器人补足后开始游戏，
+// 已离开的玩家不再放回队列，开始失败的玩家放回队列
+func (mp *MatchmakingProcessor) fillWithBots(entries []*service.QueueEntry) {
+	connManager := service.GetConnectionManager()
+	level := config.GetBotFillLevel()
+
+	roomSizes := make([]int, 0)
+	waiting := make(map[int][]*service.QueueEntry)
+	clients := make(map[string]*types.ClientInfo)
+	for _, entry := range entries {
+		clientInfo, exists := connManager.GetConnectionByClientID(entry.ClientID)
+		if !exists || clientInfo.Username != entry.Username || clientInfo.GetStatus() != types.StatusReady {
+			continue
+		}
+		if _, exists := waiting[entry.RoomSize]; !exists {
+			roomSizes = append(roomSizes, entry.RoomSize)
+		}
+		waiting[entry.RoomSize] = append(waiting[entry.RoomSize], entry)
+		clients[entry.ClientID] = clientInfo
+	}
+
+	for _, roomSize := range roomSizes {
+		entries := waiting[roomSize]
+		for start := 0; start < len(entries); start += roomSize - 1 {
+			group := entries[start:min(start+roomSize-1, len(entries))]
+			players := make([]*types.ClientInfo, 0, len(group))
+			usernames := make([]string, 0, len(group))
+			for _, entry := range group {
+				players = append(players, clients[entry.ClientID])
+				usernames = append(usernames, entry.Username)
+			}
+
+			if _, _, err := GlobalBotProcessor.StartBotMatch(players, level, roomSize, 0); err != nil {
+				log.Printf("Failed to start bot-filled match for %s: %v", strings.Join(usernames, ", "), err)
+				for _, entry := range group {
+					mp.queue.Requeue(entry)
+				}
+			}
+		}
+	}
+}
+
+// sendQueueStatus 向玩家推送排队状态 (消息码1401)
+func (mp *MatchmakingProcessor) sendQueueStatus(clientInfo *types.ClientInfo) {
+	status, exists := mp.queue.Status(clientInfo.Username, time.Now())
+	if !exists || clientInfo.Conn == nil {
+		return
+	}
+	protocol.WriteJSON(clientInfo.Conn, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1401, status))
+}
+
+// UpdateRatings 根据对局结果更新参与玩家的评分，winner为空表示平局
+func (mp *MatchmakingProcessor) UpdateRatings(usernames []string, winner string) error {
+	ratings, err := service.GetPlayerRatings(usernames)
+	if err != nil {
+		return err
+	}
+
+	current := make([]models.PlayerRating, 0, len(usernames))
+	for _, username := range usernames {
+		current = append(current, ratings[username])
+	}
+
+	return service.SavePlayerRatings(CalculateEloRatings(current, winner))
+}
+
+// CalculateEloRatings 计算对局后的ELO评分
+// 胜者对每位其他玩家记1分，其余玩家之间记0.5分；多人对局时按对手数平分K值
+func CalculateEloRatings(ratings []models.PlayerRating, winner string) []models.PlayerRating {
+	updated := make([]models.PlayerRating, len(ratings))
+	if len(ratings) < 2 {
+		copy(updated, ratings)
+		return updated
+	}
+
+	opponents := float64(len(ratings) - 1)
+	for i, player := range ratings {
+		delta := 0.0
+		for j, opponent := range ratings {
+			if i == j {
+				continue
+			}
+
+			score := 0.5
+			if player.Username == winner {
+				score = 1
+			} else if opponent.Username == winner {
+				score = 0
+			}
+
+			expected := 1 / (1 + math.Pow(10, (opponent.Rating-player.Rating)/400))
+			delta += score - expected
+		}
+
+		kFactor := EloKFactor
+		if player.GamesPlayed < eloProvisionalGames {
+			kFactor = EloKFactorProvisional
+		}
+
+		updated[i] = models.PlayerRating{
+			Username:    player.Username,
+			Rating:      math.Round((player.Rating+kFactor/opponents*delta)*10) / 10,
+			GamesPlayed: player.GamesPlayed + 1,
+		}
+	}
+
+	return updated
+}
@@ -0,0 +1,224 @@
+package logic
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// PauseProcessor 对局暂停处理器：暂停期间冻结回合计时，到期或由发起者恢复
+type PauseProcessor struct {
+	Name   string
+	pauses map[string]*roomPause // 房间ID -> 暂停中的计时信息
+	mutex  sync.Mutex
+}
+
+// roomPause 暂停中的房间计时信息
+type roomPause struct {
+	resumeTimer *time.Timer   // 暂停到期自动恢复的计时器
+	remaining   time.Duration // 暂停时当前回合剩余的时间
+}
+
+// NewPauseProcessor 创建新的暂停处理器
+func NewPauseProcessor() *PauseProcessor {
+	return &PauseProcessor{
+		Name:   "PauseProcessor",
+		pauses: make(map[string]*roomPause),
+	}
+}
+
+// 全局暂停处理器实例
+var GlobalPauseProcessor = NewPauseProcessor()
+
+// ProcessPause 处理暂停事件：玩家主动暂停或断线自动暂停，向房间内玩家发送暂停通知 (消息码1801)
+func (pp *PauseProcessor) ProcessPause(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	clientID, _ := eventData.GetString("client_id")
+	username, _ := eventData.GetString("username")
+	reason, exists := eventData.GetString("reason")
+	if !exists || reason == "" {
+		reason = types.PauseReasonPlayer
+	}
+
+	room, err := service.GetRoomManager().GetRoom(eventData.RoomID)
+	if err != nil || room.Status != "playing" {
+		pp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1803))
+		return
+	}
+
+	maxPauses := config.GetMaxPausesPerPlayer()
+	duration, err := room.BeginPause(username, reason, config.GetPauseDuration(), config.GetMaxTotalPause(), maxPauses)
+	if err != nil {
+		pp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(pauseErrorCode(err)))
+		return
+	}
+
+	// 冻结回合计时，到期自动恢复
+	entry := &roomPause{remaining: GlobalRoomTimerProcessor.PauseRoomTimer(room.RoomID)}
+	roomID := room.RoomID
+	pp.mutex.Lock()
+	entry.resumeTimer = time.AfterFunc(duration, func() {
+		pp.resume(roomID, entry, "", types.PauseReasonTimeout)
+	})
+	pp.pauses[roomID] = entry
+	pp.mutex.Unlock()
+
+	notification := map[string]interface{}{
+		"RoomID":          roomID,
+		"PausedBy":        username,
+		"Reason":          reason,
+		"DurationSeconds": int(duration.Seconds()),
+		"TurnSecondsLeft": int(entry.remaining.Seconds()),
+	}
+	if reason == types.PauseReasonPlayer && maxPauses > 0 {
+		notification["PausesLeft"] = maxPauses - room.GetPauseState().PauseCounts[username]
+	}
+	broadcastToRoomPlayers(room, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1801, notification))
+}
+
+// ProcessResume 处理恢复事件：主动暂停只能由发起者提前恢复，断线暂停在该玩家重连时恢复
+func (pp *PauseProcessor) ProcessResume(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	clientID, _ := eventData.GetString("client_id")
+	username, _ := eventData.GetString("username")
+	reason, exists := eventData.GetString("reason")
+	if !exists || reason == "" {
+		reason = types.PauseReasonPlayer
+	}
+
+	room, err := service.GetRoomManager().GetRoom(eventData.RoomID)
+	if err != nil {
+		pp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1803))
+		return
+	}
+
+	// 重连只恢复该玩家断线引起的暂停
+	state := room.GetPauseState()
+	if reason == types.PauseReasonReconnect && state.Reason != types.PauseReasonDisconnect {
+		return
+	}
+	if !state.Paused || state.PausedBy != username {
+		pp.reply(clientID, tools.GlobalResponseHelper.CreateErrorTcpResponse(1807))
+		return
+	}
+
+	pp.resume(room.RoomID, nil, username, reason)
+}
+
+// resume 结束房间暂停并以剩余时间恢复回合计时，向房间内玩家发送恢复通知 (消息码1802)
+// expected不为空时只在该暂停仍有效时恢复，用于到期自动恢复
+func (pp *PauseProcessor) resume(roomID string, expected *roomPause, username, reason string) {
+	pp.mutex.Lock()
+	entry, exists := pp.pauses[roomID]
+	if !exists || (expected != nil && entry != expected) {
+		pp.mutex.Unlock()
+		return
+	}
+	delete(pp.pauses, roomID)
+	pp.mutex.Unlock()
+	entry.resumeTimer.Stop()
+
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil {
+		return
+	}
+	if _, err := room.EndPause(); err != nil {
+		return
+	}
+
+	if err := GlobalRoomTimerProcessor.ResumeRoomTimer(roomID, entry.remaining); err != nil {
+		log.Printf("Failed to resume turn timer for room %s: %v", roomID, err)
+	}
+
+	broadcastToRoomPlayers(room, tools.GlobalResponseHelper.CreateSuccessTcpResponse(1802, map[string]interface{}{
+		"RoomID":    roomID,
+		"ResumedBy": username,
+		"Reason":    reason,
+	}))
+}
+
+// ReleasePlayer 玩家被淘汰时结束由其发起的暂停
+func (pp *PauseProcessor) ReleasePlayer(roomID, username string) {
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil {
+		return
+	}
+	if state := room.GetPauseState(); !state.Paused || state.PausedBy != username {
+		return
+	}
+
+	// 淘汰时可能已切换回合，恢复后重新开始完整的回合计时
+	pp.mutex.Lock()
+	if entry, exists := pp.pauses[roomID]; exists {
+		entry.remaining = 0
+	}
+	pp.mutex.Unlock()
+	pp.resume(roomID, nil, username, types.PauseReasonEliminated)
+}
+
+// ClearRoom 清理房间的暂停计时，对局结束时调用
+func (pp *PauseProcessor) ClearRoom(roomID string) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	if entry, exists := pp.pauses[roomID]; exists {
+		entry.resumeTimer.Stop()
+		delete(pp.pauses, roomID)
+	}
+}
+
+// reply 向请求的客户端发送消息，自动暂停等没有请求客户端时不发送
+func (pp *PauseProcessor) reply(clientID string, response *models.TcpResponse) {
+	if clientID == "" {
+		return
+	}
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByClientID(clientID)
+	if !exists || clientInfo.Conn == nil {
+		return
+	}
+	if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+		log.Printf("Failed to send pause message %s to client %s: %v", response.Code, clientID, err)
+	}
+}
+
+// pauseErrorCode 暂停错误对应的响应码
+func pauseErrorCode(err error) int {
+	switch err {
+	case types.ErrGamePaused:
+		return 1806
+	case types.ErrPauseLimitReached:
+		return 1804
+	case types.ErrPauseTimeExhausted:
+		return 1805
+	default:
+		return 1803
+	}
+}
+
+// broadcastToRoomPlayers 向房间内所有在线玩家发送消息
+func broadcastToRoomPlayers(room *types.RoomInfo, response *models.TcpResponse) {
+	connManager := service.GetConnectionManager()
+	for _, username := range room.GetTurnOrder() {
+		clientInfo, exists := connManager.GetConnectionByUsername(username)
+		if !exists || clientInfo.Conn == nil || clientInfo.GetGameRoom() != room.RoomID {
+			continue
+		}
+		if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+			log.Printf("Failed to send message %s of room %s to %s: %v", response.Code, room.RoomID, username, err)
+		}
+	}
+}
@@ -11,15 +11,22 @@ import (
 
 // PlayCardProcessor 出牌逻辑处理器
 type PlayCardProcessor struct {
-	Name           string
-	bondCalculator *BondCalculator
+	Name       string
+	replayMode bool // 回放模式：不启动计时器、不发布事件、不写回放日志
 }
 
 // NewPlayCardProcessor 创建新的出牌逻辑处理器
 func NewPlayCardProcessor() *PlayCardProcessor {
 	return &PlayCardProcessor{
-		Name:           "PlayCardProcessor",
-		bondCalculator: NewBondCalculator(),
+		Name: "PlayCardProcessor",
+	}
+}
+
+// NewReplayPlayCardProcessor 创建用于回放模拟的出牌逻辑处理器
+func NewReplayPlayCardProcessor() *PlayCardProcessor {
+	return &PlayCardProcessor{
+		Name:       "ReplayPlayCardProcessor",
+		replayMode: true,
 	}
 }
 
@@ -28,7 +35,28 @@ type PlayCardData struct {
 	RoomID      string        `json:"room_id"`
 	Player      string        `json:"player"`
 	CardsToPlay []models.Card `json:"cards_to_play"` // 要出的所有卡牌
-	TargetType  string        `json:"target_type"`
+	TargetType  string        `json:"target_type"` // 目标类型：opponent, all, self
+	Target      string        `json:"target"`      // 目标玩家，TargetType为opponent时有效，为空时指向下一名存活玩家
+	Source      string        `json:"source"`      // 出牌来源：user, timeout, bot
+}
+
+// 出牌目标
+const (
+	PlayTargetAll  = "all"  // 对所有存活的其他玩家造成伤害
+	PlayTargetSelf = "self" // 为自己恢复血量
+)
+
+// ParsePlayTarget 解析客户端传入的出牌目标，返回目标类型和目标玩家
+// 目标可以是玩家用户名、all 或 self，为空时对下一名存活玩家造成伤害
+func ParsePlayTarget(target string) (string, string) {
+	switch target {
+	case PlayTargetAll:
+		return "all", ""
+	case PlayTargetSelf:
+		return "self", ""
+	default:
+		return "opponent", target
+	}
 }
 
 // ProcessPlayCard 处理出牌逻辑
@@ -41,69 +69,182 @@ func (p *PlayCardProcessor) ProcessPlayCard(eventData *events.EventData) {
 	roomID, _ := eventData.GetString("room_id")
 	// 获取玩家发送的自身卡牌数据
 	selfCardsData, _ := eventData.GetData("self_cards")
+	// 获取出牌目标
+	target, _ := eventData.GetString("target")
 
 	// 转换为卡牌切片
 	receivedSelfCards, _ := selfCardsData.([]models.Card)
 
 	// 构建出牌数据（所有验证交给ProcessPlayCard处理）
+	targetType, targetPlayer := ParsePlayTarget(target)
 	data := &PlayCardData{
 		RoomID:      roomID,
 		Player:      player,
 		CardsToPlay: receivedSelfCards,
-		TargetType:  "opponent",
+		TargetType:  targetType,
+		Target:      targetPlayer,
+		Source:      "user",
 	}
-
-	// 步骤1: 验证出牌信息是否正确
-	room, validatedCards, err := p.validatePlayCardRequest(data)
-	if err != nil {
-		return
+	// 机器人出牌时指定出牌来源
+	if source, exists := eventData.GetString("source"); exists && source != "" {
+		data.Source = source
 	}
 
-	// 步骤2: 计算羁绊伤害加成，得到伤害结果和触发羁绊
-	bondResult := p.bondCalculator.CalculateBondDamage(validatedCards)
-
-	// 步骤3: 为房间内玩家更新信息（血量、收到伤害、造成伤害等）并为出牌方抽取新卡牌
-	gameEnded, err := p.updateRoomPlayersInfo(room, data.Player, bondResult.TotalDamage, data.TargetType, &bondResult, validatedCards)
+	// 步骤1-3: 验证出牌、计算羁绊伤害并更新房间内玩家信息
+	room, gameEnded, err := p.ExecutePlayCard(data)
 	if err != nil {
 		return
 	}
 
+	// 玩家主动出牌，重置其连续超时次数
+	room.ResetPlayerTimeouts(data.Player)
+
 	// 步骤4: 发送游戏状态更新事件（仅在游戏未结束时）
 	if !gameEnded {
 		p.publishGameStateUpdateWithBonds(room)
 	}
 }
 
-// validatePlayCardRequest 验证出牌请求信息
-func (p *PlayCardProcessor) validatePlayCardRequest(data *PlayCardData) (*types.RoomInfo, []models.Card, error) {
-	// 获取房间管理器
-	roomManager := service.GetRoomManager()
-
+// ExecutePlayCard 执行出牌逻辑（验证、计算羁绊伤害、更新玩家信息），不发布状态更新
+// 返回值：(room, gameEnded, error) - gameEnded表示游戏是否结束
+func (p *PlayCardProcessor) ExecutePlayCard(data *PlayCardData) (*types.RoomInfo, bool, error) {
 	// 获取房间信息
+	roomManager := service.GetRoomManager()
 	room, err := roomManager.GetRoom(data.RoomID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get room %s: %v", data.RoomID, err)
+		return nil, false, fmt.Errorf("failed to get room %s: %v", data.RoomID, err)
+	}
+
+	_, gameEnded, err := p.executePlayCardOnRoom(room, data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return room, gameEnded, nil
+}
+
+// executePlayCardOnRoom 在指定房间上执行出牌逻辑，返回羁绊计算结果
+func (p *PlayCardProcessor) executePlayCardOnRoom(room *types.RoomInfo, data *PlayCardData) (*BondCalculationResult, bool, error) {
+	// 步骤1: 验证出牌信息是否正确
+	validatedCards, err := p.validatePlayCardRequest(room, data)
+	if err != nil {
+		return nil, false, err
 	}
 
+	// 记录出牌前的血量和手牌，用于回放日志
+	healthBefore := room.GetPlayersHealth()
+	handBefore, _ := room.GetPlayerHandCards(data.Player)
+
+	// 步骤2: 按房间开局的卡牌集计算羁绊伤害加成，得到伤害结果和触发羁绊
+	bondResult := NewRoomBondCalculator(room).CalculateBondDamage(validatedCards)
+
+	// 步骤3: 为房间内玩家更新信息（血量、收到伤害、造成伤害等）并为出牌方抽取新卡牌
+	gameEnded, err := p.updateRoomPlayersInfo(room, data.Player, bondResult.TotalDamage, data.TargetType, data.Target, &bondResult, validatedCards)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if p.replayMode {
+		return &bondResult, gameEnded, nil
+	}
+
+	// 步骤4: 写入回放日志（先于游戏结束事件，保证日志顺序）
+	GlobalReplayRecorder.RecordPlayCard(room, data, validatedCards, &bondResult, healthBefore, handBefore)
+
+	if gameEnded {
+		p.publishGameEnd(room)
+	}
+
+	return &bondResult, gameEnded, nil
+}
+
+// PassTurn 跳过当前玩家的回合（不出牌、不抽牌）
+// 返回值：gameEnded表示下一名玩家回合开始时结算持续伤害后游戏是否结束
+func (p *PlayCardProcessor) PassTurn(room *types.RoomInfo, playerName string) (bool, error) {
+	playerInfo, err := room.GetPlayerInfo(playerName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get player info for %s: %v", playerName, err)
+	}
+
+	if playerInfo.Round != "current" {
+		return false, fmt.Errorf("it's not player %s's turn (round status: %s)", playerName, playerInfo.Round)
+	}
+
+	gameEnded, err := p.switchToNextPlayer(room, playerName)
+	if err != nil {
+		return false, err
+	}
+
+	if p.replayMode {
+		return gameEnded, nil
+	}
+
+	GlobalReplayRecorder.RecordPassTurn(room, playerName)
+	if gameEnded {
+		p.publishGameEnd(room)
+	}
+	return gameEnded, nil
+}
+
+// EliminatePlayer 淘汰玩家（血量置零），被淘汰的玩家为当前回合玩家时切换到下一名存活玩家
+// 返回值：gameEnded表示淘汰后是否只剩一名存活玩家，此时由游戏结束流程结算，不切换回合
+func (p *PlayCardProcessor) EliminatePlayer(room *types.RoomInfo, playerName, reason string) (bool, error) {
+	if err := room.SetPlayerHealth(playerName, 0); err != nil {
+		return false, err
+	}
+
+	if p.checkGameEnd(room) {
+		return true, nil
+	}
+
+	if currentPlayer, exists := room.GetCurrentPlayer(); exists && currentPlayer == playerName {
+		gameEnded, err := p.switchToNextPlayer(room, playerName)
+		if err != nil {
+			return false, err
+		}
+		if gameEnded {
+			return true, nil
+		}
+	}
+
+	if !p.replayMode {
+		GlobalReplayRecorder.RecordEliminate(room, playerName, reason)
+	}
+	return false, nil
+}
+
+// validatePlayCardRequest 验证出牌请求信息
+func (p *PlayCardProcessor) validatePlayCardRequest(room *types.RoomInfo, data *PlayCardData) ([]models.Card, error) {
 	// 验证房间状态
 	if room.Status != "playing" {
-		return nil, nil, fmt.Errorf("room %s is not in playing state: %s", data.RoomID, room.Status)
+		return nil, fmt.Errorf("room %s is not in playing state: %s", data.RoomID, room.Status)
+	}
+	if room.IsPaused() {
+		return nil, fmt.Errorf("room %s is paused", data.RoomID)
 	}
 
 	// 获取玩家信息
 	playerInfo, err := room.GetPlayerInfo(data.Player)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get player info for %s: %v", data.Player, err)
+		return nil, fmt.Errorf("failed to get player info for %s: %v", data.Player, err)
 	}
 
 	// 验证玩家回合状态
 	if playerInfo.Round != "current" {
-		return nil, nil, fmt.Errorf("it's not player %s's turn (round status: %s)", data.Player, playerInfo.Round)
+		return nil, fmt.Errorf("it's not player %s's turn (round status: %s)", data.Player, playerInfo.Round)
+	}
+	if playerInfo.CurrentHealth <= 0 {
+		return nil, fmt.Errorf("player %s has been eliminated", data.Player)
+	}
+
+	// 验证出牌目标
+	if err := p.resolvePlayTarget(room, data); err != nil {
+		return nil, err
 	}
 
 	// 验证是否有卡牌要出
 	if len(data.CardsToPlay) == 0 {
-		return nil, nil, fmt.Errorf("no cards to play for player %s", data.Player)
+		return nil, fmt.Errorf("no cards to play for player %s", data.Player)
 	}
 	// 构建手牌UID映射用于快速查找和验证
 	handCardMap := make(map[string]models.Card)
@@ -117,12 +258,12 @@ func (p *PlayCardProcessor) validatePlayCardRequest(data *PlayCardData) (*types.
 		if handCard, exists := handCardMap[cardToPlay.UID]; exists {
 			// 验证卡牌详细信息匹配
 			if handCard.Name != cardToPlay.Name || handCard.ID != cardToPlay.ID {
-				return nil, nil, fmt.Errorf("card information mismatch for UID %s: expected %s (ID: %d), got %s (ID: %d)",
+				return nil, fmt.Errorf("card information mismatch for UID %s: expected %s (ID: %d), got %s (ID: %d)",
 					cardToPlay.UID, handCard.Name, handCard.ID, cardToPlay.Name, cardToPlay.ID)
 			}
 			validatedCards = append(validatedCards, cardToPlay)
 		} else {
-			return nil, nil, fmt.Errorf("card UID %s not found in player %s's hand", cardToPlay.UID, data.Player)
+			return nil, fmt.Errorf("card UID %s not found in player %s's hand", cardToPlay.UID, data.Player)
 		}
 	}
 
@@ -130,105 +271,137 @@ func (p *PlayCardProcessor) validatePlayCardRequest(data *PlayCardData) (*types.
 	uidSet := make(map[string]bool)
 	for _, card := range validatedCards {
 		if uidSet[card.UID] {
-			return nil, nil, fmt.Errorf("duplicate card UID %s in play request", card.UID)
+			return nil, fmt.Errorf("duplicate card UID %s in play request", card.UID)
 		}
 		uidSet[card.UID] = true
 	}
 
-	return room, validatedCards, nil
+	return validatedCards, nil
 }
 
-// updateRoomPlayersInfo 更新房间内玩家信息（血量、伤害统计、羁绊、移除卡牌、切换回合、抽取新卡牌等）
+// resolvePlayTarget 验证出牌目标，未指定目标玩家时使用座位顺序中的下一名存活玩家
+func (p *PlayCardProcessor) resolvePlayTarget(room *types.RoomInfo, data *PlayCardData) error {
+	switch data.TargetType {
+	case "all", "self":
+		data.Target = ""
+		return nil
+	case "opponent":
+	default:
+		return fmt.Errorf("invalid target type %s", data.TargetType)
+	}
+
+	if data.Target == "" {
+		nextPlayer, exists := room.GetNextAlivePlayer(data.Player)
+		if !exists {
+			return fmt.Errorf("opponent not found for player %s", data.Player)
+		}
+		data.Target = nextPlayer
+		return nil
+	}
+
+	if data.Target == data.Player {
+		return fmt.Errorf("player %s cannot target themselves with damage", data.Player)
+	}
+	if _, err := room.GetPlayerInfo(data.Target); err != nil {
+		return fmt.Errorf("target %s not found in room %s", data.Target, room.RoomID)
+	}
+	if !room.IsPlayerAlive(data.Target) {
+		return fmt.Errorf("target %s has been eliminated", data.Target)
+	}
+	return nil
+}
+
+// updateRoomPlayersInfo 更新房间内玩家信息（血量、伤害统计、羁绊、移除卡牌、羁绊技能、切换回合、抽取新卡牌等）
 // 返回值：(gameEnded bool, error) - gameEnded表示游戏是否结束
-func (p *PlayCardProcessor) updateRoomPlayersInfo(room *types.RoomInfo, playerName string, totalDamage float64, targetType string, bondResult *BondCalculationResult, playedCards []models.Card) (bool, error) { // 1. 根据目标类型执行伤害效果
-	err := p.executeCardEffectWithBondDamage(room, playerName, totalDamage, targetType, bondResult)
+func (p *PlayCardProcessor) updateRoomPlayersInfo(room *types.RoomInfo, playerName string, totalDamage float64, targetType, targetPlayer string, bondResult *BondCalculationResult, playedCards []models.Card) (bool, error) { // 1. 根据目标类型执行伤害效果，AOE目标在伤害结算前确定，被本次AOE淘汰的玩家仍计入伤害信息
+	aoeTargets := p.aoeTargets(room, playerName)
+	err := p.executeCardEffectWithBondDamage(room, playerName, targetPlayer, totalDamage, targetType, bondResult, aoeTargets)
 	if err != nil {
 		return false, fmt.Errorf("failed to execute card effect: %v", err)
 	}
 
-	// 2. 更新玩家战斗统计数据
-	err = p.updatePlayerBattleStats(room, playerName, totalDamage, targetType, bondResult)
-	if err != nil {
-		return false, fmt.Errorf("failed to update battle stats: %v", err)
-	}
-	// 3. 从手牌中移除出的卡牌
+	// 2. 从手牌中移除出的卡牌并放入弃牌堆
 	var cardUIDs []string
 	for _, card := range playedCards {
 		cardUIDs = append(cardUIDs, card.UID)
 	}
-	err = room.RemoveCardsFromPlayerByUID(playerName, cardUIDs)
+	err = room.DiscardCardsFromPlayerByUID(playerName, cardUIDs)
 	if err != nil {
 		return false, fmt.Errorf("failed to remove cards from player %s: %v", playerName, err)
 	}
 
-	// 4. 切换到下一个玩家的回合
-	err = p.switchToNextPlayer(room, playerName)
+	// 3. 伤害结算后执行触发羁绊的技能效果
+	skillTargets := p.skillTargets(room, playerName, targetType, targetPlayer)
+	effects, extraTurn := p.applyBondSkills(room, playerName, skillTargets, bondResult)
+
+	// 4. 更新玩家战斗统计数据
+	err = p.updatePlayerBattleStats(room, playerName, targetPlayer, aoeTargets, totalDamage, targetType, bondResult, effects)
 	if err != nil {
-		return false, fmt.Errorf("failed to switch to next player: %v", err)
-	} // 5. 检查游戏是否结束
+		return false, fmt.Errorf("failed to update battle stats: %v", err)
+	}
+
+	// 5. 检查游戏是否结束（只剩一名存活玩家）
 	gameEnded := p.checkGameEnd(room)
 	if gameEnded {
 		// 游戏已结束，不再执行后续逻辑
 		return true, nil
 	}
 
-	// 6. 为出牌方新增三张卡牌（如果游戏仍在进行）
+	// 6. 切换到下一名存活玩家的回合，获得额外回合时继续由出牌方行动
+	if extraTurn {
+		p.grantExtraTurn(room)
+	} else {
+		gameEnded, err = p.switchToNextPlayer(room, playerName)
+		if err != nil {
+			return false, fmt.Errorf("failed to switch to next player: %v", err)
+		}
+		if gameEnded {
+			return true, nil
+		}
+	}
+
+	// 6. 为出牌方新增三张卡牌（如果游戏仍在进行），无牌可抽的疲劳伤害可能结束游戏
 	if room.Status == "playing" {
 		err = p.drawCardsForPlayer(room, playerName, 3)
 		if err != nil {
 		}
+		if p.checkGameEnd(room) {
+			return true, nil
+		}
 	}
 
 	return false, nil
 }
 
 // executeCardEffectWithBondDamage 使用羁绊计算后的伤害执行效果
-func (p *PlayCardProcessor) executeCardEffectWithBondDamage(room *types.RoomInfo, playerName string, totalDamage float64, targetType string, bondResult *BondCalculationResult) error {
+func (p *PlayCardProcessor) executeCardEffectWithBondDamage(room *types.RoomInfo, playerName, targetPlayer string, totalDamage float64, targetType string, bondResult *BondCalculationResult, aoeTargets []string) error {
 	switch targetType {
 	case "opponent":
-		return p.applyDamageToOpponent(room, playerName, totalDamage)
+		return p.applyDamageToOpponent(room, playerName, targetPlayer, totalDamage)
 	case "self":
 		return p.applySelfHeal(room, playerName, totalDamage)
 	case "all":
-		return p.applyAOEDamage(room, playerName, totalDamage)
+		return p.applyAOEDamage(room, playerName, aoeTargets, totalDamage)
 	default:
 		// 默认对对手造成伤害
-		return p.applyDamageToOpponent(room, playerName, totalDamage)
+		return p.applyDamageToOpponent(room, playerName, targetPlayer, totalDamage)
 	}
 }
 
-// applyDamageToOpponent 对对手造成伤害
-func (p *PlayCardProcessor) applyDamageToOpponent(room *types.RoomInfo, playerName string, damage float64) error {
-	// 获取对手名称
-	var opponentName string
-	for _, player := range room.Players {
-		if player.Username != playerName {
-			opponentName = player.Username
-			break
-		}
-	}
-
+// applyDamageToOpponent 对目标对手造成伤害
+func (p *PlayCardProcessor) applyDamageToOpponent(room *types.RoomInfo, playerName, opponentName string, damage float64) error {
 	if opponentName == "" {
 		return fmt.Errorf("opponent not found for player %s", playerName)
 	}
 
-	// 获取对手当前血量
-	currentHealth, err := room.GetPlayerCurrentHealth(opponentName)
+	// 扣除对手血量，护盾优先抵消伤害
+	dealt, _, err := room.ApplyDamage(opponentName, damage)
 	if err != nil {
-		return fmt.Errorf("failed to get opponent health: %v", err)
-	}
-
-	// 计算伤害后的血量
-	newHealth := currentHealth - damage
-	if newHealth < 0 {
-		newHealth = 0
+		return fmt.Errorf("failed to apply damage to opponent: %v", err)
 	}
 
-	// 设置对手新血量
-	err = room.SetPlayerHealth(opponentName, newHealth)
-	if err != nil {
-		return fmt.Errorf("failed to set opponent health: %v", err)
-	}
+	// 记录实际造成的伤害
+	room.RecordDamage(playerName, opponentName, dealt)
 
 	return nil
 }
@@ -261,75 +434,149 @@ func (p *PlayCardProcessor) applySelfHeal(room *types.RoomInfo, playerName strin
 	return nil
 }
 
-// applyAOEDamage 对所有玩家应用AOE伤害
-func (p *PlayCardProcessor) applyAOEDamage(room *types.RoomInfo, playerName string, damage float64) error {
-	// 对所有玩家造成伤害（AOE效果）
-	for _, player := range room.Players {
-		if player.Username != playerName { // 通常AOE不影响施法者
-			// 获取玩家当前血量
-			currentHealth, err := room.GetPlayerCurrentHealth(player.Username)
-			if err != nil {
-				continue
-			}
-
-			// 计算伤害后的血量
-			newHealth := currentHealth - damage
-			if newHealth < 0 {
-				newHealth = 0
-			}
+// aoeTargets 获取AOE伤害的目标：除出牌方外的所有存活玩家（按座位顺序）
+func (p *PlayCardProcessor) aoeTargets(room *types.RoomInfo, playerName string) []string {
+	targets := make([]string, 0, len(room.Players))
+	for _, username := range room.GetAlivePlayers() {
+		if username != playerName {
+			targets = append(targets, username)
+		}
+	}
+	return targets
+}
 
-			// 设置新血量
-			err = room.SetPlayerHealth(player.Username, newHealth)
-			if err != nil {
-				continue
-			}
+// applyAOEDamage 对AOE目标应用伤害
+func (p *PlayCardProcessor) applyAOEDamage(room *types.RoomInfo, playerName string, targets []string, damage float64) error {
+	for _, target := range targets {
+		// 扣除血量，护盾优先抵消伤害
+		dealt, _, err := room.ApplyDamage(target, damage)
+		if err != nil {
+			continue
 		}
+
+		// 记录实际造成的伤害
+		room.RecordDamage(playerName, target, dealt)
 	}
 
 	return nil
 }
 
-// switchToNextPlayer 切换到下一个玩家
-func (p *PlayCardProcessor) switchToNextPlayer(room *types.RoomInfo, currentPlayer string) error {
-	// 获取所有玩家
-	players := room.Players
-	if len(players) != 2 {
-		return fmt.Errorf("invalid number of players: %d", len(players))
+// switchToNextPlayer 按座位顺序切换到下一名存活玩家，跳过已淘汰的玩家
+// 下一名玩家回合开始时结算其身上的状态效果，被中毒淘汰或处于眩晕时继续顺延
+// 返回值：gameEnded表示结算状态效果后是否只剩一名存活玩家，此时不再切换回合
+func (p *PlayCardProcessor) switchToNextPlayer(room *types.RoomInfo, currentPlayer string) (bool, error) {
+	if len(room.Players) < 2 {
+		return false, fmt.Errorf("invalid number of players: %d", len(room.Players))
 	}
 
-	// 找到下一个玩家
-	var nextPlayer string
-	for _, player := range players {
-		if player.Username != currentPlayer {
-			nextPlayer = player.Username
+	// 找到下一名存活玩家
+	nextPlayer := currentPlayer
+	for {
+		candidate, exists := room.GetNextAlivePlayer(nextPlayer)
+		if !exists {
+			return false, fmt.Errorf("next player not found")
+		}
+		nextPlayer = candidate
+
+		stunned, err := p.tickStatusEffects(room, nextPlayer)
+		if err != nil {
+			return false, err
+		}
+		if p.checkGameEnd(room) {
+			return true, nil
+		}
+		if room.IsPlayerAlive(nextPlayer) && !stunned {
 			break
 		}
 	}
 
-	if nextPlayer == "" {
-		return fmt.Errorf("next player not found")
+	room.SetPlayerRound(currentPlayer, "waiting")
+	room.SetPlayerRound(nextPlayer, "current")
+	room.IncrementTurnCount()
+
+	// 回合切换后重新开始计时
+	if !p.replayMode {
+		GlobalRoomTimerProcessor.StartRoomTimer(room.RoomID)
 	}
-	roomManager := service.GetRoomManager()
-	roomManager.SetPlayerRound(room.RoomID, currentPlayer, "waiting")
-	roomManager.SetPlayerRound(room.RoomID, nextPlayer, "current")
 
-	return nil
+	return false, nil
 }
 
-// checkGameEnd 检查游戏是否结束，返回true表示游戏已结束
-func (p *PlayCardProcessor) checkGameEnd(room *types.RoomInfo) bool {
-	for _, player := range room.Players {
-		if player.CurrentHealth <= 0 {
+// grantExtraTurn 出牌方获得额外回合：保持当前回合玩家不变，回合数加一并重新计时
+func (p *PlayCardProcessor) grantExtraTurn(room *types.RoomInfo) {
+	room.IncrementTurnCount()
+
+	if !p.replayMode {
+		GlobalRoomTimerProcessor.StartRoomTimer(room.RoomID)
+	}
+}
 
-			// 发布游戏结束事件
-			gameEndData := events.NewEventData(events.EventGameEnd, "play_card_processor", map[string]interface{}{})
-			gameEndData.SetRoom(room.RoomID)
-			events.Publish(events.EventGameEnd, gameEndData)
+// tickStatusEffects 玩家回合开始时结算状态效果：中毒造成伤害，眩晕使玩家跳过本回合，并向所有玩家同步结算信息
+// 返回值：stunned表示玩家本回合是否处于眩晕
+func (p *PlayCardProcessor) tickStatusEffects(room *types.RoomInfo, playerName string) (bool, error) {
+	activeEffects, err := room.TickStatusEffects(playerName)
+	if err != nil {
+		return false, fmt.Errorf("failed to tick status effects for %s: %v", playerName, err)
+	}
 
-			return true // 游戏已结束
+	stunned := false
+	for _, effect := range activeEffects {
+		var damageInfo models.DamageInfo
+		switch effect.Type {
+		case models.StatusEffectPoison:
+			dealt, _, err := room.ApplyDamage(playerName, effect.Value)
+			if err != nil {
+				return false, fmt.Errorf("failed to apply poison damage to %s: %v", playerName, err)
+			}
+			room.RecordDamage(effect.Source, playerName, dealt)
+			damageInfo = p.statusDamageInfo(effect, playerName, "Poison", dealt)
+		case models.StatusEffectStun:
+			if stunned {
+				continue
+			}
+			stunned = true
+			damageInfo = p.statusDamageInfo(effect, playerName, "Stunned", 0)
+		default:
+			continue
+		}
+
+		for _, player := range room.Players {
+			room.SetPlayerDamage(player.Username, damageInfo)
 		}
 	}
-	return false // 游戏继续
+
+	return stunned, nil
+}
+
+// statusDamageInfo 创建状态效果结算的伤害信息
+func (p *PlayCardProcessor) statusDamageInfo(effect models.StatusEffect, playerName, damageType string, value float64) models.DamageInfo {
+	return models.DamageInfo{
+		DamageSource:   effect.Source,
+		DamageTarget:   playerName,
+		DamageType:     damageType,
+		DamageValue:    value,
+		TriggeredBonds: []models.BondModel{},
+		Effects: []models.SkillEffectInfo{{
+			Bond:   effect.Bond,
+			Effect: effect.Type,
+			Source: effect.Source,
+			Target: playerName,
+			Value:  value,
+			Turns:  effect.TurnsLeft - 1,
+		}},
+	}
+}
+
+// checkGameEnd 检查游戏是否结束（存活玩家不超过一名），返回true表示游戏已结束
+func (p *PlayCardProcessor) checkGameEnd(room *types.RoomInfo) bool {
+	return len(room.GetAlivePlayers()) <= 1
+}
+
+// publishGameEnd 发布游戏结束事件
+func (p *PlayCardProcessor) publishGameEnd(room *types.RoomInfo) {
+	gameEndData := events.NewEventData(events.EventGameEnd, "play_card_processor", map[string]interface{}{})
+	gameEndData.SetRoom(room.RoomID)
+	events.Publish(events.EventGameEnd, gameEndData)
 }
 
 // publishGameStateUpdateWithBonds 发布包含羁绊信息的游戏状态更新事件
@@ -340,7 +587,8 @@ func (p *PlayCardProcessor) publishGameStateUpdateWithBonds(room *types.RoomInfo
 	events.Publish(events.EventGameStateUpdate, stateUpdateData)
 }
 
-// drawCardsForPlayer 为玩家从1级卡牌池抽取指定数量的卡牌
+// drawCardsForPlayer 为玩家从抽牌堆（未使用卡组时为1级卡牌池）抽取指定数量的卡牌
+// 卡牌池抽空时将弃牌堆洗回卡牌池，两者都为空时按房间设置对玩家造成疲劳伤害
 func (p *PlayCardProcessor) drawCardsForPlayer(room *types.RoomInfo, playerName string, count int) error {
 	// 检查玩家当前手牌数量
 	playerInfo, err := room.GetPlayerInfo(playerName)
@@ -357,11 +605,20 @@ func (p *PlayCardProcessor) drawCardsForPlayer(room *types.RoomInfo, playerName
 		count = availableSlots // 只抽取可用槽位数量的卡牌
 	}
 
-	// 从1级卡牌池抽取卡牌
-	drawnCards, err := room.DrawRandomCardsFromLevel1Pool(count)
+	// 从玩家的抽牌堆（未使用卡组时为1级卡牌池）抽取卡牌
+	drawResult, err := room.DrawCardsWithReshuffle(playerName, count)
 	if err != nil {
-		return fmt.Errorf("failed to draw %d cards from level 1 pool: %v", count, err)
+		return fmt.Errorf("failed to draw %d cards for player %s: %v", count, playerName, err)
+	}
+	if drawResult.PoolEmptied && !p.replayMode {
+		p.publishDeckEmpty(room, playerName, drawResult)
+	}
+	if drawResult.Shortfall > 0 {
+		if err := p.applyFatigueDamage(room, playerName, drawResult.Shortfall); err != nil {
+			return err
+		}
 	}
+	drawnCards := drawResult.Cards
 
 	// 将抽取的卡牌添加到玩家手牌
 	successCount := 0
@@ -369,9 +626,7 @@ func (p *PlayCardProcessor) drawCardsForPlayer(room *types.RoomInfo, playerName
 		err = room.AddCardToPlayer(playerName, card)
 		if err != nil {
 			// 如果添加失败，将剩余未添加的卡牌放回卡牌池
-			for i := successCount; i < len(drawnCards); i++ {
-				room.Level1CardPool = append(room.Level1CardPool, drawnCards[i])
-			}
+			room.ReturnCardsToPlayerPile(playerName, drawnCards[successCount:])
 			return fmt.Errorf("failed to add card %s to player %s after %d successful additions: %v",
 				card.Name, playerName, successCount, err)
 		}
@@ -381,24 +636,75 @@ func (p *PlayCardProcessor) drawCardsForPlayer(room *types.RoomInfo, playerName
 	return nil
 }
 
-// updatePlayerBattleStats 更新双方玩家的战斗数据
-func (p *PlayCardProcessor) updatePlayerBattleStats(room *types.RoomInfo, attackerName string, totalDamage float64, targetType string, bondResult *BondCalculationResult) error {
+// publishDeckEmpty 发布卡牌池抽空事件，弃牌堆洗回卡牌池时同时发布洗牌事件
+func (p *PlayCardProcessor) publishDeckEmpty(room *types.RoomInfo, playerName string, drawResult *types.DrawResult) {
+	deckEmptyData := events.NewEventData(events.EventDeckEmpty, "play_card_processor", map[string]interface{}{
+		"player":     playerName,
+		"reshuffled": drawResult.Reshuffled,
+		"shortfall":  drawResult.Shortfall,
+	})
+	deckEmptyData.SetRoom(room.RoomID)
+	events.Publish(events.EventDeckEmpty, deckEmptyData)
+
+	if drawResult.Reshuffled > 0 {
+		shuffleData := events.NewEventData(events.EventCardShuffle, "play_card_processor", map[string]interface{}{
+			"player": playerName,
+			"cards":  drawResult.Reshuffled,
+		})
+		shuffleData.SetRoom(room.RoomID)
+		events.Publish(events.EventCardShuffle, shuffleData)
+	}
+}
+
+// applyFatigueDamage 卡牌池和弃牌堆都为空时，按少抽的卡牌数量对玩家造成疲劳伤害，并向所有玩家同步
+func (p *PlayCardProcessor) applyFatigueDamage(room *types.RoomInfo, playerName string, shortfall int) error {
+	fatigueDamage := room.GetFatigueDamage()
+	if fatigueDamage <= 0 {
+		return nil
+	}
+
+	dealt, _, err := room.ApplyDamage(playerName, fatigueDamage*float64(shortfall))
+	if err != nil {
+		return fmt.Errorf("failed to apply fatigue damage to %s: %v", playerName, err)
+	}
+
+	damageInfo := models.DamageInfo{
+		DamageTarget:   playerName,
+		DamageType:     "Fatigue",
+		DamageValue:    dealt,
+		TriggeredBonds: []models.BondModel{},
+	}
+	for _, player := range room.Players {
+		room.SetPlayerDamage(player.Username, damageInfo)
+	}
+	return nil
+}
+
+// updatePlayerBattleStats 更新房间内玩家的战斗数据，羁绊技能效果随伤害信息同步给所有玩家
+// AOE时向所有玩家同步每个AOE目标承受的伤害，不包含出牌方和已淘汰的玩家
+func (p *PlayCardProcessor) updatePlayerBattleStats(room *types.RoomInfo, attackerName, targetPlayer string, aoeTargets []string, totalDamage float64, targetType string, bondResult *BondCalculationResult, effects []models.SkillEffectInfo) error {
 	// 将触发的羁绊转换为BondModel切片
 	triggeredBondModels := make([]models.BondModel, 0, len(bondResult.TriggeredBonds))
+	triggeredBondNames := make([]string, 0, len(bondResult.TriggeredBonds))
 	for _, triggeredBond := range bondResult.TriggeredBonds {
 		triggeredBondModels = append(triggeredBondModels, *triggeredBond.Bond)
+		triggeredBondNames = append(triggeredBondNames, triggeredBond.Bond.Name)
 	}
 
+	// 记录出牌方触发的羁绊
+	room.RecordBondsTriggered(attackerName, triggeredBondNames)
+
 	switch targetType {
 	case "opponent":
-		// 为其他玩家设置承受伤害
+		// 向所有玩家同步目标玩家承受的伤害
 		for _, player := range room.Players {
 			DamageInfo := models.DamageInfo{
 				DamageSource:   attackerName,
-				DamageTarget:   player.Username,
+				DamageTarget:   targetPlayer,
 				DamageType:     "Attacked",
 				DamageValue:    totalDamage,
 				TriggeredBonds: triggeredBondModels,
+				Effects:        effects,
 			}
 			room.SetPlayerDamage(player.Username, DamageInfo)
 		}
@@ -411,30 +717,33 @@ func (p *PlayCardProcessor) updatePlayerBattleStats(room *types.RoomInfo, attack
 				DamageType:     "Recover",
 				DamageValue:    totalDamage,
 				TriggeredBonds: triggeredBondModels,
+				Effects:        effects,
 			}
 			room.SetPlayerDamage(player.Username, DamageInfo)
 		}
 
 	case "all":
-		// 为所有其他玩家设置承受伤害
-		for _, player := range room.Players {
-			// 更新被攻击方数据
+		// 向所有玩家同步每个AOE目标承受的伤害
+		for _, target := range aoeTargets {
 			DamageInfo := models.DamageInfo{
 				DamageSource:   attackerName,
-				DamageTarget:   player.Username,
+				DamageTarget:   target,
 				DamageType:     "AOE",
 				DamageValue:    totalDamage,
 				TriggeredBonds: triggeredBondModels,
+				Effects:        effects,
 			}
-			err := room.SetPlayerDamage(player.Username, DamageInfo)
-			if err != nil {
-				return fmt.Errorf("failed to set attacker bonds: %v", err)
+			for _, player := range room.Players {
+				err := room.SetPlayerDamage(player.Username, DamageInfo)
+				if err != nil {
+					return fmt.Errorf("failed to set AOE damage info: %v", err)
+				}
 			}
 		}
 
 	default:
 		// 默认按对手处理
-		return p.updatePlayerBattleStats(room, attackerName, totalDamage, "opponent", bondResult)
+		return p.updatePlayerBattleStats(room, attackerName, targetPlayer, aoeTargets, totalDamage, "opponent", bondResult, effects)
 	}
 
 
@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"log"
+
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// PrivateRoomProcessor 私人房间处理器，负责同步房间等待信息和处理离开房间
+type PrivateRoomProcessor struct {
+	Name string
+}
+
+// NewPrivateRoomProcessor 创建新的私人房间处理器
+func NewPrivateRoomProcessor() *PrivateRoomProcessor {
+	return &PrivateRoomProcessor{
+		Name: "PrivateRoomProcessor",
+	}
+}
+
+// 全局私人房间处理器实例
+var GlobalPrivateRoomProcessor = NewPrivateRoomProcessor()
+
+// ProcessRoomUpdate 处理房间信息更新事件，向房间内玩家同步等待信息
+func (prp *PrivateRoomProcessor) ProcessRoomUpdate(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok || eventData.RoomID == "" {
+		return
+	}
+	prp.BroadcastLobby(eventData.RoomID)
+}
+
+// BroadcastLobby 向私人房间内所有玩家发送房间等待信息 (消息码1504)
+func (prp *PrivateRoomProcessor) BroadcastLobby(roomID string) {
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil || room.InviteCode == "" {
+		return
+	}
+
+	response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(1504, room.LobbyInfo())
+	connManager := service.GetConnectionManager()
+	for _, username := range room.GetTurnOrder() {
+		clientInfo, exists := connManager.GetConnectionByUsername(username)
+		if !exists || clientInfo.Conn == nil {
+			continue
+		}
+		if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+			log.Printf("Failed to send lobby update of room %s to %s: %v", roomID, username, err)
+		}
+	}
+}
+
+// LeaveRoom 玩家离开私人房间并恢复为已登录状态，房间仍有玩家时同步等待信息
+func (prp *PrivateRoomProcessor) LeaveRoom(clientInfo *types.ClientInfo) error {
+	roomID := clientInfo.GetGameRoom()
+
+	_, removed, err := service.GetRoomManager().LeavePrivateRoom(roomID, clientInfo.Username)
+	if err != nil {
+		return err
+	}
+
+	clientInfo.SetStatus(types.StatusLoggedIn)
+	clientInfo.SetGameRoom("")
+
+	if !removed {
+		prp.BroadcastLobby(roomID)
+	}
+	return nil
+}
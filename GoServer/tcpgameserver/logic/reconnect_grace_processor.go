@@ -0,0 +1,214 @@
+package logic
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// ReconnectGraceProcessor 断线重连等待处理器：玩家断线后按房间设置等待重连，
+// 期间向对手推送倒计时 (消息码7003)，超时未重连判负 (消息码7004)
+type ReconnectGraceProcessor struct {
+	Name   string
+	graces map[string]*reconnectGrace // 房间ID:用户名 -> 等待中的重连
+	mutex  sync.Mutex
+}
+
+// reconnectGrace 等待中的断线重连
+type reconnectGrace struct {
+	roomID   string
+	username string
+	clientID string    // 断线时的客户端ID
+	deadline time.Time // 重连截止时间
+	stop     chan struct{}
+}
+
+// NewReconnectGraceProcessor 创建新的断线重连等待处理器
+func NewReconnectGraceProcessor() *ReconnectGraceProcessor {
+	return &ReconnectGraceProcessor{
+		Name:   "ReconnectGraceProcessor",
+		graces: make(map[string]*reconnectGrace),
+	}
+}
+
+// 全局断线重连等待处理器实例
+var GlobalReconnectGraceProcessor = NewReconnectGraceProcessor()
+
+// graceKey 生成等待记录的key
+func graceKey(roomID, username string) string {
+	return roomID + ":" + username
+}
+
+// StartGrace 开始等待玩家重连，返回重连等待时间；重复断线时沿用原有的截止时间
+func (rgp *ReconnectGraceProcessor) StartGrace(room *types.RoomInfo, username, clientID string) time.Duration {
+	window := room.ReconnectWindow
+	if window <= 0 {
+		window = config.GetReconnectWindow()
+	}
+
+	rgp.mutex.Lock()
+	key := graceKey(room.RoomID, username)
+	if existing, exists := rgp.graces[key]; exists {
+		rgp.mutex.Unlock()
+		return time.Until(existing.deadline)
+	}
+	grace := &reconnectGrace{
+		roomID:   room.RoomID,
+		username: username,
+		clientID: clientID,
+		deadline: time.Now().Add(window),
+		stop:     make(chan struct{}),
+	}
+	rgp.graces[key] = grace
+	rgp.mutex.Unlock()
+
+	go rgp.countdown(grace)
+	return window
+}
+
+// CancelGrace 玩家重连成功，停止等待；返回false表示没有等待中的重连（已超时判负）
+func (rgp *ReconnectGraceProcessor) CancelGrace(roomID, username string) bool {
+	grace, exists := rgp.take(graceKey(roomID, username), nil)
+	if !exists {
+		return false
+	}
+	close(grace.stop)
+	return true
+}
+
+// ClearRoom 停止房间内所有的重连等待，对局结束时调用
+func (rgp *ReconnectGraceProcessor) ClearRoom(roomID string) {
+	rgp.mutex.Lock()
+	defer rgp.mutex.Unlock()
+
+	for key, grace := range rgp.graces {
+		if grace.roomID == roomID {
+			close(grace.stop)
+			delete(rgp.graces, key)
+		}
+	}
+}
+
+// take 取出等待记录，expected不为空时只在记录未被替换时取出
+func (rgp *ReconnectGraceProcessor) take(key string, expected *reconnectGrace) (*reconnectGrace, bool) {
+	rgp.mutex.Lock()
+	defer rgp.mutex.Unlock()
+
+	grace, exists := rgp.graces[key]
+	if !exists || (expected != nil && grace != expected) {
+		return nil, false
+	}
+	delete(rgp.graces, key)
+	return grace, true
+}
+
+// countdown 定时向对手推送重连倒计时，截止时仍未重连则判负
+func (rgp *ReconnectGraceProcessor) countdown(grace *reconnectGrace) {
+	ticker := time.NewTicker(config.GetReconnectCountdownInterval())
+	defer ticker.Stop()
+	expire := time.NewTimer(time.Until(grace.deadline))
+	defer expire.Stop()
+
+	rgp.notifyCountdown(grace)
+	for {
+		select {
+		case <-grace.stop:
+			return
+		case <-ticker.C:
+			rgp.notifyCountdown(grace)
+		case <-expire.C:
+			if _, exists := rgp.take(graceKey(grace.roomID, grace.username), grace); exists {
+				rgp.forfeit(grace)
+			}
+			return
+		}
+	}
+}
+
+// notifyCountdown 向房间内其他玩家推送重连剩余时间 (消息码7003)
+func (rgp *ReconnectGraceProcessor) notifyCountdown(grace *reconnectGrace) {
+	secondsLeft := int(time.Until(grace.deadline).Round(time.Second).Seconds())
+	if secondsLeft < 0 {
+		secondsLeft = 0
+	}
+	rgp.notifyOpponents(grace, tools.GlobalResponseHelper.CreateSuccessTcpResponse(7003, map[string]interface{}{
+		"RoomID":      grace.roomID,
+		"Username":    grace.username,
+		"SecondsLeft": secondsLeft,
+	}))
+}
+
+// forfeit 重连超时判负：淘汰该玩家并移除其断开的连接，只剩一名存活玩家时通过游戏结束流程结算，否则继续游戏
+func (rgp *ReconnectGraceProcessor) forfeit(grace *reconnectGrace) {
+	room, err := service.GetRoomManager().GetRoom(grace.roomID)
+	if err != nil || room.Status != "playing" || !room.IsPlayerAlive(grace.username) {
+		return
+	}
+
+	rgp.notifyOpponents(grace, tools.GlobalResponseHelper.CreateSuccessTcpResponse(7004, map[string]interface{}{
+		"RoomID":   grace.roomID,
+		"Username": grace.username,
+	}))
+
+	gameEnded, err := NewPlayCardProcessor().EliminatePlayer(room, grace.username, "disconnect_forfeit")
+	if err != nil {
+		log.Printf("Failed to forfeit disconnected player %s in room %s: %v", grace.username, grace.roomID, err)
+		return
+	}
+
+	// 移除仍在等待重连的旧连接
+	connManager := service.GetConnectionManager()
+	if clientInfo, exists := connManager.GetConnectionByClientID(grace.clientID); exists && clientInfo.GetStatus() == types.StatusWaitingReconnect {
+		connManager.RemoveConnection(grace.clientID)
+	}
+
+	if !gameEnded {
+		// 结束该玩家断线引起的暂停，其余玩家继续游戏
+		GlobalPauseProcessor.ReleasePlayer(room.RoomID, grace.username)
+
+		stateUpdateData := events.NewEventData(events.EventGameStateUpdate, "reconnect_grace_processor", map[string]interface{}{
+			"eliminated_player": grace.username,
+			"reason":            "disconnect_forfeit",
+		})
+		stateUpdateData.SetRoom(room.RoomID)
+		events.Publish(events.EventGameStateUpdate, stateUpdateData)
+		return
+	}
+
+	gameEndData := events.NewEventData(events.EventGameEnd, "reconnect_grace_processor", map[string]interface{}{
+		"reason": "disconnect_forfeit",
+		"loser":  grace.username,
+	})
+	gameEndData.SetRoom(room.RoomID)
+	events.Publish(events.EventGameEnd, gameEndData)
+}
+
+// notifyOpponents 向房间内除断线玩家外的在线玩家发送消息
+func (rgp *ReconnectGraceProcessor) notifyOpponents(grace *reconnectGrace, response *models.TcpResponse) {
+	room, err := service.GetRoomManager().GetRoom(grace.roomID)
+	if err != nil {
+		return
+	}
+
+	connManager := service.GetConnectionManager()
+	for _, username := range room.GetTurnOrder() {
+		if username == grace.username {
+			continue
+		}
+		clientInfo, exists := connManager.GetConnectionByUsername(username)
+		if !exists || clientInfo.Conn == nil || clientInfo.GetStatus() != types.StatusInGame {
+			continue
+		}
+		if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+			log.Printf("Failed to send reconnect message %s to %s: %v", response.Code, username, err)
+		}
+	}
+}
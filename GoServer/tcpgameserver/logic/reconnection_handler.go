@@ -1,11 +1,11 @@
 package logic
 
 import (
-	"encoding/json"
-	"net"
 	"time"
 
+	"GoServer/tcpgameserver/events"
 	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/tools"
 	"GoServer/tcpgameserver/types"
@@ -37,7 +37,10 @@ func (r *ReconnectionHandler) HandlePlayerReconnection(clientID, username string
 	}
 
 	// 获取玩家的游戏信息
-	room, _ := roomManager.FindRoomByPlayer(username)
+	room, err := roomManager.FindRoomByPlayer(username)
+	if err != nil || room == nil || room.Status != "playing" {
+		return r.sendReconnectionFailure(clientID, "No active game found", connManager)
+	}
 	playerGameInfo, err := roomManager.GetPlayerGameInfo(room.RoomID, username)
 	if err != nil || playerGameInfo == nil {
 		return r.sendReconnectionFailure(clientID, "No active game found", connManager)
@@ -55,6 +58,12 @@ func (r *ReconnectionHandler) HandlePlayerReconnection(clientID, username string
 		return r.sendReconnectionFailure(clientID, "Failed to bind user", connManager)
 	}
 
+	// 绑定成功后停止重连等待，之前失败时等待仍会按时判负
+	// 等待已超时（已判负）时重连失败，新连接保持已登录状态
+	if !GlobalReconnectGraceProcessor.CancelGrace(room.RoomID, username) {
+		return r.sendReconnectionFailure(clientID, "Reconnection window expired", connManager)
+	}
+
 	// 设置玩家状态为游戏中
 	connManager.SetPlayerStatus(clientID, types.StatusInGame)
 	connManager.SetPlayerGameRoom(clientID, playerGameInfo.RoomId)
@@ -69,27 +78,24 @@ func (r *ReconnectionHandler) HandlePlayerReconnection(clientID, username string
 	if err != nil {
 	}
 
+	// 恢复因该玩家断线引起的暂停
+	resumeData := events.NewEventData(events.EventGameResume, "reconnection_handler", map[string]interface{}{
+		"username": username,
+		"reason":   types.PauseReasonReconnect,
+	})
+	resumeData.SetRoom(playerGameInfo.RoomId)
+	events.Publish(events.EventGameResume, resumeData)
+
 	return nil
 }
 
 // sendReconnectionSuccess 发送重连成功消息 (消息类型 6001)
-func (r *ReconnectionHandler) sendReconnectionSuccess(conn net.Conn, playerGameInfo *models.PlayerGameInfo) error {
+func (r *ReconnectionHandler) sendReconnectionSuccess(conn protocol.Conn, playerGameInfo *models.PlayerGameInfo) error {
 	// 创建重连成功响应
 	response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(6001, playerGameInfo)
 
 	// 序列化并发送消息
-	messageData, err := json.Marshal(response)
-	if err != nil {
-		return err
-	}
-	messageData = append(messageData, '\n')
-
-	_, err = conn.Write(messageData)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return protocol.WriteJSON(conn, response)
 }
 
 // sendReconnectionFailure 发送重连失败消息
@@ -102,18 +108,7 @@ func (r *ReconnectionHandler) sendReconnectionFailure(clientID, reason string, c
 	response := tools.GlobalResponseHelper.CreateErrorTcpResponse(6002)
 
 	// 序列化并发送消息
-	messageData, err := json.Marshal(response)
-	if err != nil {
-		return err
-	}
-	messageData = append(messageData, '\n')
-
-	_, err = clientInfo.Conn.Write(messageData)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return protocol.WriteJSON(clientInfo.Conn, response)
 }
 
 // notifyRoomPlayersReconnection 通知房间内其他玩家有玩家重连
@@ -145,12 +140,8 @@ func (r *ReconnectionHandler) notifyRoomPlayersReconnection(username, roomID str
 		if player.Conn != nil {
 			response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(7002, reconnectNotification)
 
-			if messageData, err := json.Marshal(response); err == nil {
-				messageData = append(messageData, '\n')
-				if _, writeErr := player.Conn.Write(messageData); writeErr != nil {
-				} else {
-					notifiedCount++
-				}
+			if writeErr := protocol.WriteJSON(player.Conn, response); writeErr == nil {
+				notifiedCount++
 			}
 		}
 	}
@@ -0,0 +1,187 @@
+package logic
+
+import (
+	"log"
+
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/types"
+)
+
+// ReplayRecorder 对局回放记录器，将房间内的每个动作追加写入回放日志
+type ReplayRecorder struct {
+	Name   string
+	logger *service.ReplayLogger
+}
+
+// NewReplayRecorder 创建新的回放记录器
+func NewReplayRecorder() *ReplayRecorder {
+	return &ReplayRecorder{
+		Name:   "ReplayRecorder",
+		logger: service.GetReplayLogger(),
+	}
+}
+
+// 全局回放记录器实例
+var GlobalReplayRecorder = NewReplayRecorder()
+
+// RecordGameStart 记录房间初始数据（需在发牌前调用，卡牌池为完整状态）
+func (rr *ReplayRecorder) RecordGameStart(room *types.RoomInfo, players []string) {
+	level1Cards, _ := room.GetSharedCardPool(1)
+	level2Cards, _ := room.GetSharedCardPool(2)
+	level3Cards, _ := room.GetSharedCardPool(3)
+
+	rr.append(room, &models.ReplayEntry{
+		Type: models.ReplayEntryGameStart,
+		Start: &models.ReplayGameStart{
+			Seed:           room.Seed,
+			CardSetVersion: room.GetCardSetVersion(),
+			Players:        players,
+			InitialHealth:  room.InitialHealth,
+			MaxHandCards:   room.MaxHandCards,
+			FatigueDamage:  room.FatigueDamage,
+			Level1CardPool: level1Cards,
+			Level2CardPool: level2Cards,
+			Level3CardPool: level3Cards,
+			DrawPiles:      room.GetDrawPiles(),
+		},
+	})
+}
+
+// RecordDeal 记录玩家的初始手牌
+func (rr *ReplayRecorder) RecordDeal(room *types.RoomInfo, username string, cards []models.Card) {
+	rr.append(room, &models.ReplayEntry{
+		Type:   models.ReplayEntryDeal,
+		Player: username,
+		Cards:  cards,
+	})
+}
+
+// RecordFirstTurn 记录先手玩家
+func (rr *ReplayRecorder) RecordFirstTurn(room *types.RoomInfo, username string) {
+	rr.append(room, &models.ReplayEntry{
+		Type:   models.ReplayEntryFirstTurn,
+		Player: username,
+		State:  room.ReplaySnapshot(),
+	})
+}
+
+// RecordPlayCard 记录一次出牌，包括羁绊计算结果、血量变化和抽到的卡牌
+func (rr *ReplayRecorder) RecordPlayCard(room *types.RoomInfo, data *PlayCardData, playedCards []models.Card, bondResult *BondCalculationResult, healthBefore map[string]float64, handBefore []models.Card) {
+	// 计算血量变化
+	healthDeltas := make(map[string]float64)
+	for username, health := range room.GetPlayersHealth() {
+		if delta := health - healthBefore[username]; delta != 0 {
+			healthDeltas[username] = delta
+		}
+	}
+
+	// 出牌后新增的手牌即为抽到的卡牌
+	beforeUIDs := make(map[string]bool, len(handBefore))
+	for _, card := range handBefore {
+		beforeUIDs[card.UID] = true
+	}
+	handAfter, _ := room.GetPlayerHandCards(data.Player)
+	drawnCards := make([]models.Card, 0)
+	for _, card := range handAfter {
+		if !beforeUIDs[card.UID] {
+			drawnCards = append(drawnCards, card)
+		}
+	}
+
+	rr.append(room, &models.ReplayEntry{
+		Type:         models.ReplayEntryPlayCard,
+		Player:       data.Player,
+		Source:       data.Source,
+		Target:       replayTarget(data),
+		Cards:        playedCards,
+		NewCards:     drawnCards,
+		BondResult:   toReplayBondResult(bondResult),
+		HealthDeltas: healthDeltas,
+		State:        room.ReplaySnapshot(),
+	})
+}
+
+// RecordComposeCard 记录一次合成
+func (rr *ReplayRecorder) RecordComposeCard(room *types.RoomInfo, username string, result *ComposeResult) {
+	rr.append(room, &models.ReplayEntry{
+		Type:     models.ReplayEntryComposeCard,
+		Player:   username,
+		Cards:    result.RemovedCards,
+		NewCards: result.NewCards,
+		State:    room.ReplaySnapshot(),
+	})
+}
+
+// RecordPassTurn 记录跳过回合
+func (rr *ReplayRecorder) RecordPassTurn(room *types.RoomInfo, username string) {
+	rr.append(room, &models.ReplayEntry{
+		Type:   models.ReplayEntryPassTurn,
+		Player: username,
+		Source: "timeout",
+		State:  room.ReplaySnapshot(),
+	})
+}
+
+// RecordEliminate 记录玩家被淘汰
+func (rr *ReplayRecorder) RecordEliminate(room *types.RoomInfo, username, reason string) {
+	rr.append(room, &models.ReplayEntry{
+		Type:   models.ReplayEntryEliminate,
+		Player: username,
+		Reason: reason,
+		State:  room.ReplaySnapshot(),
+	})
+}
+
+// RecordGameEnd 记录游戏结束，loser为判负玩家（非判负结束时为空），并结束该房间的日志
+func (rr *ReplayRecorder) RecordGameEnd(room *types.RoomInfo, winner, reason, loser string) {
+	rr.append(room, &models.ReplayEntry{
+		Type:   models.ReplayEntryGameEnd,
+		Player: loser,
+		Winner: winner,
+		Reason: reason,
+		State:  room.ReplaySnapshot(),
+	})
+	rr.logger.Finish(room.RoomID)
+}
+
+// append 追加写入回放日志，写入失败只记录错误不影响对局
+func (rr *ReplayRecorder) append(room *types.RoomInfo, entry *models.ReplayEntry) {
+	if err := rr.logger.Append(room.RoomID, entry); err != nil {
+		log.Printf("Failed to append replay entry %s for room %s: %v", entry.Type, room.RoomID, err)
+	}
+}
+
+// toReplayBondResult 转换羁绊计算结果为回放日志格式
+func toReplayBondResult(bondResult *BondCalculationResult) *models.ReplayBondResult {
+	result := &models.ReplayBondResult{
+		TotalDamage:    bondResult.TotalDamage,
+		TriggeredBonds: make([]models.ReplayTriggeredBond, 0, len(bondResult.TriggeredBonds)),
+	}
+
+	for _, triggeredBond := range bondResult.TriggeredBonds {
+		cardUIDs := make([]string, 0, len(triggeredBond.UsedCards))
+		for _, card := range triggeredBond.UsedCards {
+			cardUIDs = append(cardUIDs, card.UID)
+		}
+		result.TriggeredBonds = append(result.TriggeredBonds, models.ReplayTriggeredBond{
+			Name:       triggeredBond.Bond.Name,
+			BondDamage: triggeredBond.BondDamage,
+			CardUIDs:   cardUIDs,
+		})
+	}
+
+	return result
+}
+
+// replayTarget 将出牌目标转换为回放日志中的目标：目标玩家用户名、all 或 self
+func replayTarget(data *PlayCardData) string {
+	switch data.TargetType {
+	case "all":
+		return PlayTargetAll
+	case "self":
+		return PlayTargetSelf
+	default:
+		return data.Target
+	}
+}
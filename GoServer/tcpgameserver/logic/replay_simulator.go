@@ -0,0 +1,260 @@
+package logic
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"GoServer/tcpgameserver/cards"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/types"
+)
+
+// ReplayMismatch 回放校验中与记录不一致的条目
+type ReplayMismatch struct {
+	Seq    int    `json:"seq"`
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+// ReplayVerification 回放校验结果
+type ReplayVerification struct {
+	RoomID     string              `json:"room_id"`
+	Entries    int                 `json:"entries"`
+	Matched    bool                `json:"matched"`
+	Mismatches []ReplayMismatch    `json:"mismatches"`
+	FinalState *models.ReplayState `json:"final_state"`
+}
+
+// ReplaySimulator 回放模拟器，根据回放日志通过出牌和合成处理器重新模拟对局
+type ReplaySimulator struct {
+	Name              string
+	playCardProcessor *PlayCardProcessor
+	composeProcessor  *CardComposeProcessor
+}
+
+// NewReplaySimulator 创建新的回放模拟器
+func NewReplaySimulator() *ReplaySimulator {
+	return &ReplaySimulator{
+		Name:              "ReplaySimulator",
+		playCardProcessor: NewReplayPlayCardProcessor(),
+		composeProcessor:  NewCardComposeProcessor(),
+	}
+}
+
+// SimulateRoom 读取房间的回放日志并重新模拟
+func (rs *ReplaySimulator) SimulateRoom(roomID string) (*ReplayVerification, error) {
+	entries, err := service.GetReplayLogger().ReadReplay(roomID)
+	if err != nil {
+		return nil, err
+	}
+	return rs.Simulate(entries)
+}
+
+// Simulate 根据回放日志重新模拟对局，逐条校验模拟状态与记录状态是否一致
+// 模拟在独立的房间实例上进行，不影响房间管理器、计时器和事件系统
+func (rs *ReplaySimulator) Simulate(entries []models.ReplayEntry) (*ReplayVerification, error) {
+	if len(entries) == 0 || entries[0].Type != models.ReplayEntryGameStart || entries[0].Start == nil {
+		return nil, fmt.Errorf("replay log must begin with a %s entry", models.ReplayEntryGameStart)
+	}
+
+	room, err := rs.buildRoom(&entries[0])
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &ReplayVerification{
+		RoomID:     entries[0].RoomID,
+		Entries:    len(entries),
+		Mismatches: make([]ReplayMismatch, 0),
+	}
+	mismatch := func(entry *models.ReplayEntry, format string, args ...interface{}) {
+		verification.Mismatches = append(verification.Mismatches, ReplayMismatch{
+			Seq:    entry.Seq,
+			Type:   entry.Type,
+			Detail: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for i := 1; i < len(entries); i++ {
+		entry := &entries[i]
+
+		switch entry.Type {
+		case models.ReplayEntryDeal:
+			dealtCards, err := room.DrawCardsForPlayer(entry.Player, len(entry.Cards))
+			if err != nil {
+				return nil, fmt.Errorf("seq %d: failed to deal cards to %s: %v", entry.Seq, entry.Player, err)
+			}
+			player, exists := room.Players[entry.Player]
+			if !exists {
+				return nil, fmt.Errorf("seq %d: player %s not found in replay room", entry.Seq, entry.Player)
+			}
+			player.HandCards = dealtCards
+			if !sameCardUIDs(dealtCards, entry.Cards) {
+				mismatch(entry, "dealt cards differ for %s: simulated %v, recorded %v",
+					entry.Player, cardUIDs(dealtCards), cardUIDs(entry.Cards))
+			}
+
+		case models.ReplayEntryFirstTurn:
+			for username := range room.Players {
+				room.SetPlayerRound(username, "waiting")
+			}
+			room.SetPlayerRound(entry.Player, "current")
+			room.MarkMatchStarted()
+
+		case models.ReplayEntryPlayCard:
+			targetType, targetPlayer := ParsePlayTarget(entry.Target)
+			bondResult, _, err := rs.playCardProcessor.executePlayCardOnRoom(room, &PlayCardData{
+				RoomID:      room.RoomID,
+				Player:      entry.Player,
+				CardsToPlay: entry.Cards,
+				TargetType:  targetType,
+				Target:      targetPlayer,
+				Source:      entry.Source,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("seq %d: failed to replay play card for %s: %v", entry.Seq, entry.Player, err)
+			}
+			if entry.BondResult != nil && bondResult.TotalDamage != entry.BondResult.TotalDamage {
+				mismatch(entry, "total damage differs: simulated %.1f, recorded %.1f",
+					bondResult.TotalDamage, entry.BondResult.TotalDamage)
+			}
+
+		case models.ReplayEntryComposeCard:
+			composeResult, err := rs.composeProcessor.composeOnRoom(room, &CardComposeData{
+				RoomID: room.RoomID,
+				Player: entry.Player,
+				Cards:  entry.Cards,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("seq %d: failed to replay compose for %s: %v", entry.Seq, entry.Player, err)
+			}
+			if !sameCardUIDs(composeResult.NewCards, entry.NewCards) {
+				mismatch(entry, "composed cards differ: simulated %v, recorded %v",
+					cardUIDs(composeResult.NewCards), cardUIDs(entry.NewCards))
+			}
+
+		case models.ReplayEntryPassTurn:
+			if _, err := rs.playCardProcessor.PassTurn(room, entry.Player); err != nil {
+				return nil, fmt.Errorf("seq %d: failed to replay pass turn for %s: %v", entry.Seq, entry.Player, err)
+			}
+
+		case models.ReplayEntryEliminate:
+			if _, err := rs.playCardProcessor.EliminatePlayer(room, entry.Player, entry.Reason); err != nil {
+				return nil, fmt.Errorf("seq %d: failed to replay elimination of %s: %v", entry.Seq, entry.Player, err)
+			}
+
+		case models.ReplayEntryGameEnd:
+			// 判负不经过出牌流程，按记录将判负玩家血量置零
+			if entry.Player != "" {
+				room.SetPlayerHealth(entry.Player, 0)
+			}
+			room.UpdateRoomStatus("finished")
+
+		default:
+			return nil, fmt.Errorf("seq %d: unknown replay entry type %s", entry.Seq, entry.Type)
+		}
+
+		if entry.State != nil {
+			for _, detail := range diffReplayState(entry.State, room.ReplaySnapshot()) {
+				mismatch(entry, "%s", detail)
+			}
+		}
+	}
+
+	verification.FinalState = room.ReplaySnapshot()
+	verification.Matched = len(verification.Mismatches) == 0
+	return verification, nil
+}
+
+// buildRoom 根据game_start条目创建独立的模拟房间
+// 模拟房间使用当前卡牌集，与对局记录的卡牌集版本不一致时无法重现羁绊和合成结果
+func (rs *ReplaySimulator) buildRoom(entry *models.ReplayEntry) (*types.RoomInfo, error) {
+	start := entry.Start
+
+	cardSet := cards.CurrentCardSet()
+	if start.CardSetVersion != "" && start.CardSetVersion != cardSet.Version {
+		return nil, fmt.Errorf("replay uses card set %s but current card set is %s", start.CardSetVersion, cardSet.Version)
+	}
+
+	room := types.NewRoomInfo(entry.RoomID, "Replay "+entry.RoomID, len(start.Players))
+	room.SetCardSet(cardSet)
+	room.SetRandomSeed(start.Seed)
+	room.InitialHealth = start.InitialHealth
+	room.MaxHandCards = start.MaxHandCards
+	room.FatigueDamage = start.FatigueDamage
+
+	if err := room.InitializeCardPools(start.Level1CardPool, start.Level2CardPool, start.Level3CardPool); err != nil {
+		return nil, fmt.Errorf("failed to initialize replay card pools: %v", err)
+	}
+
+	for _, username := range start.Players {
+		if err := room.AddPlayer(username); err != nil {
+			return nil, fmt.Errorf("failed to add player %s to replay room: %v", username, err)
+		}
+		if pile, usesDeck := start.DrawPiles[username]; usesDeck {
+			room.SetPlayerDrawPile(username, pile)
+		}
+	}
+
+	room.UpdateRoomStatus("playing")
+	return room, nil
+}
+
+// diffReplayState 比较记录状态与模拟状态，返回不一致的描述
+func diffReplayState(recorded, simulated *models.ReplayState) []string {
+	var diffs []string
+
+	usernames := make([]string, 0, len(recorded.Players))
+	for username := range recorded.Players {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
+		expected := recorded.Players[username]
+		actual, exists := simulated.Players[username]
+		if !exists {
+			diffs = append(diffs, fmt.Sprintf("player %s missing from simulated state", username))
+			continue
+		}
+		if expected.Health != actual.Health {
+			diffs = append(diffs, fmt.Sprintf("health of %s differs: simulated %.1f, recorded %.1f", username, actual.Health, expected.Health))
+		}
+		if expected.Round != actual.Round {
+			diffs = append(diffs, fmt.Sprintf("round of %s differs: simulated %s, recorded %s", username, actual.Round, expected.Round))
+		}
+		if !reflect.DeepEqual(expected.HandCardUIDs, actual.HandCardUIDs) {
+			diffs = append(diffs, fmt.Sprintf("hand of %s differs: simulated %v, recorded %v", username, actual.HandCardUIDs, expected.HandCardUIDs))
+		}
+	}
+
+	if recorded.Level1PoolCount != simulated.Level1PoolCount ||
+		recorded.Level2PoolCount != simulated.Level2PoolCount ||
+		recorded.Level3PoolCount != simulated.Level3PoolCount {
+		diffs = append(diffs, fmt.Sprintf("card pool sizes differ: simulated %d/%d/%d, recorded %d/%d/%d",
+			simulated.Level1PoolCount, simulated.Level2PoolCount, simulated.Level3PoolCount,
+			recorded.Level1PoolCount, recorded.Level2PoolCount, recorded.Level3PoolCount))
+	}
+
+	return diffs
+}
+
+// sameCardUIDs 判断两组卡牌的UID集合是否一致
+func sameCardUIDs(a, b []models.Card) bool {
+	aUIDs := cardUIDs(a)
+	bUIDs := cardUIDs(b)
+	sort.Strings(aUIDs)
+	sort.Strings(bUIDs)
+	return reflect.DeepEqual(aUIDs, bUIDs)
+}
+
+// cardUIDs 获取卡牌UID列表
+func cardUIDs(cards []models.Card) []string {
+	uids := make([]string, 0, len(cards))
+	for _, card := range cards {
+		uids = append(uids, card.UID)
+	}
+	return uids
+}
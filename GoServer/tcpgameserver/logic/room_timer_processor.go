@@ -1,10 +1,12 @@
 package logic
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
 	"GoServer/tcpgameserver/service"
 	"GoServer/tcpgameserver/types"
 )
@@ -17,9 +19,16 @@ const (
 // RoomTimerProcessor 房间计时处理器
 type RoomTimerProcessor struct {
 	Name       string
-	timers     map[string]*time.Timer // 房间ID -> 计时器
-	timerMutex sync.RWMutex           // 计时器操作的互斥锁
-	Duration   time.Duration          // 计时时长，可配置
+	timers     map[string]*roomTimer // 房间ID -> 计时器
+	timerMutex sync.RWMutex          // 计时器操作的互斥锁
+	Duration   time.Duration         // 房间未设置回合时长时使用的默认计时时长
+}
+
+// roomTimer 房间回合计时器
+type roomTimer struct {
+	timer    *time.Timer
+	player   string    // 计时开始时的当前回合玩家
+	deadline time.Time // 回合超时时间
 }
 
 // 全局处理器实例
@@ -29,7 +38,7 @@ var GlobalRoomTimerProcessor *RoomTimerProcessor
 func init() {
 	GlobalRoomTimerProcessor = &RoomTimerProcessor{
 		Name:     "RoomTimerProcessor",
-		timers:   make(map[string]*time.Timer),
+		timers:   make(map[string]*roomTimer),
 		Duration: DefaultTimerDuration,
 	}
 }
@@ -38,7 +47,7 @@ func init() {
 func NewRoomTimerProcessor() *RoomTimerProcessor {
 	return &RoomTimerProcessor{
 		Name:     "RoomTimerProcessor",
-		timers:   make(map[string]*time.Timer),
+		timers:   make(map[string]*roomTimer),
 		Duration: DefaultTimerDuration,
 	}
 }
@@ -68,47 +77,202 @@ func (rtp *RoomTimerProcessor) startPlayerTimer(room *types.RoomInfo) error {
 	// 停止现有计时器（如果存在）
 	rtp.stopRoomTimer(room.RoomID)
 
-	// 检查是否有Round为current的玩家
-	hasCurrentPlayer := false
-	for _, player := range room.Players {
-		if player.Round == "current" {
-			hasCurrentPlayer = true
-			break
-		}
+	// 暂停期间不计时，恢复时重新开始
+	if room.IsPaused() {
+		return nil
 	}
 
+	// 检查是否有Round为current的玩家
+	currentPlayer, hasCurrentPlayer := room.GetCurrentPlayer()
 	if !hasCurrentPlayer {
 		return nil
 	}
 
-	// 创建计时器，使用可配置的时长
-	timer := time.AfterFunc(rtp.Duration, func() {
+	// 使用房间配置的回合时长，未配置时使用默认时长
+	duration := room.TurnTimeout
+	if duration <= 0 {
+		duration = rtp.Duration
+	}
+
+	rtp.scheduleTimer(room.RoomID, currentPlayer, duration)
+	return nil
+}
 
-		// 调用强制出牌请求
-		err := rtp.forceCardPlay(room.RoomID)
-		if err != nil {
+// scheduleTimer 为当前回合玩家创建计时器，到期后执行超时处理
+func (rtp *RoomTimerProcessor) scheduleTimer(roomID, currentPlayer string, duration time.Duration) {
+	entry := &roomTimer{player: currentPlayer, deadline: time.Now().Add(duration)}
+	entry.timer = time.AfterFunc(duration, func() {
+		// 计时器已被替换或停止时不再处理
+		if !rtp.takeTimer(roomID, entry) {
+			return
 		}
 
-		// 清理计时器记录
-		rtp.timerMutex.Lock()
-		delete(rtp.timers, room.RoomID)
-		rtp.timerMutex.Unlock()
+		// 执行超时处理
+		rtp.forceCardPlay(roomID, entry.player)
 	})
 
 	// 保存计时器引用
 	rtp.timerMutex.Lock()
-	rtp.timers[room.RoomID] = timer
+	rtp.timers[roomID] = entry
 	rtp.timerMutex.Unlock()
+}
+
+// PauseRoomTimer 暂停房间计时，返回当前回合剩余的时间（没有计时器时返回0）
+func (rtp *RoomTimerProcessor) PauseRoomTimer(roomID string) time.Duration {
+	rtp.timerMutex.Lock()
+	defer rtp.timerMutex.Unlock()
+
+	entry, exists := rtp.timers[roomID]
+	if !exists {
+		return 0
+	}
+	entry.timer.Stop()
+	delete(rtp.timers, roomID)
 
+	remaining := time.Until(entry.deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining
+}
+
+// ResumeRoomTimer 恢复房间计时，当前回合玩家使用暂停时剩余的时间，剩余时间为0时重新开始计时
+func (rtp *RoomTimerProcessor) ResumeRoomTimer(roomID string, remaining time.Duration) error {
+	if remaining <= 0 {
+		return rtp.StartRoomTimer(roomID)
+	}
+
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+	currentPlayer, exists := room.GetCurrentPlayer()
+	if !exists {
+		return nil
+	}
+
+	rtp.stopRoomTimer(roomID)
+	rtp.scheduleTimer(roomID, currentPlayer, remaining)
 	return nil
 }
 
-// forceCardPlay 发送强制出牌请求
-func (rtp *RoomTimerProcessor) forceCardPlay(roomID string) error {
+// takeTimer 检查计时器是否仍为房间当前计时器，是则移除记录
+func (rtp *RoomTimerProcessor) takeTimer(roomID string, entry *roomTimer) bool {
+	rtp.timerMutex.Lock()
+	defer rtp.timerMutex.Unlock()
+
+	if current, exists := rtp.timers[roomID]; !exists || current != entry {
+		return false
+	}
+	delete(rtp.timers, roomID)
+	return true
+}
+
+// forceCardPlay 回合超时处理：按房间超时策略自动出牌或跳过回合，连续超时达到上限时判负
+func (rtp *RoomTimerProcessor) forceCardPlay(roomID, playerName string) error {
+	roomManager := service.GetRoomManager()
+	room, err := roomManager.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if room.Status != "playing" || room.IsPaused() {
+		return nil
+	}
+
+	// 确认超时玩家仍为当前回合玩家
+	if currentPlayer, exists := room.GetCurrentPlayer(); !exists || currentPlayer != playerName {
+		return nil
+	}
+
+	// 记录连续超时次数，达到上限则判负
+	timeouts, err := room.IncrementPlayerTimeouts(playerName)
+	if err != nil {
+		return err
+	}
+	if room.MaxConsecutiveTimeouts > 0 && timeouts >= room.MaxConsecutiveTimeouts {
+		return rtp.forfeitPlayer(room, playerName, timeouts)
+	}
+
+	processor := NewPlayCardProcessor()
+	gameEnded := false
+
+	switch room.TimeoutPolicy {
+	case types.TimeoutPolicyAutoPlay:
+		gameEnded, err = rtp.autoPlayLowestCard(processor, room, playerName)
+	default:
+		gameEnded, err = processor.PassTurn(room, playerName)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply timeout policy %s for player %s: %v", room.TimeoutPolicy, playerName, err)
+	}
+
 	// 发布游戏状态更新事件
-	stateUpdateData := events.NewEventData(events.EventGameStateUpdate, "force_cardplay_processor", map[string]interface{}{})
-	stateUpdateData.SetRoom(roomID)
-	events.Publish(events.EventGameStateUpdate, stateUpdateData)
+	if !gameEnded {
+		stateUpdateData := events.NewEventData(events.EventGameStateUpdate, "force_cardplay_processor", map[string]interface{}{
+			"timeout_player":       playerName,
+			"timeout_policy":       room.TimeoutPolicy,
+			"consecutive_timeouts": timeouts,
+		})
+		stateUpdateData.SetRoom(roomID)
+		events.Publish(events.EventGameStateUpdate, stateUpdateData)
+	}
+	return nil
+}
+
+// autoPlayLowestCard 自动打出玩家手牌中伤害最低的一张卡牌，手牌为空时跳过回合
+func (rtp *RoomTimerProcessor) autoPlayLowestCard(processor *PlayCardProcessor, room *types.RoomInfo, playerName string) (bool, error) {
+	handCards, err := room.GetPlayerHandCards(playerName)
+	if err != nil {
+		return false, err
+	}
+
+	if len(handCards) == 0 {
+		return processor.PassTurn(room, playerName)
+	}
+
+	lowestCard := handCards[0]
+	for _, card := range handCards[1:] {
+		if card.Damage < lowestCard.Damage {
+			lowestCard = card
+		}
+	}
+
+	_, gameEnded, err := processor.ExecutePlayCard(&PlayCardData{
+		RoomID:      room.RoomID,
+		Player:      playerName,
+		CardsToPlay: []models.Card{lowestCard},
+		TargetType:  "opponent",
+		Source:      "timeout",
+	})
+	return gameEnded, err
+}
+
+// forfeitPlayer 连续超时判负：淘汰该玩家，只剩一名存活玩家时通过游戏结束流程结算，否则继续游戏
+func (rtp *RoomTimerProcessor) forfeitPlayer(room *types.RoomInfo, playerName string, timeouts int) error {
+	gameEnded, err := NewPlayCardProcessor().EliminatePlayer(room, playerName, "timeout_forfeit")
+	if err != nil {
+		return err
+	}
+
+	if !gameEnded {
+		stateUpdateData := events.NewEventData(events.EventGameStateUpdate, "room_timer_processor", map[string]interface{}{
+			"eliminated_player":    playerName,
+			"reason":               "timeout_forfeit",
+			"consecutive_timeouts": timeouts,
+		})
+		stateUpdateData.SetRoom(room.RoomID)
+		events.Publish(events.EventGameStateUpdate, stateUpdateData)
+		return nil
+	}
+
+	gameEndData := events.NewEventData(events.EventGameEnd, "room_timer_processor", map[string]interface{}{
+		"reason":               "timeout_forfeit",
+		"loser":                playerName,
+		"consecutive_timeouts": timeouts,
+	})
+	gameEndData.SetRoom(room.RoomID)
+	events.Publish(events.EventGameEnd, gameEndData)
 	return nil
 }
 
@@ -119,10 +283,9 @@ func (rtp *RoomTimerProcessor) stopRoomTimer(roomID string) error {
 	defer rtp.timerMutex.Unlock()
 
 	// 查找并停止计时器
-	if timer, exists := rtp.timers[roomID]; exists {
-		timer.Stop()
+	if entry, exists := rtp.timers[roomID]; exists {
+		entry.timer.Stop()
 		delete(rtp.timers, roomID)
-	} else {
 	}
 
 	return nil
@@ -144,12 +307,12 @@ func (rtp *RoomTimerProcessor) CleanupAllTimers() {
 	rtp.timerMutex.Lock()
 	defer rtp.timerMutex.Unlock()
 
-	for _, timer := range rtp.timers {
-		timer.Stop()
+	for _, entry := range rtp.timers {
+		entry.timer.Stop()
 	}
 
 	// 清空计时器映射
-	rtp.timers = make(map[string]*time.Timer)
+	rtp.timers = make(map[string]*roomTimer)
 }
 
 // 全局便捷函数，供外部直接调用
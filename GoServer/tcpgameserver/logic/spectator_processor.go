@@ -0,0 +1,176 @@
+package logic
+
+import (
+	"log"
+	"time"
+
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/events"
+	"GoServer/tcpgameserver/models"
+	"GoServer/tcpgameserver/protocol"
+	"GoServer/tcpgameserver/service"
+	"GoServer/tcpgameserver/tools"
+	"GoServer/tcpgameserver/types"
+)
+
+// SpectatorProcessor 观战处理器，向观战者推送（可延迟的）公开游戏信息，并向玩家通知观战者进出
+type SpectatorProcessor struct {
+	Name string
+}
+
+// NewSpectatorProcessor 创建新的观战处理器
+func NewSpectatorProcessor() *SpectatorProcessor {
+	return &SpectatorProcessor{
+		Name: "SpectatorProcessor",
+	}
+}
+
+// 全局观战处理器实例
+var GlobalSpectatorProcessor = NewSpectatorProcessor()
+
+// ProcessSpectatorJoin 处理观战者加入：通知玩家 (消息码1604) 并向该观战者推送当前画面
+func (sp *SpectatorProcessor) ProcessSpectatorJoin(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	username, _ := eventData.GetString("username")
+
+	room, err := service.GetRoomManager().GetRoom(eventData.RoomID)
+	if err != nil {
+		return
+	}
+	sp.announce(room, 1604, username)
+
+	view, err := service.GetRoomManager().GetSpectatorGameInfo(room.RoomID)
+	if err != nil {
+		return
+	}
+	response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(1602, view)
+	roomID := room.RoomID
+	sp.deliver(func() {
+		sp.sendToSpectator(username, roomID, response)
+	})
+}
+
+// ProcessSpectatorLeave 处理观战者离开：通知玩家 (消息码1605)
+func (sp *SpectatorProcessor) ProcessSpectatorLeave(data interface{}) {
+	eventData, ok := data.(*events.EventData)
+	if !ok {
+		return
+	}
+	username, _ := eventData.GetString("username")
+
+	room, err := service.GetRoomManager().GetRoom(eventData.RoomID)
+	if err != nil {
+		return
+	}
+	sp.announce(room, 1605, username)
+}
+
+// BroadcastView 向房间内所有观战者推送当前的公开游戏信息 (消息码1602)
+// 画面在调用时生成，按配置延迟后发送
+func (sp *SpectatorProcessor) BroadcastView(room *types.RoomInfo) {
+	if len(room.GetSpectators()) == 0 {
+		return
+	}
+
+	view, err := service.GetRoomManager().GetSpectatorGameInfo(room.RoomID)
+	if err != nil {
+		return
+	}
+	response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(1602, view)
+	roomID := room.RoomID
+	sp.deliver(func() {
+		for _, username := range room.GetSpectators() {
+			sp.sendToSpectator(username, roomID, response)
+		}
+	})
+}
+
+// EndSpectating 游戏结束时向观战者推送结果 (消息码1606)，并将观战者恢复为已登录状态
+// 结果与观战画面使用相同的延迟，观战者先收到延迟中的画面再离开房间
+func (sp *SpectatorProcessor) EndSpectating(room *types.RoomInfo, winner string) {
+	spectators := room.GetSpectators()
+	if len(spectators) == 0 {
+		return
+	}
+
+	view, _ := service.GetRoomManager().GetSpectatorGameInfo(room.RoomID)
+	response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(1606, map[string]interface{}{
+		"RoomID":     room.RoomID,
+		"Winner":     winner,
+		"FinalState": view,
+	})
+
+	roomID := room.RoomID
+	sp.deliver(func() {
+		connManager := service.GetConnectionManager()
+		for _, username := range spectators {
+			room.RemoveSpectator(username)
+
+			// 延迟期间已离开的观战者不再接收结果
+			clientInfo, exists := connManager.GetConnectionByUsername(username)
+			if !exists || clientInfo.GetStatus() != types.StatusSpectating || clientInfo.GetGameRoom() != roomID {
+				continue
+			}
+			clientInfo.SetStatus(types.StatusLoggedIn)
+			clientInfo.SetGameRoom("")
+			sp.sendToUser(username, response)
+		}
+	})
+}
+
+// StopSpectating 观战者离开房间并恢复为已登录状态（用于断线）
+func (sp *SpectatorProcessor) StopSpectating(clientInfo *types.ClientInfo) {
+	roomID := clientInfo.GetGameRoom()
+	clientInfo.SetStatus(types.StatusLoggedIn)
+	clientInfo.SetGameRoom("")
+
+	room, err := service.GetRoomManager().GetRoom(roomID)
+	if err != nil || !room.RemoveSpectator(clientInfo.Username) {
+		return
+	}
+	sp.announce(room, 1605, clientInfo.Username)
+}
+
+// announce 向房间内玩家通知观战者进出
+func (sp *SpectatorProcessor) announce(room *types.RoomInfo, code int, username string) {
+	response := tools.GlobalResponseHelper.CreateSuccessTcpResponse(code, map[string]interface{}{
+		"Username":   username,
+		"Spectators": len(room.GetSpectators()),
+	})
+	for _, playerName := range room.GetTurnOrder() {
+		sp.sendToUser(playerName, response)
+	}
+}
+
+// deliver 按配置的观战延迟执行发送
+func (sp *SpectatorProcessor) deliver(send func()) {
+	delay := config.GetSpectatorDelay()
+	if delay <= 0 {
+		send()
+		return
+	}
+	time.AfterFunc(delay, send)
+}
+
+// sendToSpectator 向仍在观战该房间的用户发送消息（延迟期间离开的观战者不再接收）
+func (sp *SpectatorProcessor) sendToSpectator(username, roomID string, response *models.TcpResponse) {
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByUsername(username)
+	if !exists || clientInfo.GetStatus() != types.StatusSpectating || clientInfo.GetGameRoom() != roomID {
+		return
+	}
+	sp.sendToUser(username, response)
+}
+
+// sendToUser 向指定用户发送消息
+func (sp *SpectatorProcessor) sendToUser(username string, response *models.TcpResponse) {
+	clientInfo, exists := service.GetConnectionManager().GetConnectionByUsername(username)
+	if !exists || clientInfo.Conn == nil {
+		return
+	}
+	if err := protocol.WriteJSON(clientInfo.Conn, response); err != nil {
+		log.Printf("Failed to send spectator message %s to %s: %v", response.Code, username, err)
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+// DefaultBondHintLimit 羁绊提示默认返回数量
+const DefaultBondHintLimit = 5
+
+// PreviewPlayRequest 预览出牌请求，只计算羁绊结果，不改变游戏状态
+type PreviewPlayRequest struct {
+	Cards []Card `json:"Cards"`
+}
+
+// BondHintsRequest 羁绊提示请求
+type BondHintsRequest struct {
+	Limit int `json:"Limit"` // 返回数量，未设置时使用默认值
+}
+
+// BondHint 羁绊提示：手牌可以完成或接近完成的羁绊
+type BondHint struct {
+	Bond         BondModel `json:"Bond"`
+	MissingCards []string  `json:"MissingCards"` // 完成羁绊还缺少的卡牌名称
+	Complete     bool      `json:"Complete"`     // 当前手牌是否已可触发该羁绊
+}
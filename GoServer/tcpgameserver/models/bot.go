@@ -0,0 +1,42 @@
+package models
+
+import "strings"
+
+// 机器人难度
+const (
+	BotLevelEasy   = "easy"   // 随机打出一张卡牌，不合成
+	BotLevelNormal = "normal" // 合成后打出伤害最高的最多3张卡牌
+	BotLevelHard   = "hard"   // 合成后优先斩杀和触发羁绊，集火血量最低的对手
+)
+
+// BotUsernamePrefix 机器人用户名前缀，玩家不能注册该前缀的用户名
+const BotUsernamePrefix = "Bot_"
+
+// ValidBotLevel 判断机器人难度是否有效
+func ValidBotLevel(level string) bool {
+	switch level {
+	case BotLevelEasy, BotLevelNormal, BotLevelHard:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsBotUsername 判断用户名是否属于机器人（不区分大小写）
+func IsBotUsername(username string) bool {
+	return strings.HasPrefix(strings.ToLower(username), strings.ToLower(BotUsernamePrefix))
+}
+
+// PracticeRequest 人机练习请求参数
+type PracticeRequest struct {
+	Level    string `json:"Level"`    // 机器人难度：easy, normal, hard，为空时默认为normal
+	RoomSize int    `json:"RoomSize"` // 房间人数（2-4），除发起玩家外均为机器人，为空时默认为2
+	Seed     int64  `json:"Seed"`     // 开局使用的固定随机种子（仅管理员可用），0表示随机
+}
+
+// PracticeInfo 人机练习开始时返回的信息
+type PracticeInfo struct {
+	RoomID string   `json:"RoomID"`
+	Level  string   `json:"Level"`
+	Bots   []string `json:"Bots"` // 机器人用户名
+}
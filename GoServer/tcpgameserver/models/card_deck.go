@@ -2,10 +2,10 @@ package models
 
 // CardDeck 卡组结构体
 type CardDeck struct {
-	ID         int     `json:"id"`
-	Name       string  `json:"name"`
-	CardsNum   int     `json:"cards_num"`
-	Damage     float64 `json:"damage"`
-	TargetName *string `json:"targetname,omitempty"`
-	Level      int     `json:"level"`
+	ID         int     `json:"id" yaml:"id"`
+	Name       string  `json:"name" yaml:"name"`
+	CardsNum   int     `json:"cards_num" yaml:"cards_num"`
+	Damage     float64 `json:"damage" yaml:"damage"`
+	TargetName *string `json:"targetname,omitempty" yaml:"targetname,omitempty"`
+	Level      int     `json:"level" yaml:"level"`
 }
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// CardSet 卡牌、羁绊和合成配方定义的只读快照，热加载时整体替换，进行中的房间继续使用开局时的快照
+type CardSet struct {
+	Version     string             // 版本号，由卡牌和羁绊定义内容计算得出
+	LoadedAt    time.Time          // 加载时间
+	Templates   []Card             // 卡牌模板，每种卡牌一个
+	Level1Cards []Card             // 1级卡牌池
+	Level2Cards []Card             // 2级卡牌池
+	Level3Cards []Card             // 3级卡牌池
+	Bonds       map[int]*BondModel // 羁绊ID -> 羁绊模型
+	Recipes     []ComposeRecipe    // 合成配方，按匹配顺序排列
+}
+
+// GetCardByName 根据卡牌名称获取卡牌模板副本
+func (cs *CardSet) GetCardByName(cardName string) (*Card, error) {
+	for _, card := range cs.Templates {
+		if card.Name == cardName {
+			foundCard := card
+			return &foundCard, nil
+		}
+	}
+	return nil, fmt.Errorf("card with name '%s' not found in card set %s", cardName, cs.Version)
+}
+
+// GetCardByID 根据卡牌ID获取卡牌模板
+func (cs *CardSet) GetCardByID(cardID int) (Card, bool) {
+	for _, card := range cs.Templates {
+		if card.ID == cardID {
+			return card, true
+		}
+	}
+	return Card{}, false
+}
+
+// GetBondByID 根据ID获取羁绊
+func (cs *CardSet) GetBondByID(bondID int) (*BondModel, bool) {
+	bond, exists := cs.Bonds[bondID]
+	return bond, exists
+}
+
+// GetAllBonds 获取所有羁绊（副本映射）
+func (cs *CardSet) GetAllBonds() map[int]*BondModel {
+	result := make(map[int]*BondModel, len(cs.Bonds))
+	for id, bond := range cs.Bonds {
+		result[id] = bond
+	}
+	return result
+}
+
+// GetCardPools 获取各等级卡牌池副本
+func (cs *CardSet) GetCardPools() (level1, level2, level3 []Card) {
+	return copyCards(cs.Level1Cards), copyCards(cs.Level2Cards), copyCards(cs.Level3Cards)
+}
+
+// copyCards 复制卡牌切片
+func copyCards(cards []Card) []Card {
+	result := make([]Card, len(cards))
+	copy(result, cards)
+	return result
+}
@@ -0,0 +1,42 @@
+package models
+
+// DefaultComposeCount 默认配方消耗的相同卡牌数量
+const DefaultComposeCount = 3
+
+// ComposeRecipe 合成配方：消耗指定的卡牌，按成功率产出结果卡牌，成功时按奖励概率额外产出奖励卡牌
+type ComposeRecipe struct {
+	ID          int                `json:"ID" yaml:"ID"`                                       // 配方ID，由卡牌合成目标生成的默认配方为0
+	Name        string             `json:"Name" yaml:"Name"`                                   // 配方名称
+	Ingredients []RecipeIngredient `json:"Ingredients" yaml:"Ingredients"`                     // 消耗的卡牌，可以混合多种卡牌
+	Result      string             `json:"Result" yaml:"Result"`                               // 产出的卡牌名称
+	SuccessRate float64            `json:"SuccessRate" yaml:"SuccessRate"`                     // 成功概率(0,1]，失败时消耗卡牌但不产出
+	BonusResult string             `json:"BonusResult,omitempty" yaml:"BonusResult,omitempty"` // 奖励卡牌名称，为空时奖励一张结果卡牌
+	BonusRate   float64            `json:"BonusRate,omitempty" yaml:"BonusRate,omitempty"`     // 成功后额外产出奖励卡牌的概率，0表示没有奖励
+}
+
+// RecipeIngredient 配方消耗的卡牌
+type RecipeIngredient struct {
+	CardName string `json:"CardName" yaml:"CardName"` // 卡牌名称
+	Count    int    `json:"Count" yaml:"Count"`       // 消耗数量
+}
+
+// GetBonusResult 获取奖励卡牌名称，未配置时为结果卡牌
+func (r *ComposeRecipe) GetBonusResult() string {
+	if r.BonusResult != "" {
+		return r.BonusResult
+	}
+	return r.Result
+}
+
+// TargetComposeRecipe 根据卡牌的合成目标生成默认配方：3张相同卡牌必定合成目标卡牌
+func TargetComposeRecipe(card CardDeck) (ComposeRecipe, bool) {
+	if card.TargetName == nil || *card.TargetName == "" {
+		return ComposeRecipe{}, false
+	}
+	return ComposeRecipe{
+		Name:        card.Name + " -> " + *card.TargetName,
+		Ingredients: []RecipeIngredient{{CardName: card.Name, Count: DefaultComposeCount}},
+		Result:      *card.TargetName,
+		SuccessRate: 1,
+	}, true
+}
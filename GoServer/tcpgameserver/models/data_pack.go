@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DataPackFormatVersion 当前支持的数据包格式版本
+const DataPackFormatVersion = 1
+
+// DataPack 卡牌、羁绊、合成配方和响应码数据包，可保存为JSON或YAML文件代替MySQL中的数据
+type DataPack struct {
+	FormatVersion int             `json:"format_version" yaml:"format_version"`       // 数据包格式版本
+	Version       string          `json:"version" yaml:"version"`                     // 数据包内容版本，由维护者填写
+	Cards         []CardDeck      `json:"cards" yaml:"cards"`                         // 卡组配置，对应CardDeck表
+	Bonds         []BondModel     `json:"bonds" yaml:"bonds"`                         // 羁绊及关联卡牌，对应Bonds和BondCards表
+	Recipes       []ComposeRecipe `json:"recipes,omitempty" yaml:"recipes,omitempty"` // 合成配方，对应ComposeRecipes和ComposeRecipeIngredients表
+	ResponseCodes []ResponseInfo  `json:"response_codes" yaml:"response_codes"`       // 响应码，对应ResponseInfo表
+}
+
+// Validate 检查数据包的完整性：ID和卡牌名称不重复，羁绊、合成配方引用的卡牌和合成目标卡牌都必须存在
+// 返回所有发现的问题，没有问题时返回nil
+func (dp *DataPack) Validate() error {
+	var problems []error
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if dp.FormatVersion != DataPackFormatVersion {
+		addProblem("unsupported format_version %d (expected %d)", dp.FormatVersion, DataPackFormatVersion)
+	}
+
+	cardIDs := make(map[int]bool, len(dp.Cards))
+	cardNames := make(map[string]bool, len(dp.Cards))
+	for _, card := range dp.Cards {
+		if card.ID <= 0 {
+			addProblem("card %q has invalid id %d", card.Name, card.ID)
+		} else if cardIDs[card.ID] {
+			addProblem("duplicate card id %d", card.ID)
+		}
+		cardIDs[card.ID] = true
+
+		if card.Name == "" {
+			addProblem("card %d has no name", card.ID)
+		} else if cardNames[card.Name] {
+			addProblem("duplicate card name %q", card.Name)
+		}
+		cardNames[card.Name] = true
+
+		if card.Level < 1 || card.Level > 3 {
+			addProblem("card %q has invalid level %d", card.Name, card.Level)
+		}
+		if card.CardsNum < 0 {
+			addProblem("card %q has negative cards_num %d", card.Name, card.CardsNum)
+		}
+	}
+
+	// 合成目标卡牌必须存在
+	for _, card := range dp.Cards {
+		if card.TargetName != nil && *card.TargetName != "" && !cardNames[*card.TargetName] {
+			addProblem("card %q composes into unknown card %q", card.Name, *card.TargetName)
+		}
+	}
+
+	bondIDs := make(map[int]bool, len(dp.Bonds))
+	for _, bond := range dp.Bonds {
+		if bond.ID <= 0 {
+			addProblem("bond %q has invalid id %d", bond.Name, bond.ID)
+		} else if bondIDs[bond.ID] {
+			addProblem("duplicate bond id %d", bond.ID)
+		}
+		bondIDs[bond.ID] = true
+
+		if len(bond.CardNames) == 0 {
+			addProblem("bond %q has no cards", bond.Name)
+		}
+		// 羁绊引用的卡牌必须存在
+		for _, cardName := range bond.CardNames {
+			if !cardNames[cardName] {
+				addProblem("bond %q references unknown card %q", bond.Name, cardName)
+			}
+		}
+	}
+
+	recipeIDs := make(map[int]bool, len(dp.Recipes))
+	for _, recipe := range dp.Recipes {
+		if recipe.ID <= 0 {
+			addProblem("recipe %q has invalid id %d", recipe.Name, recipe.ID)
+		} else if recipeIDs[recipe.ID] {
+			addProblem("duplicate recipe id %d", recipe.ID)
+		}
+		recipeIDs[recipe.ID] = true
+
+		if len(recipe.Ingredients) == 0 {
+			addProblem("recipe %q has no ingredients", recipe.Name)
+		}
+		for _, ingredient := range recipe.Ingredients {
+			if !cardNames[ingredient.CardName] {
+				addProblem("recipe %q consumes unknown card %q", recipe.Name, ingredient.CardName)
+			}
+			if ingredient.Count <= 0 {
+				addProblem("recipe %q has invalid count %d for card %q", recipe.Name, ingredient.Count, ingredient.CardName)
+			}
+		}
+		if !cardNames[recipe.Result] {
+			addProblem("recipe %q produces unknown card %q", recipe.Name, recipe.Result)
+		}
+		if recipe.SuccessRate <= 0 || recipe.SuccessRate > 1 {
+			addProblem("recipe %q has invalid success_rate %v", recipe.Name, recipe.SuccessRate)
+		}
+		if recipe.BonusRate < 0 || recipe.BonusRate > 1 {
+			addProblem("recipe %q has invalid bonus_rate %v", recipe.Name, recipe.BonusRate)
+		}
+		if recipe.BonusResult != "" && !cardNames[recipe.BonusResult] {
+			addProblem("recipe %q rewards unknown card %q", recipe.Name, recipe.BonusResult)
+		}
+	}
+
+	responseIDs := make(map[int]bool, len(dp.ResponseCodes))
+	for _, response := range dp.ResponseCodes {
+		if responseIDs[response.ID] {
+			addProblem("duplicate response code id %d", response.ID)
+		}
+		responseIDs[response.ID] = true
+	}
+
+	return errors.Join(problems...)
+}
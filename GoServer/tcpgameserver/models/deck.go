@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 卡组校验错误
+var (
+	ErrDeckSize         = errors.New("deck size out of range")
+	ErrDeckCopyLimit    = errors.New("too many copies of a card")
+	ErrDeckCardNotOwned = errors.New("card not owned")
+	ErrDeckInvalidCard  = errors.New("card cannot be used in decks")
+)
+
+// DeckRules 组卡规则
+type DeckRules struct {
+	MinSize   int `json:"MinSize"`   // 卡组最少卡牌数
+	MaxSize   int `json:"MaxSize"`   // 卡组最多卡牌数
+	MaxCopies int `json:"MaxCopies"` // 同名卡牌最多张数
+	MaxDecks  int `json:"MaxDecks"`  // 每名玩家最多保存的卡组数
+}
+
+// DeckCard 卡组或收藏中的一种卡牌及数量
+type DeckCard struct {
+	CardID   int `json:"CardID"`
+	Quantity int `json:"Quantity"`
+}
+
+// CollectionCard 玩家收藏中的一种卡牌
+type CollectionCard struct {
+	CardID   int     `json:"CardID"`
+	Name     string  `json:"Name"`
+	Level    int     `json:"Level"`
+	Damage   float64 `json:"Damage"`
+	Quantity int     `json:"Quantity"` // 拥有的张数
+}
+
+// Deck 玩家保存的卡组
+type Deck struct {
+	Name      string     `json:"Name"`
+	Cards     []DeckCard `json:"Cards"`
+	Size      int        `json:"Size"`
+	UpdatedAt time.Time  `json:"UpdatedAt"`
+}
+
+// SaveDeckRequest 保存卡组请求，同名卡组会被覆盖
+type SaveDeckRequest struct {
+	Name  string     `json:"Name"`
+	Cards []DeckCard `json:"Cards"`
+}
+
+// DeckNameRequest 按名称操作卡组的请求（删除、选择），选择时名称为空表示不使用卡组
+type DeckNameRequest struct {
+	Name string `json:"Name"`
+}
+
+// MergeDeckCards 合并同一卡牌的多条记录并按卡牌ID排序，忽略数量不大于0的记录
+func MergeDeckCards(cards []DeckCard) []DeckCard {
+	quantities := make(map[int]int)
+	for _, card := range cards {
+		if card.Quantity > 0 {
+			quantities[card.CardID] += card.Quantity
+		}
+	}
+
+	merged := make([]DeckCard, 0, len(quantities))
+	for cardID, quantity := range quantities {
+		merged = append(merged, DeckCard{CardID: cardID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].CardID < merged[j].CardID })
+	return merged
+}
+
+// DeckSize 计算卡组卡牌总数
+func DeckSize(cards []DeckCard) int {
+	size := 0
+	for _, card := range cards {
+		size += card.Quantity
+	}
+	return size
+}
+
+// Validate 按组卡规则检查卡组：卡牌总数在范围内、只包含卡牌集中的1级卡牌、
+// 同名卡牌不超过张数上限且不超过玩家拥有的数量
+func (r DeckRules) Validate(cards []DeckCard, owned map[int]int, cardSet *CardSet) error {
+	if size := DeckSize(cards); size < r.MinSize || size > r.MaxSize {
+		return fmt.Errorf("%w: %d cards (allowed %d-%d)", ErrDeckSize, size, r.MinSize, r.MaxSize)
+	}
+
+	for _, card := range cards {
+		template, exists := cardSet.GetCardByID(card.CardID)
+		if !exists || template.Level != 1 {
+			return fmt.Errorf("%w: card %d", ErrDeckInvalidCard, card.CardID)
+		}
+		if card.Quantity > r.MaxCopies {
+			return fmt.Errorf("%w: %d copies of %s (max %d)", ErrDeckCopyLimit, card.Quantity, template.Name, r.MaxCopies)
+		}
+		if card.Quantity > owned[card.CardID] {
+			return fmt.Errorf("%w: %d copies of %s (owned %d)", ErrDeckCardNotOwned, card.Quantity, template.Name, owned[card.CardID])
+		}
+	}
+	return nil
+}
+
+// StarterCollection 新玩家的初始收藏：卡牌集中每种1级卡牌各copies张
+func StarterCollection(cardSet *CardSet, copies int) []DeckCard {
+	starter := make([]DeckCard, 0)
+	for _, card := range cardSet.Templates {
+		if card.Level == 1 {
+			starter = append(starter, DeckCard{CardID: card.ID, Quantity: copies})
+		}
+	}
+	return starter
+}
@@ -0,0 +1,12 @@
+package models
+
+// GameDataReloadResult 游戏数据热加载结果
+type GameDataReloadResult struct {
+	CardSetVersion  string `json:"CardSetVersion"`  // 新的卡牌集版本号，之后开局的房间使用该版本
+	PreviousVersion string `json:"PreviousVersion"` // 替换前的卡牌集版本号，进行中的房间继续使用
+	PackVersion     string `json:"PackVersion"`     // 数据包内容版本，从MySQL读取时为空
+	Cards           int    `json:"Cards"`           // 卡牌种类数量
+	Bonds           int    `json:"Bonds"`           // 羁绊数量
+	Recipes         int    `json:"Recipes"`         // 合成配方数量，包含按卡牌合成目标生成的默认配方
+	ResponseCodes   int    `json:"ResponseCodes"`   // 响应码数量
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// 对局结果
+const (
+	MatchResultWin  = "win"
+	MatchResultLoss = "loss"
+	MatchResultDraw = "draw"
+)
+
+// MatchRecord 对局记录
+type MatchRecord struct {
+	ID           int64              `json:"ID"`
+	RoomID       string             `json:"Room_Id"`
+	Winner       string             `json:"Winner"`    // 胜者，平局为空
+	EndReason    string             `json:"EndReason"` // 结束原因
+	TurnCount    int                `json:"TurnCount"` // 回合数
+	StartedAt    time.Time          `json:"StartedAt"`
+	EndedAt      time.Time          `json:"EndedAt"`
+	Participants []MatchParticipant `json:"Participants"`
+}
+
+// MatchParticipant 对局参与者记录
+type MatchParticipant struct {
+	Username       string         `json:"Username"`
+	Result         string         `json:"Result"` // win, loss, draw
+	FinalHealth    float64        `json:"FinalHealth"`
+	DamageDealt    float64        `json:"DamageDealt"`
+	DamageTaken    float64        `json:"DamageTaken"`
+	BondsTriggered map[string]int `json:"BondsTriggered"` // 羁绊名称 -> 触发次数
+}
+
+// PlayerStats 玩家对局汇总统计
+type PlayerStats struct {
+	Username           string  `json:"Username"`
+	TotalMatches       int     `json:"TotalMatches"`
+	Wins               int     `json:"Wins"`
+	Losses             int     `json:"Losses"`
+	Draws              int     `json:"Draws"`
+	FavouriteBond      string  `json:"FavouriteBond"` // 触发次数最多的羁绊
+	AverageDamageDealt float64 `json:"AverageDamageDealt"`
+	AverageDamageTaken float64 `json:"AverageDamageTaken"`
+}
+
+// MatchHistoryQuery 对局历史查询请求
+type MatchHistoryQuery struct {
+	Username string `json:"username"` // 查询的用户名，为空时查询自己
+	Limit    int    `json:"limit"`    // 返回的最大对局数量
+}
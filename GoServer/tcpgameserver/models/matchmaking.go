@@ -0,0 +1,35 @@
+package models
+
+// DefaultPlayerRating 新玩家的初始评分
+const DefaultPlayerRating = 1500.0
+
+// 房间人数范围，超过2人为多人混战
+const (
+	MinRoomSize = 2
+	MaxRoomSize = 4
+)
+
+// PlayerRating 玩家匹配评分（ELO）
+type PlayerRating struct {
+	Username    string  `json:"Username"`
+	Rating      float64 `json:"Rating"`
+	GamesPlayed int     `json:"GamesPlayed"`
+}
+
+// ReadyRequest 准备请求参数（可选）
+type ReadyRequest struct {
+	RoomSize int    `json:"RoomSize"` // 期望的房间人数（2-4），为空时默认为2
+	Deck     string `json:"Deck"`     // 使用的卡组名称，为空时沿用之前选择的卡组
+}
+
+// QueueStatus 匹配队列状态
+type QueueStatus struct {
+	Username             string  `json:"Username"`
+	Rating               float64 `json:"Rating"`
+	RoomSize             int     `json:"RoomSize"`             // 匹配的房间人数
+	Position             int     `json:"Position"`             // 队列位置，从1开始
+	QueueSize            int     `json:"QueueSize"`            // 队列人数
+	WaitedSeconds        float64 `json:"WaitedSeconds"`        // 已等待时间
+	RatingWindow         float64 `json:"RatingWindow"`         // 当前可匹配的评分差
+	EstimatedWaitSeconds float64 `json:"EstimatedWaitSeconds"` // 预计剩余等待时间，-1表示无法估算
+}
@@ -0,0 +1,6 @@
+package models
+
+// RespondDrawRequest 回应和棋提议的请求数据
+type RespondDrawRequest struct {
+	Accept bool `json:"Accept"` // 是否同意和棋
+}
@@ -2,26 +2,41 @@ package models
 
 // ResponseInfo 响应信息结构体
 type PlayerGameInfo struct {
-	RoomId       string                `json:"Room_Id"`
-	Username     string                `json:"Username"`
-	Round        string                `json:"Round"`
-	Health       float64               `json:"Health"`
-	SelfCards    []Card                `json:"SelfCards"`
-	OtherPlayers []OtherPlayerGameInfo `json:"OtherPlayers"`
-	DamageInfo   []DamageInfo          `json:"DamageInfo"`
+	RoomId         string                `json:"Room_Id"`
+	Username       string                `json:"Username"`
+	Round          string                `json:"Round"`
+	Health         float64               `json:"Health"`
+	SelfCards      []Card                `json:"SelfCards"`
+	OtherPlayers   []OtherPlayerGameInfo `json:"OtherPlayers"`
+	DamageInfo     []DamageInfo          `json:"DamageInfo"`
+	Target         string                `json:"Target,omitempty"`         // 出牌目标：玩家用户名、all 或 self，仅出牌请求使用
+	StatusEffects  []StatusEffect        `json:"StatusEffects"`            // 自身的状态效果
+	CardSetVersion string                `json:"CardSetVersion,omitempty"` // 对局使用的卡牌集版本号
 }
 
 type OtherPlayerGameInfo struct {
-	Username   string  `json:"Username"`
-	Round      string  `json:"Round"`
-	Health     float64 `json:"Health"`
-	CardsCount int     `json:"CardsCount"`
+	Username      string         `json:"Username"`
+	Round         string         `json:"Round"`
+	Health        float64        `json:"Health"`
+	CardsCount    int            `json:"CardsCount"`
+	StatusEffects []StatusEffect `json:"StatusEffects"` // 该玩家的状态效果
 }
 
 type DamageInfo struct {
-	DamageSource   string      `json:"DamageSource"`
-	DamageTarget   string      `json:"DamageTarget"`
-	DamageType     string      `json:"DamageType"`
-	DamageValue    float64     `json:"DamageValue"`
-	TriggeredBonds []BondModel `json:"TriggeredBonds"`
+	DamageSource   string            `json:"DamageSource"`
+	DamageTarget   string            `json:"DamageTarget"`
+	DamageType     string            `json:"DamageType"`
+	DamageValue    float64           `json:"DamageValue"`
+	TriggeredBonds []BondModel       `json:"TriggeredBonds"`
+	Effects        []SkillEffectInfo `json:"Effects,omitempty"` // 羁绊技能产生的效果
+}
+
+// SkillEffectInfo 羁绊技能效果记录
+type SkillEffectInfo struct {
+	Bond   string  `json:"Bond"`            // 触发效果的羁绊名称
+	Effect string  `json:"Effect"`          // 效果名称：heal, shield, draw, discard, dot, extra_turn
+	Source string  `json:"Source"`          // 效果施加方
+	Target string  `json:"Target"`          // 效果承受方
+	Value  float64 `json:"Value"`           // 效果数值（治疗量、护盾值、抽弃牌数量、每回合伤害）
+	Turns  int     `json:"Turns,omitempty"` // 持续回合数，仅持续伤害有效
 }
@@ -0,0 +1,76 @@
+package models
+
+// RoomSettings 房间规则设置，请求中为0（指针字段为空）的字段表示沿用预设或当前值
+type RoomSettings struct {
+	MaxPlayers             int     `json:"MaxPlayers"`             // 最大玩家数量（2-4）
+	InitialHealth          float64 `json:"InitialHealth"`          // 初始血量
+	MaxHandCards           int     `json:"MaxHandCards"`           // 最大手牌数量
+	TurnTimeoutSeconds     int     `json:"TurnTimeoutSeconds"`     // 回合时长（秒）
+	OpeningHandSize        int     `json:"OpeningHandSize"`        // 初始手牌数量
+	ReconnectWindowSeconds int     `json:"ReconnectWindowSeconds"` // 断线重连等待时间（秒），超时未重连判负
+	FatigueDamage          float64 `json:"FatigueDamage"`          // 卡牌池和弃牌堆都为空时每少抽一张卡牌受到的伤害，0表示关闭
+	TimeoutPolicy          string  `json:"TimeoutPolicy"`          // 回合超时策略：pass, auto_play
+	MaxConsecutiveTimeouts *int    `json:"MaxConsecutiveTimeouts"` // 连续超时判负次数，0表示不判负
+}
+
+// CreateRoomRequest 创建私人房间请求
+type CreateRoomRequest struct {
+	RoomName string       `json:"RoomName"`
+	Preset   string       `json:"Preset"`   // 规则预设：standard, blitz, marathon，为空时使用 standard
+	Settings RoomSettings `json:"Settings"` // 覆盖预设中的部分设置
+	Seed     int64        `json:"Seed"`     // 开局使用的固定随机种子（仅管理员可用），0表示随机
+}
+
+// JoinRoomRequest 加入私人房间请求
+type JoinRoomRequest struct {
+	InviteCode string `json:"InviteCode"`
+}
+
+// UpdateRoomSettingsRequest 房主修改房间设置请求
+type UpdateRoomSettingsRequest struct {
+	Preset   string       `json:"Preset"`   // 规则预设，为空时在当前设置上修改
+	Settings RoomSettings `json:"Settings"` // 需要修改的设置
+}
+
+// RoomLobbyInfo 私人房间等待信息
+type RoomLobbyInfo struct {
+	RoomID     string       `json:"RoomID"`
+	RoomName   string       `json:"RoomName"`
+	InviteCode string       `json:"InviteCode"`
+	Host       string       `json:"Host"`
+	Status     string       `json:"Status"`
+	Players    []string     `json:"Players"` // 按座位顺序
+	Settings   RoomSettings `json:"Settings"`
+}
+
+// MergeRoomSettings 用覆盖设置中非零（指针字段非空）的字段替换基础设置
+func MergeRoomSettings(base, override RoomSettings) RoomSettings {
+	if override.MaxPlayers != 0 {
+		base.MaxPlayers = override.MaxPlayers
+	}
+	if override.InitialHealth != 0 {
+		base.InitialHealth = override.InitialHealth
+	}
+	if override.MaxHandCards != 0 {
+		base.MaxHandCards = override.MaxHandCards
+	}
+	if override.TurnTimeoutSeconds != 0 {
+		base.TurnTimeoutSeconds = override.TurnTimeoutSeconds
+	}
+	if override.OpeningHandSize != 0 {
+		base.OpeningHandSize = override.OpeningHandSize
+	}
+	if override.ReconnectWindowSeconds != 0 {
+		base.ReconnectWindowSeconds = override.ReconnectWindowSeconds
+	}
+	if override.FatigueDamage != 0 {
+		base.FatigueDamage = override.FatigueDamage
+	}
+	if override.TimeoutPolicy != "" {
+		base.TimeoutPolicy = override.TimeoutPolicy
+	}
+	if override.MaxConsecutiveTimeouts != nil {
+		base.MaxConsecutiveTimeouts = override.MaxConsecutiveTimeouts
+	}
+	return base
+}
@@ -0,0 +1,75 @@
+package models
+
+// 回放日志条目类型
+const (
+	ReplayEntryGameStart   = "game_start"   // 房间初始化（随机种子、卡牌池、房间设置）
+	ReplayEntryDeal        = "deal"         // 初始发牌
+	ReplayEntryFirstTurn   = "first_turn"   // 确定先手玩家
+	ReplayEntryPlayCard    = "play_card"    // 出牌
+	ReplayEntryComposeCard = "compose_card" // 合成卡牌
+	ReplayEntryPassTurn    = "pass_turn"    // 跳过回合
+	ReplayEntryEliminate   = "eliminate"    // 玩家被淘汰（多人对局中判负但游戏继续）
+	ReplayEntryGameEnd     = "game_end"     // 游戏结束
+)
+
+// ReplayEntry 回放日志条目，按Seq顺序追加写入
+type ReplayEntry struct {
+	Seq       int    `json:"seq"`
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+	RoomID    string `json:"room_id"`
+	Player    string `json:"player,omitempty"`
+	Source    string `json:"source,omitempty"` // 动作来源：user, timeout
+	Target    string `json:"target,omitempty"` // 出牌目标：玩家用户名、all 或 self
+
+	Start        *ReplayGameStart   `json:"start,omitempty"`         // game_start 专用
+	Cards        []Card             `json:"cards,omitempty"`         // 发牌/出牌/合成消耗的卡牌（已验证）
+	NewCards     []Card             `json:"new_cards,omitempty"`     // 合成得到或出牌后抽到的卡牌
+	BondResult   *ReplayBondResult  `json:"bond_result,omitempty"`   // 出牌的羁绊计算结果
+	HealthDeltas map[string]float64 `json:"health_deltas,omitempty"` // 本次动作造成的血量变化
+	Winner       string             `json:"winner,omitempty"`        // game_end 专用
+	Reason       string             `json:"reason,omitempty"`        // game_end、eliminate 专用
+	State        *ReplayState       `json:"state,omitempty"`         // 动作完成后的房间状态
+}
+
+// ReplayGameStart 房间初始数据
+type ReplayGameStart struct {
+	Seed           int64             `json:"seed"`
+	CardSetVersion string            `json:"card_set_version,omitempty"` // 对局使用的卡牌集版本号
+	Players        []string          `json:"players"`
+	InitialHealth  float64           `json:"initial_health"`
+	MaxHandCards   int               `json:"max_hand_cards"`
+	FatigueDamage  float64           `json:"fatigue_damage,omitempty"` // 疲劳伤害，0表示关闭
+	Level1CardPool []Card            `json:"level1_card_pool"`
+	Level2CardPool []Card            `json:"level2_card_pool"`
+	Level3CardPool []Card            `json:"level3_card_pool"`
+	DrawPiles      map[string][]Card `json:"draw_piles,omitempty"` // 使用卡组的玩家的抽牌堆，key为用户名
+}
+
+// ReplayBondResult 羁绊计算结果
+type ReplayBondResult struct {
+	TotalDamage    float64               `json:"total_damage"`
+	TriggeredBonds []ReplayTriggeredBond `json:"triggered_bonds"`
+}
+
+// ReplayTriggeredBond 触发的羁绊
+type ReplayTriggeredBond struct {
+	Name       string   `json:"name"`
+	BondDamage float64  `json:"bond_damage"`
+	CardUIDs   []string `json:"card_uids"`
+}
+
+// ReplayState 房间状态快照，用于回放校验
+type ReplayState struct {
+	Players         map[string]ReplayPlayerState `json:"players"`
+	Level1PoolCount int                          `json:"level1_pool_count"`
+	Level2PoolCount int                          `json:"level2_pool_count"`
+	Level3PoolCount int                          `json:"level3_pool_count"`
+}
+
+// ReplayPlayerState 玩家状态快照
+type ReplayPlayerState struct {
+	Health       float64  `json:"health"`
+	Round        string   `json:"round"`
+	HandCardUIDs []string `json:"hand_card_uids"` // 已排序
+}
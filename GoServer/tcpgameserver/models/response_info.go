@@ -2,10 +2,10 @@ package models
 
 // ResponseInfo 响应信息结构体
 type ResponseInfo struct {
-	ID          int    `json:"id"`
-	Code        string `json:"code"`
-	ResponseKey string `json:"responsekey"`
-	Message     string `json:"message"`
+	ID          int    `json:"id" yaml:"id"`
+	Code        string `json:"code" yaml:"code"`
+	ResponseKey string `json:"responsekey" yaml:"responsekey"`
+	Message     string `json:"message" yaml:"message"`
 }
 
 // TcpRequest TCP请求结构体，用于接收客户端数据
@@ -0,0 +1,10 @@
+package models
+
+// ServerStats 服务器运行状态，用于压力测试前后比较连接、房间和协程数量是否泄漏
+type ServerStats struct {
+	Connections  map[string]int `json:"Connections"`  // 按玩家状态统计的连接数量，total为连接总数
+	Rooms        int            `json:"Rooms"`        // 房间总数
+	PlayingRooms int            `json:"PlayingRooms"` // 进行中的房间数量
+	QueueSize    int            `json:"QueueSize"`    // 匹配队列人数
+	Goroutines   int            `json:"Goroutines"`   // 服务器协程数量
+}
@@ -0,0 +1,16 @@
+package models
+
+// SpectatorGameInfo 观战者看到的游戏信息，只包含公开信息（手牌数量，不含手牌内容）
+type SpectatorGameInfo struct {
+	RoomId     string                `json:"Room_Id"`
+	TurnCount  int                   `json:"TurnCount"`
+	Players    []OtherPlayerGameInfo `json:"Players"` // 按座位顺序
+	DamageInfo []DamageInfo          `json:"DamageInfo"`
+	Spectators int                   `json:"Spectators"`
+}
+
+// SpectateRoomRequest 观战请求，通过房间ID或邀请码指定房间
+type SpectateRoomRequest struct {
+	RoomID     string `json:"RoomID"`
+	InviteCode string `json:"InviteCode"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// 状态效果类型
+const (
+	StatusEffectShield = "shield" // 护盾：受到伤害时优先扣除护盾值
+	StatusEffectPoison = "poison" // 中毒：回合开始时受到伤害
+	StatusEffectStun   = "stun"   // 眩晕：跳过回合
+)
+
+// 状态效果叠加规则
+const (
+	StackAdd         = "add"         // 同类效果合并：数值累加，持续回合取较大值
+	StackRefresh     = "refresh"     // 同类效果合并：数值和持续回合均取较大值
+	StackIndependent = "independent" // 每次施加独立存在，各自结算
+)
+
+// StatusEffectStackRules 各类状态效果的叠加规则，未列出的类型独立叠加
+var StatusEffectStackRules = map[string]string{
+	StatusEffectShield: StackAdd,
+	StatusEffectPoison: StackIndependent,
+	StatusEffectStun:   StackRefresh,
+}
+
+// StatusEffect 附加在玩家身上的状态效果，在该玩家每个回合开始时结算并减少剩余回合
+type StatusEffect struct {
+	Type      string  `json:"Type"`      // 效果类型：shield, poison, stun
+	Source    string  `json:"Source"`    // 施加方
+	Bond      string  `json:"Bond"`      // 来源羁绊名称
+	Value     float64 `json:"Value"`     // 护盾剩余值或中毒每回合伤害
+	TurnsLeft int     `json:"TurnsLeft"` // 剩余回合数
+}
@@ -5,3 +5,21 @@ type UserAccount struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
+
+// LoginRequest 登录请求，使用用户名和密码、登录时下发的会话令牌或Voyara访问令牌登录
+type LoginRequest struct {
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	SessionToken string `json:"session_token"`
+	VoyaraToken  string `json:"voyara_token"` // Voyara访问令牌，首次登录时以username作为游戏用户名创建关联账户
+}
+
+// LinkVoyaraRequest 将当前登录的游戏账户关联到Voyara用户
+type LinkVoyaraRequest struct {
+	VoyaraToken string `json:"voyara_token"`
+}
+
+// ResumeSessionRequest 凭登录时下发的会话令牌恢复断线前的对局
+type ResumeSessionRequest struct {
+	SessionToken string `json:"session_token"`
+}
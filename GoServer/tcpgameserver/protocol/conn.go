@@ -0,0 +1,37 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// Conn 传输无关的客户端连接，TCP 与 WebSocket 连接均实现该接口
+type Conn interface {
+	WriteFrame(payload []byte) error // 写出一个完整的帧
+	RemoteAddr() net.Addr            // 客户端地址
+	Close() error                    // 关闭连接
+}
+
+// FrameReader 可按帧读取消息的连接
+type FrameReader interface {
+	Conn
+	ReadFrame() ([]byte, error)
+}
+
+// WriteFrame 按连接的帧格式写出消息
+func WriteFrame(conn Conn, payload []byte) error {
+	if conn == nil {
+		return fmt.Errorf("connection is nil")
+	}
+	return conn.WriteFrame(payload)
+}
+
+// WriteJSON 序列化消息并按帧写出
+func WriteJSON(conn Conn, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return WriteFrame(conn, payload)
+}
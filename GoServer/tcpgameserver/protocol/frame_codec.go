@@ -0,0 +1,154 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// FrameMode 消息分帧模式
+type FrameMode string
+
+const (
+	FrameModeLine   FrameMode = "line"   // 换行符分隔（默认，兼容旧客户端）
+	FrameModeLength FrameMode = "length" // 4字节大端长度前缀
+)
+
+const (
+	MaxFrameSize      = 64 * 1024 // 单帧最大字节数
+	lengthHeaderSize  = 4         // 长度前缀字节数
+	defaultReaderSize = 4096      // 读缓冲区大小
+)
+
+// ErrFrameTooLarge 帧超过最大长度
+var ErrFrameTooLarge = errors.New("frame exceeds maximum size")
+
+// ParseFrameMode 解析帧模式字符串
+func ParseFrameMode(value string) (FrameMode, error) {
+	switch FrameMode(value) {
+	case FrameModeLine:
+		return FrameModeLine, nil
+	case FrameModeLength:
+		return FrameModeLength, nil
+	default:
+		return "", fmt.Errorf("unsupported frame mode: %s", value)
+	}
+}
+
+// Decoder 帧解码器，缓存不完整的帧直到读取完整
+type Decoder struct {
+	reader  *bufio.Reader
+	mode    FrameMode
+	maxSize int
+}
+
+// NewDecoder 创建帧解码器
+func NewDecoder(r io.Reader, mode FrameMode) *Decoder {
+	reader, ok := r.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReaderSize(r, defaultReaderSize)
+	}
+	return &Decoder{
+		reader:  reader,
+		mode:    mode,
+		maxSize: MaxFrameSize,
+	}
+}
+
+// ReadFrame 读取一个完整的帧（不包含分隔符或长度前缀）
+func (d *Decoder) ReadFrame() ([]byte, error) {
+	switch d.mode {
+	case FrameModeLength:
+		return d.readLengthFrame()
+	default:
+		return d.readLineFrame()
+	}
+}
+
+// readLineFrame 读取以换行符结尾的帧，跳过空行
+func (d *Decoder) readLineFrame() ([]byte, error) {
+	for {
+		var frame []byte
+		for {
+			chunk, err := d.reader.ReadSlice('\n')
+			if len(frame)+len(chunk) > d.maxSize+1 {
+				return nil, ErrFrameTooLarge
+			}
+			frame = append(frame, chunk...)
+			if err == nil {
+				break
+			}
+			if err != bufio.ErrBufferFull {
+				return nil, err
+			}
+		}
+
+		frame = bytes.TrimSpace(frame)
+		if len(frame) > 0 {
+			return frame, nil
+		}
+	}
+}
+
+// readLengthFrame 读取带4字节长度前缀的帧，跳过空帧
+func (d *Decoder) readLengthFrame() ([]byte, error) {
+	header := make([]byte, lengthHeaderSize)
+	for {
+		if _, err := io.ReadFull(d.reader, header); err != nil {
+			return nil, err
+		}
+
+		size := binary.BigEndian.Uint32(header)
+		if size > uint32(d.maxSize) {
+			return nil, ErrFrameTooLarge
+		}
+		if size == 0 {
+			continue
+		}
+
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(d.reader, frame); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+}
+
+// Encoder 帧编码器
+type Encoder struct {
+	mode    FrameMode
+	maxSize int
+}
+
+// NewEncoder 创建帧编码器
+func NewEncoder(mode FrameMode) *Encoder {
+	return &Encoder{
+		mode:    mode,
+		maxSize: MaxFrameSize,
+	}
+}
+
+// Encode 将消息体编码为一个完整的帧
+func (e *Encoder) Encode(payload []byte) ([]byte, error) {
+	if len(payload) > e.maxSize {
+		return nil, ErrFrameTooLarge
+	}
+
+	switch e.mode {
+	case FrameModeLength:
+		frame := make([]byte, lengthHeaderSize+len(payload))
+		binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+		copy(frame[lengthHeaderSize:], payload)
+		return frame, nil
+	default:
+		if bytes.IndexByte(payload, '\n') >= 0 {
+			return nil, fmt.Errorf("line frame payload must not contain newline")
+		}
+		frame := make([]byte, 0, len(payload)+1)
+		frame = append(frame, payload...)
+		return append(frame, '\n'), nil
+	}
+}
@@ -0,0 +1,180 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// lengthFrame 构造带4字节长度前缀的帧
+func lengthFrame(payload string) string {
+	header := make([]byte, lengthHeaderSize)
+	binary.BigEndian.PutUint32(header, uint32(len(payload)))
+	return string(header) + payload
+}
+
+func TestDecoderReadFrame(t *testing.T) {
+	oversize := strings.Repeat("x", MaxFrameSize+1)
+	maxSize := strings.Repeat("y", MaxFrameSize)
+
+	tests := []struct {
+		name      string
+		mode      FrameMode
+		input     string
+		oneByte   bool // 每次读取只返回一个字节，模拟不完整的帧
+		want      []string
+		wantErr   error
+		errAfterN int // 返回错误前成功读取的帧数
+	}{
+		{
+			name:  "line single frame",
+			mode:  FrameModeLine,
+			input: `{"message":"Ping"}` + "\n",
+			want:  []string{`{"message":"Ping"}`},
+		},
+		{
+			name:    "line partial frame",
+			mode:    FrameModeLine,
+			input:   `{"message":"Ping"}` + "\n",
+			oneByte: true,
+			want:    []string{`{"message":"Ping"}`},
+		},
+		{
+			name:  "line pipelined frames and blank lines",
+			mode:  FrameModeLine,
+			input: "a\n\r\n\nb\r\nc\n",
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "line frame at max size",
+			mode:  FrameModeLine,
+			input: maxSize + "\n",
+			want:  []string{maxSize},
+		},
+		{
+			name:    "line oversize frame",
+			mode:    FrameModeLine,
+			input:   "ok\n" + oversize + "\n",
+			want:    []string{"ok"},
+			wantErr: ErrFrameTooLarge,
+		},
+		{
+			name:    "line truncated frame",
+			mode:    FrameModeLine,
+			input:   "a\nunterminated",
+			want:    []string{"a"},
+			wantErr: io.EOF,
+		},
+		{
+			name:  "length single frame",
+			mode:  FrameModeLength,
+			input: lengthFrame(`{"message":"Ping"}`),
+			want:  []string{`{"message":"Ping"}`},
+		},
+		{
+			name:    "length partial frame",
+			mode:    FrameModeLength,
+			input:   lengthFrame("a\nb") + lengthFrame("c"),
+			oneByte: true,
+			want:    []string{"a\nb", "c"},
+		},
+		{
+			name:  "length pipelined frames and empty frames",
+			mode:  FrameModeLength,
+			input: lengthFrame("a") + lengthFrame("") + lengthFrame("bc") + lengthFrame("d"),
+			want:  []string{"a", "bc", "d"},
+		},
+		{
+			name:  "length frame at max size",
+			mode:  FrameModeLength,
+			input: lengthFrame(maxSize),
+			want:  []string{maxSize},
+		},
+		{
+			name:    "length oversize frame",
+			mode:    FrameModeLength,
+			input:   lengthFrame("ok") + lengthFrame(oversize),
+			want:    []string{"ok"},
+			wantErr: ErrFrameTooLarge,
+		},
+		{
+			name:    "length truncated frame",
+			mode:    FrameModeLength,
+			input:   lengthFrame("a") + lengthFrame("bcd")[:6],
+			want:    []string{"a"},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reader io.Reader = strings.NewReader(tt.input)
+			if tt.oneByte {
+				reader = iotest.OneByteReader(reader)
+			}
+			decoder := NewDecoder(reader, tt.mode)
+
+			for i, want := range tt.want {
+				frame, err := decoder.ReadFrame()
+				if err != nil {
+					t.Fatalf("frame %d: unexpected error %v", i, err)
+				}
+				if string(frame) != want {
+					t.Fatalf("frame %d = %.40q, want %.40q", i, frame, want)
+				}
+			}
+
+			wantErr := tt.wantErr
+			if wantErr == nil {
+				wantErr = io.EOF
+			}
+			if _, err := decoder.ReadFrame(); !errors.Is(err, wantErr) {
+				t.Fatalf("final error = %v, want %v", err, wantErr)
+			}
+		})
+	}
+}
+
+func TestEncoderEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    FrameMode
+		payload string
+		want    string
+		wantErr bool
+	}{
+		{name: "line", mode: FrameModeLine, payload: `{"code":"1001"}`, want: `{"code":"1001"}` + "\n"},
+		{name: "line with newline", mode: FrameModeLine, payload: "a\nb", wantErr: true},
+		{name: "line oversize", mode: FrameModeLine, payload: strings.Repeat("x", MaxFrameSize+1), wantErr: true},
+		{name: "length", mode: FrameModeLength, payload: "a\nb", want: lengthFrame("a\nb")},
+		{name: "length oversize", mode: FrameModeLength, payload: strings.Repeat("x", MaxFrameSize+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame, err := NewEncoder(tt.mode).Encode([]byte(tt.payload))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got frame of %d bytes", len(frame))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(frame, []byte(tt.want)) {
+				t.Fatalf("frame = %q, want %q", frame, tt.want)
+			}
+
+			// 编码结果可以被同模式的解码器还原
+			decoded, err := NewDecoder(bytes.NewReader(frame), tt.mode).ReadFrame()
+			if err != nil || string(decoded) != tt.payload {
+				t.Fatalf("round trip = %q, %v, want %q", decoded, err, tt.payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package protocol
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+const (
+	handshakePrefix    = "FRAME " // 握手行前缀，例如 "FRAME length\n"
+	maxHandshakeLength = 64       // 握手行最大长度
+)
+
+// FramedConn 带分帧编解码的连接，所有写出都经过编码器
+type FramedConn struct {
+	net.Conn
+	reader      *bufio.Reader
+	mode        FrameMode
+	decoder     *Decoder
+	encoder     *Encoder
+	writeMu     sync.Mutex
+	negotiating bool // 首次读取前是否仍可接受握手，仅由读取协程访问
+}
+
+// NewFramedConn 使用指定帧模式包装连接
+func NewFramedConn(conn net.Conn, mode FrameMode) *FramedConn {
+	return newFramedConn(conn, bufio.NewReaderSize(conn, defaultReaderSize), mode)
+}
+
+func newFramedConn(conn net.Conn, reader *bufio.Reader, mode FrameMode) *FramedConn {
+	return &FramedConn{
+		Conn:    conn,
+		reader:  reader,
+		mode:    mode,
+		decoder: NewDecoder(reader, mode),
+		encoder: NewEncoder(mode),
+	}
+}
+
+// Negotiate 包装连接并在首次读取时与客户端协商帧模式，不等待客户端，服务器可以立即发送消息
+// 客户端可在连接后首先发送一行 "FRAME line" 或 "FRAME length"，服务器回复 "FRAME OK <mode>"；
+// 握手前服务器发出的消息使用换行分隔模式，若客户端直接发送消息则一直使用换行分隔模式
+func Negotiate(conn net.Conn) *FramedConn {
+	fc := newFramedConn(conn, bufio.NewReaderSize(conn, defaultReaderSize), FrameModeLine)
+	fc.negotiating = true
+	return fc
+}
+
+// negotiate 读取客户端的第一行，若为握手行则切换帧模式并回复握手应答
+func (fc *FramedConn) negotiate() error {
+	head, err := fc.reader.Peek(1)
+	if err != nil {
+		return err
+	}
+	if head[0] != handshakePrefix[0] {
+		return nil
+	}
+
+	// 读取握手行
+	line, err := fc.reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull || len(line) > maxHandshakeLength {
+		return fmt.Errorf("handshake too long: %d bytes", len(line))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read handshake: %v", err)
+	}
+
+	handshake := strings.TrimSpace(string(line))
+	if !strings.HasPrefix(handshake, handshakePrefix) {
+		return fmt.Errorf("invalid handshake: %q", handshake)
+	}
+
+	fc.writeMu.Lock()
+	defer fc.writeMu.Unlock()
+
+	mode, err := ParseFrameMode(strings.TrimSpace(strings.TrimPrefix(handshake, handshakePrefix)))
+	if err != nil {
+		fc.Conn.Write([]byte("FRAME ERROR " + err.Error() + "\n"))
+		return err
+	}
+
+	// 握手应答始终以换行结尾，之后的消息使用协商的帧模式
+	if _, err := fc.Conn.Write([]byte("FRAME OK " + string(mode) + "\n")); err != nil {
+		return err
+	}
+	fc.mode = mode
+	fc.decoder = NewDecoder(fc.reader, mode)
+	fc.encoder = NewEncoder(mode)
+	return nil
+}
+
+// Mode 获取连接的帧模式
+func (fc *FramedConn) Mode() FrameMode {
+	fc.writeMu.Lock()
+	defer fc.writeMu.Unlock()
+
+	return fc.mode
+}
+
+// ReadFrame 读取一个完整的帧
+func (fc *FramedConn) ReadFrame() ([]byte, error) {
+	if fc.negotiating {
+		fc.negotiating = false
+		if err := fc.negotiate(); err != nil {
+			return nil, err
+		}
+	}
+	return fc.decoder.ReadFrame()
+}
+
+// WriteFrame 编码并写出一个完整的帧（并发安全）
+func (fc *FramedConn) WriteFrame(payload []byte) error {
+	fc.writeMu.Lock()
+	defer fc.writeMu.Unlock()
+
+	frame, err := fc.encoder.Encode(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = fc.Conn.Write(frame)
+	return err
+}
@@ -0,0 +1,102 @@
+package protocol
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+)
+
+// startNegotiatedServer 在管道的服务器端立即写出欢迎消息，然后读取一个帧并原样回写
+func startNegotiatedServer(t *testing.T) (net.Conn, <-chan error) {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		serverConn.Close()
+		clientConn.Close()
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		fc := Negotiate(serverConn)
+		if err := fc.WriteFrame([]byte("welcome")); err != nil {
+			done <- err
+			return
+		}
+		frame, err := fc.ReadFrame()
+		if err != nil {
+			done <- err
+			return
+		}
+		done <- fc.WriteFrame(frame)
+	}()
+
+	clientConn.SetDeadline(time.Now().Add(2 * time.Second))
+	return clientConn, done
+}
+
+func TestNegotiateLegacyClientGetsWelcomeFirst(t *testing.T) {
+	clientConn, done := startNegotiatedServer(t)
+	reader := bufio.NewReader(clientConn)
+
+	// 旧客户端先等待服务器发送欢迎消息
+	welcome, err := reader.ReadString('\n')
+	if err != nil || welcome != "welcome\n" {
+		t.Fatalf("welcome = %q, %v", welcome, err)
+	}
+
+	if _, err := clientConn.Write([]byte("{\"message\":\"Ping\"}\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	echo, err := reader.ReadString('\n')
+	if err != nil || echo != "{\"message\":\"Ping\"}\n" {
+		t.Fatalf("echo = %q, %v", echo, err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+}
+
+func TestNegotiateSwitchesToLengthMode(t *testing.T) {
+	clientConn, done := startNegotiatedServer(t)
+	reader := bufio.NewReader(clientConn)
+
+	welcome, err := reader.ReadString('\n')
+	if err != nil || welcome != "welcome\n" {
+		t.Fatalf("welcome = %q, %v", welcome, err)
+	}
+
+	// 握手和第一个长度帧一起发送
+	go clientConn.Write([]byte("FRAME length\n" + lengthFrame("a\nb")))
+
+	reply, err := reader.ReadString('\n')
+	if err != nil || reply != "FRAME OK length\n" {
+		t.Fatalf("handshake reply = %q, %v", reply, err)
+	}
+	echo, err := NewDecoder(reader, FrameModeLength).ReadFrame()
+	if err != nil || string(echo) != "a\nb" {
+		t.Fatalf("echo = %q, %v", echo, err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+}
+
+func TestNegotiateRejectsUnknownMode(t *testing.T) {
+	clientConn, done := startNegotiatedServer(t)
+	reader := bufio.NewReader(clientConn)
+
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("welcome: %v", err)
+	}
+	go clientConn.Write([]byte("FRAME binary\n"))
+
+	reply, err := reader.ReadString('\n')
+	if err != nil || reply != "FRAME ERROR unsupported frame mode: binary\n" {
+		t.Fatalf("handshake reply = %q, %v", reply, err)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("expected server to reject the handshake")
+	}
+}
@@ -0,0 +1,66 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// WSConn WebSocket 连接适配器，每条 WebSocket 消息即为一帧
+type WSConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+// NewWSConn 包装 WebSocket 连接
+func NewWSConn(conn *websocket.Conn) *WSConn {
+	conn.SetReadLimit(MaxFrameSize)
+	return &WSConn{conn: conn}
+}
+
+// ReadFrame 读取下一条文本或二进制消息，跳过空消息
+func (wc *WSConn) ReadFrame() ([]byte, error) {
+	for {
+		messageType, payload, err := wc.conn.ReadMessage()
+		if err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				return nil, ErrFrameTooLarge
+			}
+			return nil, err
+		}
+
+		if messageType != websocket.TextMessage && messageType != websocket.BinaryMessage {
+			continue
+		}
+
+		payload = bytes.TrimSpace(payload)
+		if len(payload) > 0 {
+			return payload, nil
+		}
+	}
+}
+
+// WriteFrame 以文本消息写出一帧（并发安全）
+func (wc *WSConn) WriteFrame(payload []byte) error {
+	if len(payload) > MaxFrameSize {
+		return ErrFrameTooLarge
+	}
+
+	wc.writeMu.Lock()
+	defer wc.writeMu.Unlock()
+
+	return wc.conn.WriteMessage(websocket.TextMessage, payload)
+}
+
+// RemoteAddr 获取客户端地址
+func (wc *WSConn) RemoteAddr() net.Addr {
+	return wc.conn.RemoteAddr()
+}
+
+// Close 关闭 WebSocket 连接
+func (wc *WSConn) Close() error {
+	return wc.conn.Close()
+}
@@ -1,9 +1,11 @@
 package tcpserver
 
 import (
+	messagehandle "GoServer/tcpgameserver/MessageHandle"
 	"GoServer/tcpgameserver/events"
-	messagehandle "GoServer/tcpgameserver/messageHandle"
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/setup"
+	"errors"
 	"log"
 	"net"
 )
@@ -32,8 +34,14 @@ func StartTCPServer() {
 
 // 处理客户端连接
 func handleConnection(conn net.Conn) {
+	// 首次读取时协商消息分帧模式，欢迎消息不等待握手
+	serveConnection(protocol.Negotiate(conn), "tcp")
+}
+
+// serveConnection 处理已建立的客户端连接（TCP 与 WebSocket 共用）
+func serveConnection(conn protocol.FrameReader, connectionType string) {
 	// 使用连接管理器处理新连接
-	clientID := messagehandle.HandleNewConnection(conn)
+	clientID := messagehandle.HandleNewConnection(conn, connectionType)
 	clientAddr := conn.RemoteAddr().String()
 	defer func() {
 		messagehandle.HandleConnectionClose(clientID)
@@ -41,10 +49,12 @@ func handleConnection(conn net.Conn) {
 		conn.Close()
 	}()
 
-	buf := make([]byte, 4096) // 8KB buffer to handle 6KB data with safety margin
 	for {
-		n, err := conn.Read(buf)
+		frame, err := conn.ReadFrame()
 		if err != nil {
+			if errors.Is(err, protocol.ErrFrameTooLarge) {
+				log.Printf("Client %s sent a frame larger than %d bytes, closing connection", clientAddr, protocol.MaxFrameSize)
+			}
 			// 发布连接超时或错误事件
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 				timeoutData := events.NewEventData(events.EventClientDisconnect, "tcp_server", map[string]interface{}{
@@ -60,8 +70,7 @@ func handleConnection(conn net.Conn) {
 		// 更新客户端活动时间
 		messagehandle.UpdateClientActivity(clientID)
 
-		msg := string(buf[:n])
-		messagehandle.HandleTCPMessage(msg, conn, clientID)
+		messagehandle.HandleTCPMessage(string(frame), conn, clientID)
 	}
 }
 
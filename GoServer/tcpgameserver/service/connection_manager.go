@@ -3,10 +3,10 @@ package service
 import (
 	"fmt"
 	"log"
-	"net"
 	"sync"
 	"time"
 
+	"GoServer/tcpgameserver/protocol"
 	"GoServer/tcpgameserver/types"
 )
 
@@ -51,7 +51,7 @@ func (cm *ConnectionManager) Stop() {
 }
 
 // AddConnection 添加新连接
-func (cm *ConnectionManager) AddConnection(conn net.Conn, clientID string) *types.ClientInfo {
+func (cm *ConnectionManager) AddConnection(conn protocol.Conn, clientID string) *types.ClientInfo {
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
 
@@ -285,7 +285,7 @@ func (cm *ConnectionManager) SendToUser(username string, data []byte) error {
 		return fmt.Errorf("connection is nil for user %s", username)
 	}
 
-	_, err := clientInfo.Conn.Write(data)
+	err := protocol.WriteFrame(clientInfo.Conn, data)
 	if err != nil {
 		// 连接出错，移除该连接
 		cm.RemoveConnection(clientInfo.ClientID)
@@ -308,7 +308,7 @@ func (cm *ConnectionManager) SendToClient(clientID string, data []byte) error {
 		return fmt.Errorf("connection is nil for client %s", clientID)
 	}
 
-	_, err := clientInfo.Conn.Write(data)
+	err := protocol.WriteFrame(clientInfo.Conn, data)
 	if err != nil {
 		// 连接出错，移除该连接
 		cm.RemoveConnection(clientID)
@@ -331,7 +331,7 @@ func (cm *ConnectionManager) Broadcast(data []byte) {
 
 	for _, clientInfo := range connections {
 		if clientInfo.Conn != nil {
-			_, err := clientInfo.Conn.Write(data)
+			err := protocol.WriteFrame(clientInfo.Conn, data)
 			if err != nil {
 				// 连接出错，移除该连接
 				cm.RemoveConnection(clientInfo.ClientID)
@@ -364,6 +364,10 @@ func (cm *ConnectionManager) performCleanup() {
 
 	var toRemove []string
 	for clientID, clientInfo := range cm.connections {
+		// 等待重连的连接由重连等待流程在超时后移除
+		if clientInfo.GetStatus() == types.StatusWaitingReconnect {
+			continue
+		}
 		if !clientInfo.IsActive(cm.heartbeatTimeout) {
 			toRemove = append(toRemove, clientID)
 		}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadGameData 读取卡牌、羁绊、合成配方和响应码定义并检查完整性
+// 配置了数据包文件时从文件读取，否则从MySQL读取
+func LoadGameData() (*models.DataPack, error) {
+	path := config.GetDataPackPath()
+	if path == "" {
+		pack, err := ExportGameData()
+		if err != nil {
+			return nil, err
+		}
+		if err := pack.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid game data in database: %w", err)
+		}
+		return pack, nil
+	}
+
+	pack, err := ReadDataPack(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid data pack %s: %w", path, err)
+	}
+	return pack, nil
+}
+
+// ExportGameData 将MySQL中的卡牌、羁绊、合成配方和响应码导出为数据包
+func ExportGameData() (*models.DataPack, error) {
+	cardDecks, err := GetAllCardDeck()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load card decks from database: %v", err)
+	}
+	bonds, err := GetAllBonds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bonds from database: %v", err)
+	}
+	recipes, err := GetAllComposeRecipes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load compose recipes from database: %v", err)
+	}
+	responseCodes, err := GetAllResponseInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load response codes from database: %v", err)
+	}
+
+	return &models.DataPack{
+		FormatVersion: models.DataPackFormatVersion,
+		Cards:         cardDecks,
+		Bonds:         bonds,
+		Recipes:       recipes,
+		ResponseCodes: responseCodes,
+	}, nil
+}
+
+// ReadDataPack 读取数据包文件，按扩展名解析JSON或YAML，不允许出现未知字段
+func ReadDataPack(path string) (*models.DataPack, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data pack %s: %v", path, err)
+	}
+
+	var pack models.DataPack
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&pack)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&pack)
+	default:
+		return nil, fmt.Errorf("unsupported data pack format %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse data pack %s: %v", path, err)
+	}
+
+	return &pack, nil
+}
+
+// WriteDataPack 将数据包写入文件，按扩展名保存为JSON或YAML
+func WriteDataPack(pack *models.DataPack, path string) error {
+	var content []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		content, err = json.MarshalIndent(pack, "", "  ")
+	case ".yaml", ".yml":
+		content, err = yaml.Marshal(pack)
+	default:
+		return fmt.Errorf("unsupported data pack format %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode data pack: %v", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create data pack directory: %v", err)
+		}
+	}
+	return os.WriteFile(path, content, 0644)
+}
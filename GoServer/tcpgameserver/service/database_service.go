@@ -3,10 +3,13 @@ package service
 import (
 	"GoServer/tcpgameserver/config"
 	"GoServer/tcpgameserver/models"
+	"crypto/subtle"
 	"database/sql"
 	"fmt"
+	"log"
 
 	_ "github.com/go-sql-driver/mysql" // MySQL驱动
+	"golang.org/x/crypto/bcrypt"
 )
 
 // GetDBConnection 获取数据库连接
@@ -144,20 +147,105 @@ func GetUserAccount(username string) (*models.UserAccount, error) {
 	return &user, nil
 }
 
-// ValidateUserLogin 验证用户登录
+// ValidateUserLogin 验证用户登录，保存为明文的旧密码验证通过后迁移为bcrypt哈希
 func ValidateUserLogin(username, password string) (bool, error) {
 	user, err := GetUserAccount(username)
 	if err != nil {
 		return false, err
 	}
 
-	// 这里应该使用加密密码比较，暂时使用明文比较
-	// 在生产环境中应该使用 bcrypt 等加密方式
-	if user.Password == password {
-		return true, nil
+	if isPasswordHash(user.Password) {
+		return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil, nil
 	}
 
-	return false, nil
+	// 通过Voyara创建的账户没有游戏密码
+	if user.Password == "" || subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) != 1 {
+		return false, nil
+	}
+
+	// 明文密码验证通过，迁移为bcrypt哈希，迁移失败不影响本次登录
+	if err := UpdateUserPassword(username, password); err != nil {
+		log.Printf("Failed to migrate plaintext password for user %s: %v", username, err)
+	}
+	return true, nil
+}
+
+// UpdateUserPassword 使用bcrypt哈希更新用户密码
+func UpdateUserPassword(username, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	query := "UPDATE UserAccount SET password = ? WHERE username = ?"
+	if _, err := db.Exec(query, hash, username); err != nil {
+		return fmt.Errorf("failed to update password: %v", err)
+	}
+	return nil
+}
+
+// GetUsernameByVoyaraUserID 获取关联到Voyara用户的游戏用户名
+func GetUsernameByVoyaraUserID(voyaraUserID int) (string, bool, error) {
+	db, err := GetDBConnection()
+	if err != nil {
+		return "", false, err
+	}
+	defer db.Close()
+
+	var username string
+	query := "SELECT username FROM UserAccount WHERE voyara_user_id = ?"
+	err = db.QueryRow(query, voyaraUserID).Scan(&username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get linked user account: %v", err)
+	}
+	return username, true, nil
+}
+
+// CreateVoyaraUserAccount 为Voyara用户创建关联的游戏账户，账户没有游戏密码，只能通过Voyara令牌或会话令牌登录
+func CreateVoyaraUserAccount(username string, voyaraUserID int) error {
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	query := "INSERT INTO UserAccount (username, password, voyara_user_id) VALUES (?, '', ?)"
+	if _, err := db.Exec(query, username, voyaraUserID); err != nil {
+		return fmt.Errorf("failed to create linked user account: %v", err)
+	}
+	return nil
+}
+
+// LinkVoyaraUser 将已有的游戏账户关联到Voyara用户，账户或Voyara用户已有关联、账户使用机器人保留名时失败
+func LinkVoyaraUser(username string, voyaraUserID int) error {
+	if models.IsBotUsername(username) {
+		return fmt.Errorf("user account %s uses a reserved bot username", username)
+	}
+
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	query := "UPDATE UserAccount SET voyara_user_id = ? WHERE username = ? AND voyara_user_id IS NULL"
+	result, err := db.Exec(query, voyaraUserID, username)
+	if err != nil {
+		return fmt.Errorf("failed to link user account: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("user account %s is already linked", username)
+	}
+	return nil
 }
 
 // CreateUserAccount 创建新用户账户
@@ -177,9 +265,13 @@ func CreateUserAccount(username, password string) error {
 		return fmt.Errorf("user already exists: %s", username)
 	}
 
-	// 插入新用户
+	// 插入新用户，密码保存为bcrypt哈希
+	hash, err := HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
 	query := "INSERT INTO UserAccount (username, password) VALUES (?, ?)"
-	_, err = db.Exec(query, username, password)
+	_, err = db.Exec(query, username, hash)
 	if err != nil {
 		return fmt.Errorf("failed to create user account: %v", err)
 	}
@@ -260,3 +352,58 @@ func GetAllBonds() ([]models.BondModel, error) {
 	}
 	return bonds, nil
 }
+
+// GetAllComposeRecipes 获取所有合成配方及其消耗的卡牌（按配方ID排序）
+func GetAllComposeRecipes() ([]models.ComposeRecipe, error) {
+	db, err := GetDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	recipeRows, err := db.Query(`
+		SELECT id, name, result_name, success_rate,
+		       COALESCE(bonus_name, '') as bonus_name, bonus_rate
+		FROM ComposeRecipes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ComposeRecipes: %v", err)
+	}
+	defer recipeRows.Close()
+
+	recipes := make([]models.ComposeRecipe, 0)
+	recipeIndexMap := make(map[int]int)
+	for recipeRows.Next() {
+		var recipe models.ComposeRecipe
+		err := recipeRows.Scan(&recipe.ID, &recipe.Name, &recipe.Result, &recipe.SuccessRate, &recipe.BonusResult, &recipe.BonusRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ComposeRecipes: %v", err)
+		}
+		recipe.Ingredients = make([]models.RecipeIngredient, 0)
+		recipes = append(recipes, recipe)
+		recipeIndexMap[recipe.ID] = len(recipes) - 1
+	}
+	if err = recipeRows.Err(); err != nil {
+		return nil, fmt.Errorf("error during ComposeRecipes iteration: %v", err)
+	}
+
+	ingredientRows, err := db.Query("SELECT recipe_id, card_name, count FROM ComposeRecipeIngredients ORDER BY recipe_id, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ComposeRecipeIngredients: %v", err)
+	}
+	defer ingredientRows.Close()
+
+	for ingredientRows.Next() {
+		var recipeID int
+		var ingredient models.RecipeIngredient
+		if err := ingredientRows.Scan(&recipeID, &ingredient.CardName, &ingredient.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan ComposeRecipeIngredients: %v", err)
+		}
+		if index, exists := recipeIndexMap[recipeID]; exists {
+			recipes[index].Ingredients = append(recipes[index].Ingredients, ingredient)
+		}
+	}
+	if err = ingredientRows.Err(); err != nil {
+		return nil, fmt.Errorf("error during ComposeRecipeIngredients iteration: %v", err)
+	}
+	return recipes, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"GoServer/tcpgameserver/models"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrDeckNotFound 卡组不存在
+var ErrDeckNotFound = errors.New("deck not found")
+
+// ErrDeckLimitReached 保存的卡组数量已达上限
+var ErrDeckLimitReached = errors.New("deck limit reached")
+
+// GetPlayerCollection 获取玩家拥有的卡牌数量（卡牌ID -> 张数）
+// 玩家还没有任何收藏时先发放初始收藏
+func GetPlayerCollection(username string, starter []models.DeckCard) (map[int]int, error) {
+	db, err := GetDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	owned, err := queryPlayerCollection(db, username)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned) > 0 || len(starter) == 0 {
+		return owned, nil
+	}
+
+	if err := grantCards(db, username, starter); err != nil {
+		return nil, err
+	}
+	return queryPlayerCollection(db, username)
+}
+
+// GrantCards 向玩家收藏中添加卡牌
+func GrantCards(username string, cards []models.DeckCard) error {
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return grantCards(db, username, cards)
+}
+
+// queryPlayerCollection 查询玩家拥有的卡牌数量
+func queryPlayerCollection(db *sql.DB, username string) (map[int]int, error) {
+	rows, err := db.Query("SELECT card_id, quantity FROM PlayerCollection WHERE username = ? AND quantity > 0", username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PlayerCollection: %v", err)
+	}
+	defer rows.Close()
+
+	owned := make(map[int]int)
+	for rows.Next() {
+		var cardID, quantity int
+		if err := rows.Scan(&cardID, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan PlayerCollection: %v", err)
+		}
+		owned[cardID] = quantity
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during PlayerCollection iteration: %v", err)
+	}
+	return owned, nil
+}
+
+// grantCards 累加玩家收藏中的卡牌数量
+func grantCards(db *sql.DB, username string, cards []models.DeckCard) error {
+	for _, card := range models.MergeDeckCards(cards) {
+		_, err := db.Exec(
+			`INSERT INTO PlayerCollection (username, card_id, quantity) VALUES (?, ?, ?)
+			 ON DUPLICATE KEY UPDATE quantity = quantity + VALUES(quantity)`,
+			username, card.CardID, card.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to grant card %d to %s: %v", card.CardID, username, err)
+		}
+	}
+	return nil
+}
+
+// GetPlayerDecks 获取玩家保存的所有卡组（按名称排序）
+func GetPlayerDecks(username string) ([]models.Deck, error) {
+	db, err := GetDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(
+		`SELECT d.name, d.updated_at, c.card_id, c.quantity
+		 FROM PlayerDecks d
+		 LEFT JOIN PlayerDeckCards c ON c.deck_id = d.id
+		 WHERE d.username = ?
+		 ORDER BY d.name, c.card_id`, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PlayerDecks: %v", err)
+	}
+	defer rows.Close()
+
+	decks := make([]models.Deck, 0)
+	for rows.Next() {
+		var name string
+		var deck models.Deck
+		var cardID, quantity sql.NullInt64
+		if err := rows.Scan(&name, &deck.UpdatedAt, &cardID, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan PlayerDecks: %v", err)
+		}
+		if len(decks) == 0 || decks[len(decks)-1].Name != name {
+			deck.Name = name
+			deck.Cards = make([]models.DeckCard, 0)
+			decks = append(decks, deck)
+		}
+		if cardID.Valid {
+			current := &decks[len(decks)-1]
+			current.Cards = append(current.Cards, models.DeckCard{CardID: int(cardID.Int64), Quantity: int(quantity.Int64)})
+			current.Size += int(quantity.Int64)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during PlayerDecks iteration: %v", err)
+	}
+	return decks, nil
+}
+
+// GetPlayerDeck 获取玩家指定名称的卡组，不存在时返回ErrDeckNotFound
+func GetPlayerDeck(username, name string) (*models.Deck, error) {
+	decks, err := GetPlayerDecks(username)
+	if err != nil {
+		return nil, err
+	}
+	for i := range decks {
+		if decks[i].Name == name {
+			return &decks[i], nil
+		}
+	}
+	return nil, ErrDeckNotFound
+}
+
+// SavePlayerDeck 保存玩家卡组，同名卡组整体覆盖；新建卡组时数量不能超过maxDecks
+func SavePlayerDeck(username, name string, cards []models.DeckCard, maxDecks int) error {
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var deckID int64
+	err = tx.QueryRow("SELECT id FROM PlayerDecks WHERE username = ? AND name = ? FOR UPDATE", username, name).Scan(&deckID)
+	switch {
+	case err == sql.ErrNoRows:
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM PlayerDecks WHERE username = ?", username).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count PlayerDecks: %v", err)
+		}
+		if count >= maxDecks {
+			return ErrDeckLimitReached
+		}
+		result, err := tx.Exec("INSERT INTO PlayerDecks (username, name) VALUES (?, ?)", username, name)
+		if err != nil {
+			return fmt.Errorf("failed to insert PlayerDecks: %v", err)
+		}
+		if deckID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get deck id: %v", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to query PlayerDecks: %v", err)
+	default:
+		if _, err := tx.Exec("UPDATE PlayerDecks SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", deckID); err != nil {
+			return fmt.Errorf("failed to update PlayerDecks: %v", err)
+		}
+		if _, err := tx.Exec("DELETE FROM PlayerDeckCards WHERE deck_id = ?", deckID); err != nil {
+			return fmt.Errorf("failed to clear PlayerDeckCards: %v", err)
+		}
+	}
+
+	for _, card := range models.MergeDeckCards(cards) {
+		_, err := tx.Exec("INSERT INTO PlayerDeckCards (deck_id, card_id, quantity) VALUES (?, ?, ?)",
+			deckID, card.CardID, card.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to insert PlayerDeckCards: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit deck: %v", err)
+	}
+	return nil
+}
+
+// DeletePlayerDeck 删除玩家卡组，不存在时返回ErrDeckNotFound
+func DeletePlayerDeck(username, name string) error {
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := db.Exec("DELETE FROM PlayerDecks WHERE username = ? AND name = ?", username, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete PlayerDecks: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrDeckNotFound
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"GoServer/tcpgameserver/models"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SaveMatchRecord 保存对局记录及参与者数据
+func SaveMatchRecord(record *models.MatchRecord) error {
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var winner sql.NullString
+	if record.Winner != "" {
+		winner = sql.NullString{String: record.Winner, Valid: true}
+	}
+
+	result, err := tx.Exec(
+		"INSERT INTO MatchHistory (room_id, winner, end_reason, turn_count, started_at, ended_at) VALUES (?, ?, ?, ?, ?, ?)",
+		record.RoomID, winner, record.EndReason, record.TurnCount, record.StartedAt, record.EndedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert MatchHistory: %v", err)
+	}
+
+	matchID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get match id: %v", err)
+	}
+
+	for _, participant := range record.Participants {
+		bondsJSON, err := json.Marshal(participant.BondsTriggered)
+		if err != nil {
+			return fmt.Errorf("failed to encode bonds for %s: %v", participant.Username, err)
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO MatchParticipants (match_id, username, result, final_health, damage_dealt, damage_taken, bonds_triggered)
+			 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			matchID, participant.Username, participant.Result, participant.FinalHealth,
+			participant.DamageDealt, participant.DamageTaken, string(bondsJSON))
+		if err != nil {
+			return fmt.Errorf("failed to insert MatchParticipants for %s: %v", participant.Username, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit match record: %v", err)
+	}
+
+	record.ID = matchID
+	return nil
+}
+
+// GetMatchHistory 获取玩家最近的对局记录（按结束时间倒序）
+func GetMatchHistory(username string, limit int) ([]models.MatchRecord, error) {
+	db, err := GetDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	query := `
+		SELECT m.id, m.room_id, COALESCE(m.winner, ''), m.end_reason, m.turn_count, m.started_at, m.ended_at
+		FROM MatchHistory m
+		JOIN MatchParticipants p ON p.match_id = m.id
+		WHERE p.username = ?
+		ORDER BY m.ended_at DESC, m.id DESC
+		LIMIT ?`
+	rows, err := db.Query(query, username, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query MatchHistory: %v", err)
+	}
+	defer rows.Close()
+
+	records := make([]models.MatchRecord, 0)
+	recordIndex := make(map[int64]int)
+	for rows.Next() {
+		var record models.MatchRecord
+		err := rows.Scan(&record.ID, &record.RoomID, &record.Winner, &record.EndReason,
+			&record.TurnCount, &record.StartedAt, &record.EndedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan MatchHistory: %v", err)
+		}
+		record.Participants = make([]models.MatchParticipant, 0)
+		records = append(records, record)
+		recordIndex[record.ID] = len(records) - 1
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during MatchHistory iteration: %v", err)
+	}
+
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	// 查询这些对局的全部参与者
+	placeholders := make([]string, 0, len(records))
+	args := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		placeholders = append(placeholders, "?")
+		args = append(args, record.ID)
+	}
+
+	participantQuery := fmt.Sprintf(`
+		SELECT match_id, username, result, final_health, damage_dealt, damage_taken, COALESCE(bonds_triggered, '{}')
+		FROM MatchParticipants
+		WHERE match_id IN (%s)
+		ORDER BY id`, strings.Join(placeholders, ","))
+	participantRows, err := db.Query(participantQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query MatchParticipants: %v", err)
+	}
+	defer participantRows.Close()
+
+	for participantRows.Next() {
+		var matchID int64
+		var participant models.MatchParticipant
+		var bondsJSON string
+		err := participantRows.Scan(&matchID, &participant.Username, &participant.Result, &participant.FinalHealth,
+			&participant.DamageDealt, &participant.DamageTaken, &bondsJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan MatchParticipants: %v", err)
+		}
+
+		participant.BondsTriggered = decodeBondCounts(bondsJSON)
+		if index, exists := recordIndex[matchID]; exists {
+			records[index].Participants = append(records[index].Participants, participant)
+		}
+	}
+	if err = participantRows.Err(); err != nil {
+		return nil, fmt.Errorf("error during MatchParticipants iteration: %v", err)
+	}
+
+	return records, nil
+}
+
+// GetPlayerStats 获取玩家的对局汇总统计
+func GetPlayerStats(username string) (*models.PlayerStats, error) {
+	db, err := GetDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	stats := &models.PlayerStats{Username: username}
+
+	query := `
+		SELECT COUNT(*),
+		       COALESCE(SUM(result = 'win'), 0),
+		       COALESCE(SUM(result = 'loss'), 0),
+		       COALESCE(SUM(result = 'draw'), 0),
+		       COALESCE(AVG(damage_dealt), 0),
+		       COALESCE(AVG(damage_taken), 0)
+		FROM MatchParticipants
+		WHERE username = ?`
+	err = db.QueryRow(query, username).Scan(&stats.TotalMatches, &stats.Wins, &stats.Losses, &stats.Draws,
+		&stats.AverageDamageDealt, &stats.AverageDamageTaken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player stats: %v", err)
+	}
+
+	// 汇总羁绊触发次数，取触发最多的羁绊
+	rows, err := db.Query("SELECT COALESCE(bonds_triggered, '{}') FROM MatchParticipants WHERE username = ?", username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bonds_triggered: %v", err)
+	}
+	defer rows.Close()
+
+	bondTotals := make(map[string]int)
+	for rows.Next() {
+		var bondsJSON string
+		if err := rows.Scan(&bondsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan bonds_triggered: %v", err)
+		}
+		for bondName, count := range decodeBondCounts(bondsJSON) {
+			bondTotals[bondName] += count
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during bonds_triggered iteration: %v", err)
+	}
+
+	bestCount := 0
+	for bondName, count := range bondTotals {
+		if count > bestCount || (count == bestCount && bondName < stats.FavouriteBond) {
+			stats.FavouriteBond = bondName
+			bestCount = count
+		}
+	}
+
+	return stats, nil
+}
+
+// decodeBondCounts 解析羁绊触发次数JSON，解析失败时返回空映射
+func decodeBondCounts(bondsJSON string) map[string]int {
+	bondCounts := make(map[string]int)
+	if err := json.Unmarshal([]byte(bondsJSON), &bondCounts); err != nil {
+		return make(map[string]int)
+	}
+	return bondCounts
+}
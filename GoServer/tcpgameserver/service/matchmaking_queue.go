@@ -0,0 +1,294 @@
+package service
+
+import (
+	"GoServer/tcpgameserver/models"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 匹配评分窗口配置
+const (
+	MatchmakingBaseWindow   = 100.0 // 入队时可匹配的评分差
+	MatchmakingWindowGrowth = 20.0  // 每等待一秒扩大的评分差
+	MatchmakingMaxWindow    = 800.0 // 评分差上限
+	matchmakingWaitSamples  = 20    // 估算等待时间使用的最近匹配样本数
+)
+
+// QueueEntry 匹配队列条目
+type QueueEntry struct {
+	ClientID   string    `json:"ClientID"`
+	Username   string    `json:"Username"`
+	Rating     float64   `json:"Rating"`
+	RoomSize   int       `json:"RoomSize"` // 期望的房间人数
+	EnqueuedAt time.Time `json:"EnqueuedAt"`
+}
+
+// RatingWindow 根据已等待时长计算当前可匹配的评分差
+func (e *QueueEntry) RatingWindow(now time.Time) float64 {
+	window := MatchmakingBaseWindow + now.Sub(e.EnqueuedAt).Seconds()*MatchmakingWindowGrowth
+	return math.Min(window, MatchmakingMaxWindow)
+}
+
+// compatibleWith 判断两名玩家的评分差是否在双方当前窗口内
+func (e *QueueEntry) compatibleWith(other *QueueEntry, now time.Time) bool {
+	diff := math.Abs(e.Rating - other.Rating)
+	return diff <= e.RatingWindow(now) && diff <= other.RatingWindow(now)
+}
+
+// secondsUntilWindow 计算评分窗口扩大到指定评分差还需等待的秒数
+func (e *QueueEntry) secondsUntilWindow(diff float64, now time.Time) float64 {
+	needed := (diff - MatchmakingBaseWindow) / MatchmakingWindowGrowth
+	return math.Max(needed-now.Sub(e.EnqueuedAt).Seconds(), 0)
+}
+
+// MatchmakingQueue 匹配队列，按入队时间排序
+type MatchmakingQueue struct {
+	entries     []*QueueEntry
+	recentWaits []time.Duration // 最近匹配成功玩家的等待时长
+	mutex       sync.Mutex
+}
+
+var (
+	matchmakingQueue *MatchmakingQueue
+	matchmakingOnce  sync.Once
+)
+
+// GetMatchmakingQueue 获取匹配队列单例
+func GetMatchmakingQueue() *MatchmakingQueue {
+	matchmakingOnce.Do(func() {
+		matchmakingQueue = &MatchmakingQueue{
+			entries:     make([]*QueueEntry, 0),
+			recentWaits: make([]time.Duration, 0, matchmakingWaitSamples),
+		}
+	})
+	return matchmakingQueue
+}
+
+// Enqueue 将玩家加入匹配队列
+func (q *MatchmakingQueue) Enqueue(entry *QueueEntry) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.indexOf(entry.Username) >= 0 {
+		return fmt.Errorf("player %s already in queue", entry.Username)
+	}
+
+	q.insert(entry)
+	return nil
+}
+
+// Requeue 将匹配失败的玩家放回队列，保留原入队时间
+func (q *MatchmakingQueue) Requeue(entry *QueueEntry) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.indexOf(entry.Username) < 0 {
+		q.insert(entry)
+	}
+}
+
+// Remove 将玩家移出匹配队列
+func (q *MatchmakingQueue) Remove(username string) (*QueueEntry, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := q.indexOf(username)
+	if index < 0 {
+		return nil, false
+	}
+
+	entry := q.entries[index]
+	q.entries = append(q.entries[:index], q.entries[index+1:]...)
+	return entry, true
+}
+
+// Len 获取队列人数
+func (q *MatchmakingQueue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.entries)
+}
+
+// Status 获取玩家的排队状态，队列位置和人数只统计相同房间人数的玩家
+func (q *MatchmakingQueue) Status(username string, now time.Time) (*models.QueueStatus, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := q.indexOf(username)
+	if index < 0 {
+		return nil, false
+	}
+
+	entry := q.entries[index]
+	position, queueSize := 0, 0
+	for i, other := range q.entries {
+		if other.RoomSize != entry.RoomSize {
+			continue
+		}
+		queueSize++
+		if i <= index {
+			position++
+		}
+	}
+
+	return &models.QueueStatus{
+		Username:             entry.Username,
+		Rating:               entry.Rating,
+		RoomSize:             entry.RoomSize,
+		Position:             position,
+		QueueSize:            queueSize,
+		WaitedSeconds:        now.Sub(entry.EnqueuedAt).Seconds(),
+		RatingWindow:         entry.RatingWindow(now),
+		EstimatedWaitSeconds: q.estimateWait(entry, now),
+	}, true
+}
+
+// FindMatches 按入队顺序为玩家组建房间：从相同房间人数的玩家中按评分差由近到远挑选对手，
+// 要求房间内任意两名玩家的评分差都在双方窗口内，组建成功的玩家移出队列
+func (q *MatchmakingQueue) FindMatches(now time.Time) [][]*QueueEntry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	matched := make(map[string]bool)
+	groups := make([][]*QueueEntry, 0)
+
+	for _, entry := range q.entries {
+		if matched[entry.Username] {
+			continue
+		}
+
+		// 与该玩家评分兼容的候选对手，按评分差排序
+		candidates := make([]*QueueEntry, 0)
+		for _, candidate := range q.entries {
+			if candidate == entry || matched[candidate.Username] || candidate.RoomSize != entry.RoomSize {
+				continue
+			}
+			if entry.compatibleWith(candidate, now) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		if len(candidates) < entry.RoomSize-1 {
+			continue
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return math.Abs(entry.Rating-candidates[i].Rating) < math.Abs(entry.Rating-candidates[j].Rating)
+		})
+
+		group := []*QueueEntry{entry}
+		for _, candidate := range candidates {
+			if len(group) == entry.RoomSize {
+				break
+			}
+			compatible := true
+			for _, member := range group[1:] {
+				if !member.compatibleWith(candidate, now) {
+					compatible = false
+					break
+				}
+			}
+			if compatible {
+				group = append(group, candidate)
+			}
+		}
+
+		if len(group) == entry.RoomSize {
+			for _, member := range group {
+				matched[member.Username] = true
+				q.recordWait(now.Sub(member.EnqueuedAt))
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	if len(matched) > 0 {
+		remaining := make([]*QueueEntry, 0, len(q.entries)-len(matched))
+		for _, entry := range q.entries {
+			if !matched[entry.Username] {
+				remaining = append(remaining, entry)
+			}
+		}
+		q.entries = remaining
+	}
+
+	return groups
+}
+
+// TakeWaiting 将等待时长达到指定时间的玩家按入队顺序移出队列，用于由机器人补足房间
+func (q *MatchmakingQueue) TakeWaiting(now time.Time, wait time.Duration) []*QueueEntry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	taken := make([]*QueueEntry, 0)
+	remaining := make([]*QueueEntry, 0, len(q.entries))
+	for _, entry := range q.entries {
+		if now.Sub(entry.EnqueuedAt) >= wait {
+			taken = append(taken, entry)
+			q.recordWait(now.Sub(entry.EnqueuedAt))
+		} else {
+			remaining = append(remaining, entry)
+		}
+	}
+	q.entries = remaining
+	return taken
+}
+
+// estimateWait 估算剩余等待秒数：优先根据队列中相同房间人数的潜在对手计算凑满房间所需的窗口扩大时间，
+// 否则使用最近匹配的平均等待时长
+func (q *MatchmakingQueue) estimateWait(entry *QueueEntry, now time.Time) float64 {
+	waits := make([]float64, 0)
+	for _, other := range q.entries {
+		if other == entry || other.RoomSize != entry.RoomSize {
+			continue
+		}
+		diff := math.Abs(entry.Rating - other.Rating)
+		if diff > MatchmakingMaxWindow {
+			continue
+		}
+		waits = append(waits, math.Max(entry.secondsUntilWindow(diff, now), other.secondsUntilWindow(diff, now)))
+	}
+	if opponents := entry.RoomSize - 1; opponents > 0 && len(waits) >= opponents {
+		sort.Float64s(waits)
+		return waits[opponents-1]
+	}
+
+	if len(q.recentWaits) == 0 {
+		return -1
+	}
+	var total time.Duration
+	for _, wait := range q.recentWaits {
+		total += wait
+	}
+	average := total.Seconds() / float64(len(q.recentWaits))
+	return math.Max(average-now.Sub(entry.EnqueuedAt).Seconds(), 0)
+}
+
+// recordWait 记录匹配成功玩家的等待时长（调用方需持有锁）
+func (q *MatchmakingQueue) recordWait(wait time.Duration) {
+	if len(q.recentWaits) >= matchmakingWaitSamples {
+		q.recentWaits = q.recentWaits[1:]
+	}
+	q.recentWaits = append(q.recentWaits, wait)
+}
+
+// insert 按入队时间插入条目（调用方需持有锁）
+func (q *MatchmakingQueue) insert(entry *QueueEntry) {
+	index := sort.Search(len(q.entries), func(i int) bool {
+		return q.entries[i].EnqueuedAt.After(entry.EnqueuedAt)
+	})
+	q.entries = append(q.entries, nil)
+	copy(q.entries[index+1:], q.entries[index:])
+	q.entries[index] = entry
+}
+
+// indexOf 查找玩家在队列中的位置（调用方需持有锁）
+func (q *MatchmakingQueue) indexOf(username string) int {
+	for i, entry := range q.entries {
+		if entry.Username == username {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"GoServer/tcpgameserver/models"
+	"fmt"
+	"strings"
+)
+
+// GetPlayerRating 获取玩家评分，尚无评分记录时返回初始评分
+func GetPlayerRating(username string) (*models.PlayerRating, error) {
+	ratings, err := GetPlayerRatings([]string{username})
+	if err != nil {
+		return nil, err
+	}
+
+	rating := ratings[username]
+	return &rating, nil
+}
+
+// GetPlayerRatings 批量获取玩家评分，尚无评分记录的玩家使用初始评分
+func GetPlayerRatings(usernames []string) (map[string]models.PlayerRating, error) {
+	ratings := make(map[string]models.PlayerRating, len(usernames))
+	for _, username := range usernames {
+		ratings[username] = models.PlayerRating{
+			Username: username,
+			Rating:   models.DefaultPlayerRating,
+		}
+	}
+
+	if len(usernames) == 0 {
+		return ratings, nil
+	}
+
+	db, err := GetDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	placeholders := make([]string, 0, len(usernames))
+	args := make([]interface{}, 0, len(usernames))
+	for _, username := range usernames {
+		placeholders = append(placeholders, "?")
+		args = append(args, username)
+	}
+
+	query := fmt.Sprintf("SELECT username, rating, games_played FROM PlayerRatings WHERE username IN (%s)",
+		strings.Join(placeholders, ","))
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PlayerRatings: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rating models.PlayerRating
+		if err := rows.Scan(&rating.Username, &rating.Rating, &rating.GamesPlayed); err != nil {
+			return nil, fmt.Errorf("failed to scan PlayerRatings: %v", err)
+		}
+		ratings[rating.Username] = rating
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during PlayerRatings iteration: %v", err)
+	}
+
+	return ratings, nil
+}
+
+// SavePlayerRatings 保存玩家评分（不存在则插入）
+func SavePlayerRatings(ratings []models.PlayerRating) error {
+	db, err := GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO PlayerRatings (username, rating, games_played) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE rating = VALUES(rating), games_played = VALUES(games_played)`
+	for _, rating := range ratings {
+		if _, err := tx.Exec(query, rating.Username, rating.Rating, rating.GamesPlayed); err != nil {
+			return fmt.Errorf("failed to save rating for %s: %v", rating.Username, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ratings: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"GoServer/tcpgameserver/config"
+	"GoServer/tcpgameserver/models"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// roomIDPattern 合法的房间ID，防止通过房间ID访问日志目录以外的文件
+var roomIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ReplayLogger 回放日志记录器，每个房间对应一个只追加写入的JSON Lines文件
+type ReplayLogger struct {
+	dir   string
+	seqs  map[string]int // 房间ID -> 最后写入的序号
+	mutex sync.Mutex
+}
+
+var (
+	replayLogger *ReplayLogger
+	replayOnce   sync.Once
+)
+
+// GetReplayLogger 获取回放日志记录器单例
+func GetReplayLogger() *ReplayLogger {
+	replayOnce.Do(func() {
+		replayLogger = &ReplayLogger{
+			dir:  config.GetReplayDir(),
+			seqs: make(map[string]int),
+		}
+	})
+	return replayLogger
+}
+
+// Append 追加一条回放日志，自动填充序号和时间戳
+func (rl *ReplayLogger) Append(roomID string, entry *models.ReplayEntry) error {
+	path, err := rl.replayPath(roomID)
+	if err != nil {
+		return err
+	}
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	if err := os.MkdirAll(rl.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create replay directory: %v", err)
+	}
+
+	entry.Seq = rl.seqs[roomID] + 1
+	entry.RoomID = roomID
+	entry.Timestamp = time.Now().UnixMilli()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode replay entry: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open replay log: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write replay entry: %v", err)
+	}
+
+	rl.seqs[roomID] = entry.Seq
+	return nil
+}
+
+// Finish 房间结束后释放序号计数
+func (rl *ReplayLogger) Finish(roomID string) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	delete(rl.seqs, roomID)
+}
+
+// ReadReplay 读取房间的全部回放日志
+func (rl *ReplayLogger) ReadReplay(roomID string) ([]models.ReplayEntry, error) {
+	path, err := rl.replayPath(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay log for room %s: %v", roomID, err)
+	}
+	defer file.Close()
+
+	entries := make([]models.ReplayEntry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry models.ReplayEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode replay entry %d: %v", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read replay log for room %s: %v", roomID, err)
+	}
+
+	return entries, nil
+}
+
+// replayPath 获取房间回放日志文件路径
+func (rl *ReplayLogger) replayPath(roomID string) (string, error) {
+	if !roomIDPattern.MatchString(roomID) {
+		return "", fmt.Errorf("invalid room id: %q", roomID)
+	}
+	return filepath.Join(rl.dir, roomID+".jsonl"), nil
+}
@@ -3,15 +3,24 @@ package service
 import (
 	"GoServer/tcpgameserver/models"
 	"GoServer/tcpgameserver/types"
+	"crypto/rand"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
 
+// 邀请码配置
+const (
+	inviteCodeLength   = 6
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // 去除易混淆的 0/O、1/I
+)
+
 // RoomManager 房间管理器
 type RoomManager struct {
-	rooms map[string]*types.RoomInfo // 房间列表，key为房间ID
-	mutex sync.RWMutex               // 读写锁
+	rooms       map[string]*types.RoomInfo // 房间列表，key为房间ID
+	inviteCodes map[string]string          // 私人房间邀请码，key为邀请码，value为房间ID
+	mutex       sync.RWMutex               // 读写锁
 }
 
 var (
@@ -23,7 +32,8 @@ var (
 func GetRoomManager() *RoomManager {
 	roomOnce.Do(func() {
 		roomManager = &RoomManager{
-			rooms: make(map[string]*types.RoomInfo),
+			rooms:       make(map[string]*types.RoomInfo),
+			inviteCodes: make(map[string]string),
 		}
 	})
 	return roomManager
@@ -45,6 +55,92 @@ func (rm *RoomManager) CreateRoom(roomName string, maxPlayers int) (*types.RoomI
 	return room, nil
 }
 
+// CreatePrivateRoom 创建带邀请码的私人房间，房主自动加入房间
+func (rm *RoomManager) CreatePrivateRoom(roomName, host string, settings models.RoomSettings) (*types.RoomInfo, error) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	roomID := fmt.Sprintf("room_%d", time.Now().UnixNano())
+	room := types.NewRoomInfo(roomID, roomName, settings.MaxPlayers)
+	if err := room.ApplySettings(settings); err != nil {
+		return nil, err
+	}
+
+	inviteCode, err := rm.generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	room.InviteCode = inviteCode
+	room.Host = host
+
+	if err := room.AddPlayer(host); err != nil {
+		return nil, err
+	}
+
+	rm.rooms[roomID] = room
+	rm.inviteCodes[inviteCode] = roomID
+	return room, nil
+}
+
+// GetRoomByInviteCode 根据邀请码获取私人房间（不区分大小写）
+func (rm *RoomManager) GetRoomByInviteCode(inviteCode string) (*types.RoomInfo, error) {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	roomID, exists := rm.inviteCodes[strings.ToUpper(strings.TrimSpace(inviteCode))]
+	if !exists {
+		return nil, fmt.Errorf("invite code %s not found", inviteCode)
+	}
+
+	room, exists := rm.rooms[roomID]
+	if !exists {
+		return nil, fmt.Errorf("room %s not found", roomID)
+	}
+	return room, nil
+}
+
+// LeavePrivateRoom 玩家离开私人房间：房主离开时由座位顺序中的下一名玩家成为房主，房间为空时删除房间
+// 返回值：(room, removed, error) - removed表示房间已被删除
+func (rm *RoomManager) LeavePrivateRoom(roomID, username string) (*types.RoomInfo, bool, error) {
+	room, err := rm.GetRoom(roomID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := room.RemovePlayer(username); err != nil {
+		return nil, false, err
+	}
+
+	remaining := room.GetTurnOrder()
+	if len(remaining) == 0 {
+		return room, true, rm.RemoveRoom(roomID)
+	}
+
+	if room.GetHost() == username {
+		room.SetHost(remaining[0])
+	}
+	return room, false, nil
+}
+
+// generateInviteCode 生成未被占用的邀请码（调用方需持有写锁）
+func (rm *RoomManager) generateInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeLength)
+	for attempt := 0; attempt < 10; attempt++ {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate invite code: %v", err)
+		}
+
+		code := make([]byte, inviteCodeLength)
+		for i, b := range buf {
+			code[i] = inviteCodeAlphabet[int(b)%len(inviteCodeAlphabet)]
+		}
+		if _, exists := rm.inviteCodes[string(code)]; !exists {
+			return string(code), nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate a unique invite code")
+}
+
 // InitCardPool 初始化房间的卡牌池
 func (rm *RoomManager) InitCardPool(roomID string, level1Cards, level2Cards, level3Cards []models.Card) error {
 	room, err := rm.GetRoom(roomID)
@@ -56,14 +152,14 @@ func (rm *RoomManager) InitCardPool(roomID string, level1Cards, level2Cards, lev
 
 }
 
-// InitCardPool 初始化玩家手牌
-func (rm *RoomManager) InitPlayerHandCard(roomID string, count int) ([]models.Card, error) {
+// InitCardPool 初始化玩家手牌（从玩家的抽牌堆或共享1级卡牌池抽取）
+func (rm *RoomManager) InitPlayerHandCard(roomID, username string, count int) ([]models.Card, error) {
 	room, err := rm.GetRoom(roomID)
 	if err != nil {
 		return nil, err
 	}
 
-	return room.DrawRandomCardsFromLevel1Pool(count)
+	return room.DrawCardsForPlayer(username, count)
 
 }
 
@@ -129,10 +225,14 @@ func (rm *RoomManager) RemoveRoom(roomID string) error {
 	rm.mutex.Lock()
 	defer rm.mutex.Unlock()
 
-	if _, exists := rm.rooms[roomID]; !exists {
+	room, exists := rm.rooms[roomID]
+	if !exists {
 		return fmt.Errorf("room %s not found", roomID)
 	}
 
+	if room.InviteCode != "" {
+		delete(rm.inviteCodes, room.InviteCode)
+	}
 	delete(rm.rooms, roomID)
 	return nil
 }
@@ -234,16 +334,13 @@ func (rm *RoomManager) GetPlayerGameInfo(roomID string, username string) (*model
 		return nil, err
 	}
 
-	// 检查房间是否在游戏状态
-	if room.Status != "playing" {
+	// 检查房间是否在游戏状态（刚结束的房间用于发送游戏结束信息）
+	if room.Status != "playing" && room.Status != "finished" {
 		return nil, nil // 不在游戏中
 	}
 
-	// 获取房间内所有玩家
-	allPlayers := make([]string, 0, len(room.Players))
-	for username := range room.Players {
-		allPlayers = append(allPlayers, username)
-	}
+	// 按座位顺序获取房间内所有玩家
+	allPlayers := room.GetTurnOrder()
 
 	// 创建游戏信息
 	return rm.createPlayerGameInfo(room, username, allPlayers), nil
@@ -258,10 +355,11 @@ func (rm *RoomManager) createPlayerGameInfo(room *types.RoomInfo, username strin
 		if playerName != username {
 			if otherPlayer, exists := room.Players[playerName]; exists {
 				OtherPlayerGameInfo := models.OtherPlayerGameInfo{
-					Username:   otherPlayer.Username,
-					Round:      otherPlayer.Round,
-					Health:     otherPlayer.CurrentHealth,
-					CardsCount: len(otherPlayer.HandCards),
+					Username:      otherPlayer.Username,
+					Round:         otherPlayer.Round,
+					Health:        otherPlayer.CurrentHealth,
+					CardsCount:    len(otherPlayer.HandCards),
+					StatusEffects: room.GetStatusEffects(otherPlayer.Username),
 				}
 				OtherPlayers = append(OtherPlayers, OtherPlayerGameInfo)
 			}
@@ -269,16 +367,60 @@ func (rm *RoomManager) createPlayerGameInfo(room *types.RoomInfo, username strin
 	}
 
 	return &models.PlayerGameInfo{
-		RoomId:       room.RoomID,
-		Username:     username,
-		Round:        roomPlayer.Round,
-		Health:       roomPlayer.CurrentHealth,
-		SelfCards:    roomPlayer.HandCards,
-		OtherPlayers: OtherPlayers,
-		DamageInfo:   roomPlayer.DamageInfo,
+		RoomId:         room.RoomID,
+		Username:       username,
+		Round:          roomPlayer.Round,
+		Health:         roomPlayer.CurrentHealth,
+		SelfCards:      roomPlayer.HandCards,
+		OtherPlayers:   OtherPlayers,
+		DamageInfo:     roomPlayer.DamageInfo,
+		StatusEffects:  room.GetStatusEffects(username),
+		CardSetVersion: room.GetCardSetVersion(),
 	}
 }
 
+// GetSpectatorGameInfo 获取观战者看到的游戏信息（玩家血量、回合、手牌数量和本回合伤害信息）
+func (rm *RoomManager) GetSpectatorGameInfo(roomID string) (*models.SpectatorGameInfo, error) {
+	room, err := rm.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &models.SpectatorGameInfo{
+		RoomId:     room.RoomID,
+		TurnCount:  room.TurnCount,
+		Players:    make([]models.OtherPlayerGameInfo, 0, len(room.Players)),
+		DamageInfo: make([]models.DamageInfo, 0),
+		Spectators: len(room.GetSpectators()),
+	}
+
+	// 每名玩家都会收到同一次伤害的信息，按来源、目标、类型和数值去重
+	seen := make(map[string]bool)
+	for _, username := range room.GetTurnOrder() {
+		player, exists := room.Players[username]
+		if !exists {
+			continue
+		}
+		info.Players = append(info.Players, models.OtherPlayerGameInfo{
+			Username:      player.Username,
+			Round:         player.Round,
+			Health:        player.CurrentHealth,
+			CardsCount:    len(player.HandCards),
+			StatusEffects: room.GetStatusEffects(player.Username),
+		})
+
+		for _, damage := range player.DamageInfo {
+			key := fmt.Sprintf("%s|%s|%s|%v", damage.DamageSource, damage.DamageTarget, damage.DamageType, damage.DamageValue)
+			if !seen[key] {
+				seen[key] = true
+				info.DamageInfo = append(info.DamageInfo, damage)
+			}
+		}
+	}
+
+	return info, nil
+}
+
 // DrawCardFromLevel1Pool 从指定房间的一级卡牌池中抽取一张卡牌
 func (rm *RoomManager) DrawCardFromLevel1Pool(roomID string) (*models.Card, error) {
 	room, err := rm.GetRoom(roomID)
@@ -299,31 +441,31 @@ func (rm *RoomManager) DrawCardsFromLevel1Pool(roomID string, count int) ([]mode
 	return room.DrawRandomCardsFromLevel1Pool(count)
 }
 
-// DrawCardForPlayer 为指定玩家从一级卡牌池抽取一张卡牌并添加到手牌
+// DrawCardForPlayer 为指定玩家从其抽牌堆（未使用卡组时为一级卡牌池）抽取一张卡牌并添加到手牌
 func (rm *RoomManager) DrawCardForPlayer(roomID, username string) error {
 	room, err := rm.GetRoom(roomID)
 	if err != nil {
 		return err
 	}
 
-	// 从一级卡牌池抽取卡牌
-	card, err := room.DrawRandomCardFromLevel1Pool()
+	// 从玩家的抽牌堆或一级卡牌池抽取卡牌
+	cards, err := room.DrawCardsForPlayer(username, 1)
 	if err != nil {
-		return fmt.Errorf("failed to draw card from level 1 pool: %v", err)
+		return fmt.Errorf("failed to draw card for player %s: %v", username, err)
 	}
 
 	// 将卡牌添加到玩家手牌
-	err = room.AddCardToPlayer(username, *card)
+	err = room.AddCardToPlayer(username, cards[0])
 	if err != nil {
 		// 如果添加失败，需要将卡牌放回卡牌池
-		room.Level1CardPool = append(room.Level1CardPool, *card)
+		room.ReturnCardsToPlayerPile(username, cards)
 		return fmt.Errorf("failed to add card to player %s: %v", username, err)
 	}
 
 	return nil
 }
 
-// DrawCardsForPlayer 为指定玩家从一级卡牌池抽取多张卡牌并添加到手牌
+// DrawCardsForPlayer 为指定玩家从其抽牌堆（未使用卡组时为一级卡牌池）抽取多张卡牌并添加到手牌
 func (rm *RoomManager) DrawCardsForPlayer(roomID, username string, count int) error {
 	room, err := rm.GetRoom(roomID)
 	if err != nil {
@@ -342,10 +484,10 @@ func (rm *RoomManager) DrawCardsForPlayer(roomID, username string, count int) er
 			username, count, len(playerInfo.HandCards), room.MaxHandCards)
 	}
 
-	// 从一级卡牌池抽取卡牌
-	cards, err := room.DrawRandomCardsFromLevel1Pool(count)
+	// 从玩家的抽牌堆或一级卡牌池抽取卡牌
+	cards, err := room.DrawCardsForPlayer(username, count)
 	if err != nil {
-		return fmt.Errorf("failed to draw %d cards from level 1 pool: %v", count, err)
+		return fmt.Errorf("failed to draw %d cards for player %s: %v", count, username, err)
 	}
 
 	// 将卡牌添加到玩家手牌
@@ -354,9 +496,7 @@ func (rm *RoomManager) DrawCardsForPlayer(roomID, username string, count int) er
 		err = room.AddCardToPlayer(username, card)
 		if err != nil {
 			// 如果添加失败，将已抽取但未添加的卡牌放回卡牌池
-			for i := successCount; i < len(cards); i++ {
-				room.Level1CardPool = append(room.Level1CardPool, cards[i])
-			}
+			room.ReturnCardsToPlayerPile(username, cards[successCount:])
 			return fmt.Errorf("failed to add card %s to player %s after %d successful additions: %v",
 				card.Name, username, successCount, err)
 		}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"GoServer/tcpgameserver/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// sessionTokenIssuer 会话令牌的签发方
+const sessionTokenIssuer = "tcpgameserver"
+
+// 会话令牌错误
+var (
+	ErrInvalidSessionToken = errors.New("invalid session token")
+	ErrSessionExpired      = errors.New("session token expired")
+	ErrSessionRevoked      = errors.New("session token revoked by a newer login")
+)
+
+// Session 玩家登录会话，断线后凭会话令牌登录或恢复对局，无需重新发送密码
+type Session struct {
+	Token     string    // 签名的会话令牌
+	ID        string    // 会话ID
+	Username  string    // 用户名
+	ExpiresAt time.Time // 过期时间
+}
+
+// sessionClaims 会话令牌内容
+type sessionClaims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+// SessionManager 会话管理器，签发和校验HS256签名的会话令牌
+// 每名玩家只保留最近一次登录的会话，较早签发的令牌失效；服务重启后令牌在有效期内仍可使用
+type SessionManager struct {
+	secret []byte
+	ttl    time.Duration
+	byUser map[string]string // 玩家当前的会话ID，key为用户名
+	mutex  sync.RWMutex
+}
+
+var (
+	sessionManager *SessionManager
+	sessionOnce    sync.Once
+)
+
+// GetSessionManager 获取会话管理器单例
+func GetSessionManager() *SessionManager {
+	sessionOnce.Do(func() {
+		sessionManager = &SessionManager{
+			secret: loadSessionSecret(),
+			ttl:    config.GetSessionTokenTTL(),
+			byUser: make(map[string]string),
+		}
+	})
+	return sessionManager
+}
+
+// loadSessionSecret 读取签名密钥，未配置或过短时生成随机密钥（服务重启后已签发的令牌失效）
+func loadSessionSecret() []byte {
+	secret := config.GetSessionSecret()
+	if len(secret) >= config.MinSessionSecretLength {
+		return []byte(secret)
+	}
+
+	if secret != "" {
+		log.Printf("GAME_SESSION_SECRET must be at least %d characters, using a random secret", config.MinSessionSecretLength)
+	} else {
+		log.Printf("GAME_SESSION_SECRET is not set, using a random secret")
+	}
+	random, err := randomHex(32)
+	if err != nil {
+		log.Fatalf("Failed to generate session secret: %v", err)
+	}
+	return []byte(random)
+}
+
+// randomHex 生成指定字节数的随机十六进制字符串
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// CreateSession 为玩家签发新的会话令牌，玩家之前的会话令牌失效
+func (sm *SessionManager) CreateSession(username string) (*Session, error) {
+	sessionID, err := randomHex(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session id: %v", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(sm.ttl)
+	claims := sessionClaims{
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
+			Subject:   username,
+			Issuer:    sessionTokenIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(sm.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign session token: %v", err)
+	}
+
+	sm.mutex.Lock()
+	sm.byUser[username] = sessionID
+	sm.mutex.Unlock()
+
+	return &Session{
+		Token:     token,
+		ID:        sessionID,
+		Username:  username,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ValidateSession 校验会话令牌的签名、有效期以及是否已被新的登录取代
+func (sm *SessionManager) ValidateSession(token string) (*Session, error) {
+	claims := &sessionClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return sm.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(sessionTokenIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrSessionExpired
+		}
+		return nil, ErrInvalidSessionToken
+	}
+	if claims.Username == "" || claims.ID == "" {
+		return nil, ErrInvalidSessionToken
+	}
+
+	sm.mutex.RLock()
+	currentID, exists := sm.byUser[claims.Username]
+	sm.mutex.RUnlock()
+	if exists && currentID != claims.ID {
+		return nil, ErrSessionRevoked
+	}
+
+	return &Session{
+		Token:     token,
+		ID:        claims.ID,
+		Username:  claims.Username,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	voyaraService "GoServer/Voyara/core/service"
+	"GoServer/tcpgameserver/models"
+)
+
+// voyaraAccessTokenIssuer Voyara访问令牌的签发方，刷新令牌使用不同的签发方
+const voyaraAccessTokenIssuer = "voyara"
+
+// maxUsernameLength 游戏用户名最大长度
+const maxUsernameLength = 50
+
+// ErrInvalidVoyaraToken Voyara访问令牌无效或已过期
+var ErrInvalidVoyaraToken = errors.New("invalid voyara access token")
+
+// ParseVoyaraToken 校验Voyara访问令牌（由 MakeAccessToken 签发），返回Voyara用户ID
+func ParseVoyaraToken(token string) (int, error) {
+	claims, err := voyaraService.ParseAccessToken(token)
+	if err != nil || claims.Issuer != voyaraAccessTokenIssuer || claims.UserID <= 0 {
+		return 0, ErrInvalidVoyaraToken
+	}
+
+	// 确认Voyara用户仍然存在
+	if _, err := voyaraService.GetUserEmailByID(claims.UserID); err != nil {
+		return 0, ErrInvalidVoyaraToken
+	}
+	return claims.UserID, nil
+}
+
+// LoginWithVoyaraToken 使用Voyara访问令牌登录，返回关联的游戏用户名
+// Voyara用户首次登录时自动创建关联的游戏账户，用户名优先使用 desiredUsername，已被占用或为机器人保留名时使用 voyara_<用户ID>
+func LoginWithVoyaraToken(token, desiredUsername string) (string, error) {
+	voyaraUserID, err := ParseVoyaraToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	username, linked, err := GetUsernameByVoyaraUserID(voyaraUserID)
+	if err != nil {
+		return "", err
+	}
+	if linked {
+		return username, nil
+	}
+
+	username = fmt.Sprintf("voyara_%d", voyaraUserID)
+	desiredUsername = strings.TrimSpace(desiredUsername)
+	if desiredUsername != "" && len(desiredUsername) <= maxUsernameLength && !models.IsBotUsername(desiredUsername) {
+		exists, err := CheckUserAccountExists(desiredUsername)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			username = desiredUsername
+		}
+	}
+
+	if err := CreateVoyaraUserAccount(username, voyaraUserID); err != nil {
+		return "", err
+	}
+	return username, nil
+}
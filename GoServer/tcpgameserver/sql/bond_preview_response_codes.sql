@@ -0,0 +1,6 @@
+-- 羁绊预览响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1701, '1701', 'PreviewPlaySuccess', '出牌预览成功'),
+(1702, '1702', 'BondHintsSuccess', '获取羁绊提示成功'),
+(1703, '1703', 'BondQueryNotInGame', '玩家不在游戏中'),
+(1704, '1704', 'BondQueryInvalidCards', '预览的卡牌无效或不在手牌中');
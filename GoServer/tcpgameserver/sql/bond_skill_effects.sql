@@ -0,0 +1,15 @@
+-- 羁绊技能效果
+-- skill 字段由一个或多个效果组成，以分号或逗号分隔，格式为 效果名[:数值[:回合数]]，未指定数值时为1
+--   heal:数值         治疗出牌方
+--   shield:数值[:回合数] 为出牌方增加护盾，受到伤害时优先扣除护盾，默认持续2回合
+--   draw:数量         出牌方额外抽牌
+--   discard:数量      出牌目标随机弃牌
+--   dot:伤害[:回合数] 出牌目标中毒，在每个回合开始时受到伤害，默认持续2回合（poison 同义）
+--   stun[:1[:回合数]] 出牌目标眩晕，跳过回合，默认持续1回合
+--   extra_turn        出牌方获得额外回合
+-- 将示例羁绊的技能描述更新为可执行的效果
+UPDATE Bonds SET skill = 'dot:3:2' WHERE name = '火焰之力';
+UPDATE Bonds SET skill = 'shield:8' WHERE name = '冰霜守护';
+UPDATE Bonds SET skill = 'discard:1' WHERE name = '雷电风暴';
+UPDATE Bonds SET skill = 'heal:10;draw:1' WHERE name = '自然调和';
+UPDATE Bonds SET skill = 'dot:5:3;extra_turn' WHERE name = '暗影之握';
@@ -4,7 +4,7 @@ CREATE TABLE IF NOT EXISTS bonds (
     name VARCHAR(50) NOT NULL COMMENT '羁绊名称',
     level INT NOT NULL COMMENT '羁绊等级',
     damage DECIMAL(5,1) NOT NULL COMMENT '羁绊伤害',
-    skill VARCHAR(100) DEFAULT '' COMMENT '羁绊技能',
+    skill VARCHAR(100) DEFAULT '' COMMENT '羁绊技能效果，如 heal:5;dot:3:2',
     description VARCHAR(200) NOT NULL COMMENT '羁绊描述',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间'
@@ -24,11 +24,11 @@ CREATE TABLE IF NOT EXISTS bond_relations (
 
 -- 插入示例羁绊数据
 INSERT INTO bonds (name, level, damage, skill, description) VALUES
-('火焰之力', 1, 15.0, '燃烧', '火属性卡牌组合，提供额外火焰伤害'),
-('冰霜守护', 1, 12.0, '冰冻', '冰属性卡牌组合，具有冰冻效果'),
-('雷电风暴', 2, 25.0, '麻痹', '雷属性高级组合，造成大量伤害并麻痹敌人'),
-('自然调和', 2, 18.0, '治愈', '自然属性组合，具有治愈和增益效果'),
-('暗影之握', 3, 35.0, '诅咒', '暗属性终极组合，具有强大的诅咒效果');
+('火焰之力', 1, 15.0, 'dot:3:2', '火属性卡牌组合，提供额外火焰伤害'),
+('冰霜守护', 1, 12.0, 'shield:8', '冰属性卡牌组合，具有冰冻效果'),
+('雷电风暴', 2, 25.0, 'discard:1', '雷属性高级组合，造成大量伤害并麻痹敌人'),
+('自然调和', 2, 18.0, 'heal:10;draw:1', '自然属性组合，具有治愈和增益效果'),
+('暗影之握', 3, 35.0, 'dot:5:3;extra_turn', '暗属性终极组合，具有强大的诅咒效果');
 
 -- 插入示例羁绊关联数据（假设CardDeck表中已有这些卡牌）
 INSERT INTO bond_relations (bond_id, card_name) VALUES
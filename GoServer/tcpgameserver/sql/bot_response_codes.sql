@@ -0,0 +1,6 @@
+-- 机器人玩家与人机练习
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(2401, '2401', 'PracticeStarted', '人机练习已开始'),
+(2402, '2402', 'InvalidPracticeRequest', '无效的人机练习请求'),
+(2403, '2403', 'PracticeStartFailed', '人机练习开始失败'),
+(2404, '2404', 'UsernameReserved', '该用户名为保留用户名');
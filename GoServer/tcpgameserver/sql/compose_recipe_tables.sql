@@ -0,0 +1,25 @@
+-- 合成配方表，未配置配方的卡牌按合成目标使用默认配方（3张相同卡牌合成目标卡牌）
+CREATE TABLE IF NOT EXISTS ComposeRecipes (
+    id INT AUTO_INCREMENT PRIMARY KEY COMMENT '配方ID，数值小的配方优先匹配',
+    name VARCHAR(50) NOT NULL COMMENT '配方名称',
+    result_name VARCHAR(50) NOT NULL COMMENT '产出的卡牌名称',
+    success_rate DECIMAL(4,3) NOT NULL DEFAULT 1.000 COMMENT '成功概率(0,1]，失败时消耗卡牌但不产出',
+    bonus_name VARCHAR(50) DEFAULT NULL COMMENT '奖励卡牌名称，为空时奖励一张结果卡牌',
+    bonus_rate DECIMAL(4,3) NOT NULL DEFAULT 0.000 COMMENT '成功后额外产出奖励卡牌的概率',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='合成配方表';
+
+-- 合成配方消耗卡牌表
+CREATE TABLE IF NOT EXISTS ComposeRecipeIngredients (
+    id INT AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    recipe_id INT NOT NULL COMMENT '配方ID',
+    card_name VARCHAR(50) NOT NULL COMMENT '消耗的卡牌名称',
+    count INT NOT NULL COMMENT '消耗数量',
+    FOREIGN KEY (recipe_id) REFERENCES ComposeRecipes(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_recipe_card (recipe_id, card_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='合成配方消耗卡牌表';
+
+-- 合成响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(2301, '2301', 'ComposeSuccess', '合成完成'),
+(2302, '2302', 'ComposeFailed', '没有可以合成的配方');
@@ -0,0 +1,43 @@
+-- 玩家卡牌收藏表
+CREATE TABLE IF NOT EXISTS PlayerCollection (
+    username VARCHAR(50) NOT NULL COMMENT '玩家用户名',
+    card_id INT NOT NULL COMMENT '卡牌ID（CardDeck.id）',
+    quantity INT NOT NULL DEFAULT 0 COMMENT '拥有的张数',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (username, card_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='玩家卡牌收藏表';
+
+-- 玩家卡组表
+CREATE TABLE IF NOT EXISTS PlayerDecks (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY COMMENT '卡组ID',
+    username VARCHAR(50) NOT NULL COMMENT '玩家用户名',
+    name VARCHAR(50) NOT NULL COMMENT '卡组名称',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_username_name (username, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='玩家卡组表';
+
+-- 卡组卡牌表
+CREATE TABLE IF NOT EXISTS PlayerDeckCards (
+    deck_id BIGINT NOT NULL COMMENT '卡组ID',
+    card_id INT NOT NULL COMMENT '卡牌ID（CardDeck.id）',
+    quantity INT NOT NULL COMMENT '张数',
+    PRIMARY KEY (deck_id, card_id),
+    FOREIGN KEY (deck_id) REFERENCES PlayerDecks(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='卡组卡牌表';
+
+-- 组卡响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(2101, '2101', 'CollectionSuccess', '获取卡牌收藏成功'),
+(2102, '2102', 'DeckListSuccess', '获取卡组列表成功'),
+(2103, '2103', 'DeckSaved', '卡组已保存'),
+(2104, '2104', 'DeckDeleted', '卡组已删除'),
+(2105, '2105', 'DeckSelected', '已选择对局使用的卡组'),
+(2106, '2106', 'DeckNotLoggedIn', '用户未登录'),
+(2107, '2107', 'DeckInvalidData', '请求数据格式错误'),
+(2108, '2108', 'DeckNotFound', '卡组不存在'),
+(2109, '2109', 'DeckSizeInvalid', '卡组卡牌数量不符合规则'),
+(2110, '2110', 'DeckCopyLimitExceeded', '同名卡牌数量超过上限'),
+(2111, '2111', 'DeckCardNotOwned', '卡组中包含未拥有或数量不足的卡牌'),
+(2112, '2112', 'DeckCardInvalid', '卡组中包含不存在或不能放入卡组的卡牌'),
+(2113, '2113', 'DeckLimitReached', '保存的卡组数量已达上限'),
+(2114, '2114', 'DeckOperationFailed', '卡组操作失败，请稍后重试');
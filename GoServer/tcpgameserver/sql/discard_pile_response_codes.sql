@@ -0,0 +1,4 @@
+-- 弃牌堆与洗牌
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(2201, '2201', 'DeckEmpty', '卡牌池已抽空'),
+(2202, '2202', 'DiscardPileShuffled', '弃牌堆已洗回卡牌池');
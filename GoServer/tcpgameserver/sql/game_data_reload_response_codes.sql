@@ -0,0 +1,5 @@
+-- 卡牌、羁绊和响应码热加载
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1901, '1901', 'GameDataReloaded', '游戏数据已重新加载，之后开局的对局使用新的卡牌集'),
+(1902, '1902', 'ReloadNotPermitted', '只有管理员可以重新加载游戏数据'),
+(1903, '1903', 'ReloadFailed', '重新加载游戏数据失败，继续使用原有数据');
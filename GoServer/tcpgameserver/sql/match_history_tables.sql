@@ -0,0 +1,33 @@
+-- 对局记录表
+CREATE TABLE IF NOT EXISTS MatchHistory (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY COMMENT '对局ID',
+    room_id VARCHAR(64) NOT NULL COMMENT '房间ID',
+    winner VARCHAR(50) DEFAULT NULL COMMENT '胜者用户名，平局为空',
+    end_reason VARCHAR(32) NOT NULL COMMENT '结束原因',
+    turn_count INT NOT NULL DEFAULT 0 COMMENT '回合数',
+    started_at DATETIME NOT NULL COMMENT '开始时间',
+    ended_at DATETIME NOT NULL COMMENT '结束时间',
+    KEY idx_ended_at (ended_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='对局记录表';
+
+-- 对局参与者表
+CREATE TABLE IF NOT EXISTS MatchParticipants (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    match_id BIGINT NOT NULL COMMENT '对局ID',
+    username VARCHAR(50) NOT NULL COMMENT '玩家用户名',
+    result VARCHAR(8) NOT NULL COMMENT '对局结果：win, loss, draw',
+    final_health DECIMAL(6,1) NOT NULL DEFAULT 0 COMMENT '结束时血量',
+    damage_dealt DECIMAL(8,1) NOT NULL DEFAULT 0 COMMENT '造成伤害',
+    damage_taken DECIMAL(8,1) NOT NULL DEFAULT 0 COMMENT '承受伤害',
+    bonds_triggered JSON COMMENT '羁绊触发次数 {羁绊名称: 次数}',
+    FOREIGN KEY (match_id) REFERENCES MatchHistory(id) ON DELETE CASCADE,
+    KEY idx_username (username),
+    KEY idx_match_id (match_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='对局参与者表';
+
+-- 对局历史响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1201, '1201', 'MatchHistorySuccess', '获取对局历史成功'),
+(1202, '1202', 'MatchHistoryInvalidData', '请求数据格式错误'),
+(1203, '1203', 'MatchHistoryNotLoggedIn', '用户未登录'),
+(1204, '1204', 'MatchHistoryQueryFailed', '获取对局历史失败');
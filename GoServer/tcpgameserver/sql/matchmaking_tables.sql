@@ -0,0 +1,16 @@
+-- 玩家评分表
+CREATE TABLE IF NOT EXISTS PlayerRatings (
+    username VARCHAR(50) NOT NULL PRIMARY KEY COMMENT '玩家用户名（UserAccount.username）',
+    rating DOUBLE NOT NULL DEFAULT 1500 COMMENT 'ELO评分',
+    games_played INT NOT NULL DEFAULT 0 COMMENT '已结算的对局数',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    KEY idx_rating (rating)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='玩家评分表';
+
+-- 匹配队列响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1401, '1401', 'QueueStatus', '匹配排队中'),
+(1402, '1402', 'QueueCancelled', '已取消匹配'),
+(1403, '1403', 'NotInQueue', '当前不在匹配队列中'),
+(1404, '1404', 'QueueNotLoggedIn', '用户未登录'),
+(1406, '1406', 'AlreadyInQueue', '已在匹配队列中');
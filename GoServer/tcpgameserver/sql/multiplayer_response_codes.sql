@@ -0,0 +1,3 @@
+-- 多人房间响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1405, '1405', 'InvalidRoomSize', '房间人数无效，仅支持2-4人');
@@ -0,0 +1,16 @@
+-- 暂停与和棋响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1801, '1801', 'GamePaused', '对局已暂停'),
+(1802, '1802', 'GameResumed', '对局已恢复'),
+(1803, '1803', 'PauseNotInGame', '玩家不在进行中的对局中'),
+(1804, '1804', 'PauseLimitReached', '暂停次数已用完'),
+(1805, '1805', 'PauseTimeExhausted', '本局暂停时间已用完'),
+(1806, '1806', 'GameAlreadyPaused', '对局已处于暂停状态'),
+(1807, '1807', 'CannotResume', '只有发起暂停的玩家可以恢复对局'),
+(1811, '1811', 'DrawOffered', '玩家提议和棋'),
+(1812, '1812', 'DrawAccepted', '玩家同意和棋'),
+(1813, '1813', 'DrawDeclined', '玩家拒绝和棋'),
+(1814, '1814', 'DrawOfferPending', '已有待回应的和棋提议'),
+(1815, '1815', 'DrawOfferTooSoon', '本回合已提议过和棋'),
+(1816, '1816', 'NoDrawOffer', '没有待回应的和棋提议'),
+(1817, '1817', 'InvalidDrawResponse', '和棋回应数据无效');
@@ -0,0 +1,15 @@
+-- 私人房间响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1501, '1501', 'RoomCreated', '房间创建成功'),
+(1502, '1502', 'RoomJoined', '加入房间成功'),
+(1503, '1503', 'RoomLeft', '已离开房间'),
+(1504, '1504', 'RoomUpdate', '房间信息更新'),
+(1505, '1505', 'RoomNotFound', '邀请码无效或房间不存在'),
+(1506, '1506', 'RoomFull', '房间已满'),
+(1507, '1507', 'RoomAlreadyStarted', '房间已开始游戏'),
+(1508, '1508', 'NotRoomHost', '只有房主可以执行该操作'),
+(1509, '1509', 'InvalidRoomSettings', '房间设置无效'),
+(1510, '1510', 'NotInRoom', '当前不在房间中'),
+(1511, '1511', 'PlayerBusy', '当前正在匹配、房间或游戏中'),
+(1512, '1512', 'NotEnoughPlayers', '房间人数不足，至少需要2名玩家'),
+(1513, '1513', 'RoomNotLoggedIn', '用户未登录');
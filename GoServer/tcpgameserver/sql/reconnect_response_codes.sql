@@ -0,0 +1,5 @@
+-- 断线重连响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(6003, '6003', 'InvalidSessionToken', '会话令牌无效或已失效'),
+(7003, '7003', 'ReconnectCountdown', '等待断线玩家重连'),
+(7004, '7004', 'ReconnectExpired', '断线玩家未在规定时间内重连，判负');
@@ -0,0 +1,8 @@
+-- 对局回放响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1301, '1301', 'ReplaySuccess', '获取对局回放成功'),
+(1302, '1302', 'ReplayInvalidData', '请求数据格式错误'),
+(1303, '1303', 'ReplayNotLoggedIn', '用户未登录'),
+(1304, '1304', 'ReplayNotFound', '对局回放不存在'),
+(1305, '1305', 'ReplayGameInProgress', '对局尚未结束，无法查看回放'),
+(1306, '1306', 'ReplayEntryTooLarge', '回放条目超过单条消息大小上限');
@@ -0,0 +1,4 @@
+-- 管理员指定开局随机种子
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1514, '1514', 'RoomSeedNotPermitted', '只有管理员可以指定房间随机种子'),
+(2405, '2405', 'PracticeSeedNotPermitted', '只有管理员可以指定练习随机种子');
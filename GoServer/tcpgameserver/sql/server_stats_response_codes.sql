@@ -0,0 +1,4 @@
+-- 服务器运行状态
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1904, '1904', 'ServerStats', '获取服务器运行状态成功'),
+(1905, '1905', 'ServerStatsNotPermitted', '只有管理员可以查看服务器运行状态');
@@ -0,0 +1,7 @@
+-- 登录会话令牌与密码哈希
+-- bcrypt哈希长度为60，扩展密码列；已有的明文密码在用户下次登录成功时迁移为bcrypt哈希
+ALTER TABLE UserAccount MODIFY COLUMN password VARCHAR(255) NOT NULL;
+
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(2006, '2006', 'LoginSessionTokenInvalid', '会话令牌无效或已被新的登录取代'),
+(2007, '2007', 'SessionTokenExpired', '会话令牌已过期，请重新登录');
@@ -0,0 +1,13 @@
+-- 观战响应码
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(1601, '1601', 'SpectateStarted', '开始观战'),
+(1602, '1602', 'SpectatorView', '观战画面更新'),
+(1603, '1603', 'SpectateStopped', '已停止观战'),
+(1604, '1604', 'SpectatorJoined', '观战者加入'),
+(1605, '1605', 'SpectatorLeft', '观战者离开'),
+(1606, '1606', 'SpectateGameEnded', '观战的对局已结束'),
+(1607, '1607', 'SpectateRoomNotFound', '房间不存在或未在游戏中'),
+(1608, '1608', 'SpectatorsFull', '观战人数已满'),
+(1609, '1609', 'NotSpectating', '当前不在观战中'),
+(1610, '1610', 'SpectateNotLoggedIn', '用户未登录'),
+(1611, '1611', 'SpectateBusy', '当前正在匹配、房间或游戏中');
@@ -0,0 +1,11 @@
+-- 游戏账户关联Voyara用户（voyara_users.id），Voyara与游戏使用不同的数据库，不设置外键
+-- 通过Voyara访问令牌首次登录时自动创建的游戏账户密码为空，不能使用密码登录
+ALTER TABLE UserAccount
+    ADD COLUMN voyara_user_id INT NULL COMMENT '关联的Voyara用户ID（voyara_users.id）',
+    ADD UNIQUE KEY uk_user_account_voyara_user_id (voyara_user_id);
+
+INSERT INTO ResponseInfo (id, code, response_key, message) VALUES
+(2008, '2008', 'InvalidVoyaraToken', 'Voyara访问令牌无效或已过期'),
+(2009, '2009', 'VoyaraAccountLinked', '游戏账户已关联Voyara账户'),
+(2010, '2010', 'VoyaraAccountLinkConflict', '游戏账户或Voyara账户已关联其他账户'),
+(2011, '2011', 'LinkVoyaraNotLoggedIn', '请先登录游戏账户');
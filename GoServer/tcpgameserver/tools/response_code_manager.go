@@ -3,7 +3,6 @@ package tools
 import (
 	"GoServer/tcpgameserver/models"
 	"GoServer/tcpgameserver/service"
-	"fmt"
 	"sync"
 )
 
@@ -28,27 +27,41 @@ func GetResponseCodeManager() *ResponseCodeManager {
 	return responseManager
 }
 
-// LoadResponseCodes 从数据库加载响应码
+// LoadResponseCodes 加载响应码（数据包文件或MySQL）并整体替换，加载失败时保留原有响应码
 func LoadResponseCodes() error {
-	manager := GetResponseCodeManager()
-	manager.mutex.Lock()
-	defer manager.mutex.Unlock()
-
-	// 从数据库获取所有响应码
-	responseInfos, err := service.GetAllResponseInfo()
+	codes, err := FetchResponseCodes()
 	if err != nil {
-		return fmt.Errorf("failed to load response codes from database: %v", err)
+		return err
 	}
+	ApplyResponseCodes(codes)
+	return nil
+}
 
-	// 清空现有数据
-	manager.codes = make(map[int]models.ResponseInfo)
+// FetchResponseCodes 读取所有响应码，不影响当前使用的响应码
+func FetchResponseCodes() (map[int]models.ResponseInfo, error) {
+	pack, err := service.LoadGameData()
+	if err != nil {
+		return nil, err
+	}
+	return BuildResponseCodes(pack.ResponseCodes), nil
+}
 
-	// 将数据存储到内存中
+// BuildResponseCodes 创建响应码ID到响应信息的映射
+func BuildResponseCodes(responseInfos []models.ResponseInfo) map[int]models.ResponseInfo {
+	codes := make(map[int]models.ResponseInfo, len(responseInfos))
 	for _, info := range responseInfos {
-		manager.codes[info.ID] = info
+		codes[info.ID] = info
 	}
+	return codes
+}
 
-	return nil
+// ApplyResponseCodes 整体替换当前使用的响应码
+func ApplyResponseCodes(codes map[int]models.ResponseInfo) {
+	manager := GetResponseCodeManager()
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.codes = codes
 }
 
 // GetResponseByID 根据ID获取响应码信息
@@ -1,7 +1,7 @@
 package types
 
 import (
-	"net"
+	"GoServer/tcpgameserver/protocol"
 	"sync"
 	"time"
 )
@@ -13,6 +13,8 @@ const (
 	StatusConnected        PlayerStatus = "connected"         // 已连接
 	StatusLoggedIn         PlayerStatus = "logged_in"         // 已登录
 	StatusReady            PlayerStatus = "ready"             // 准备就绪
+	StatusInRoom           PlayerStatus = "in_room"           // 在私人房间中等待开始
+	StatusSpectating       PlayerStatus = "spectating"        // 观战中
 	StatusInGame           PlayerStatus = "in_game"           // 游戏中
 	StatusWaitingReconnect PlayerStatus = "waiting_reconnect" // 等待重连
 	StatusDisconnected     PlayerStatus = "disconnected"      // 已断开连接
@@ -21,11 +23,11 @@ const (
 // ClientInfo 客户端连接信息
 type ClientInfo struct {
 	// 连接信息
-	Conn         net.Conn  `json:"-"`             // 网络连接（不序列化）
-	ClientID     string    `json:"client_id"`     // 客户端唯一ID
-	RemoteAddr   string    `json:"remote_addr"`   // 客户端地址
-	ConnectedAt  time.Time `json:"connected_at"`  // 连接时间
-	LastActivity time.Time `json:"last_activity"` // 最后活动时间
+	Conn         protocol.Conn `json:"-"`             // 客户端连接，TCP或WebSocket（不序列化）
+	ClientID     string        `json:"client_id"`     // 客户端唯一ID
+	RemoteAddr   string        `json:"remote_addr"`   // 客户端地址
+	ConnectedAt  time.Time     `json:"connected_at"`  // 连接时间
+	LastActivity time.Time     `json:"last_activity"` // 最后活动时间
 	// 用户信息
 	Username   string `json:"username,omitempty"` // 用户名（登录后绑定）
 	IsLoggedIn bool   `json:"is_logged_in"`       // 是否已登录
@@ -33,6 +35,7 @@ type ClientInfo struct {
 	// 状态信息
 	Status     PlayerStatus `json:"status"`                 // 玩家状态
 	GameRoomID string       `json:"game_room_id,omitempty"` // 所在游戏房间ID
+	DeckName   string       `json:"deck_name,omitempty"`    // 下一局使用的卡组名称，为空时使用共享卡牌池
 
 	// 扩展信息
 	Metadata map[string]interface{} `json:"metadata,omitempty"` // 额外的元数据
@@ -42,7 +45,7 @@ type ClientInfo struct {
 }
 
 // NewClientInfo 创建新的客户端信息
-func NewClientInfo(conn net.Conn, clientID string) *ClientInfo {
+func NewClientInfo(conn protocol.Conn, clientID string) *ClientInfo {
 	return &ClientInfo{
 		Conn:         conn,
 		ClientID:     clientID,
@@ -108,6 +111,20 @@ func (c *ClientInfo) GetGameRoom() string {
 	return c.GameRoomID
 }
 
+// SetDeck 设置下一局使用的卡组
+func (c *ClientInfo) SetDeck(deckName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.DeckName = deckName
+}
+
+// GetDeck 获取下一局使用的卡组名称
+func (c *ClientInfo) GetDeck() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.DeckName
+}
+
 // SetMetadata 设置元数据
 func (c *ClientInfo) SetMetadata(key string, value interface{}) {
 	c.mutex.Lock()
@@ -2,21 +2,101 @@ package types
 
 import (
 	"GoServer/tcpgameserver/models"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
+	"time"
 )
 
+// 回合超时策略
+const (
+	TimeoutPolicyPass     = "pass"      // 超时自动跳过回合
+	TimeoutPolicyAutoPlay = "auto_play" // 超时自动打出伤害最低的一张手牌
+)
+
+// 房间默认设置
+const (
+	DefaultTurnTimeout            = 30 * time.Second // 默认回合时长
+	DefaultMaxConsecutiveTimeouts = 3                // 默认连续超时判负次数
+	DefaultInitialHealth          = 50               // 默认初始血量
+	DefaultMaxHandCards           = 10               // 默认最大手牌数量
+	DefaultOpeningHandSize        = 6                // 默认初始手牌数量
+	DefaultReconnectWindow        = 60 * time.Second // 默认断线重连等待时间
+)
+
+// 房间设置取值范围
+const (
+	MaxInitialHealthSetting       = 500               // 初始血量上限
+	MaxHandCardsSetting           = 20                // 最大手牌数量上限
+	MinTurnTimeoutSetting         = 5 * time.Second   // 回合时长下限
+	MaxTurnTimeoutSetting         = 300 * time.Second // 回合时长上限
+	MinReconnectWindowSetting     = 10 * time.Second  // 断线重连等待时间下限
+	MaxReconnectWindowSetting     = 600 * time.Second // 断线重连等待时间上限
+	MaxFatigueDamageSetting       = 50                // 疲劳伤害上限
+	MaxConsecutiveTimeoutsSetting = 10                // 连续超时判负次数上限
+)
+
+// 暂停与恢复原因
+const (
+	PauseReasonPlayer     = "player"     // 玩家主动暂停/恢复
+	PauseReasonDisconnect = "disconnect" // 玩家断线自动暂停
+	PauseReasonReconnect  = "reconnect"  // 断线玩家重连后自动恢复
+	PauseReasonTimeout    = "timeout"    // 暂停时间用完自动恢复
+	PauseReasonEliminated = "eliminated" // 发起暂停的玩家被淘汰后自动恢复
+)
+
+// 暂停与和棋相关错误
+var (
+	ErrGamePaused         = errors.New("game is already paused")
+	ErrGameNotPaused      = errors.New("game is not paused")
+	ErrPauseLimitReached  = errors.New("player has no pauses left")
+	ErrPauseTimeExhausted = errors.New("room has no pause time left")
+	ErrDrawOfferPending   = errors.New("a draw offer is already pending")
+	ErrDrawOfferTooSoon   = errors.New("player already offered a draw this turn")
+	ErrNoDrawOffer        = errors.New("no pending draw offer")
+)
+
+// PauseState 对局暂停状态
+type PauseState struct {
+	Paused      bool           `json:"paused"`       // 是否暂停中
+	PausedBy    string         `json:"paused_by"`    // 发起暂停的玩家
+	Reason      string         `json:"reason"`       // 暂停原因：player, disconnect
+	PausedAt    time.Time      `json:"paused_at"`    // 本次暂停开始时间
+	Duration    time.Duration  `json:"duration"`     // 本次暂停的最长时长，到期自动恢复
+	TotalPaused time.Duration  `json:"total_paused"` // 本局已暂停的累计时长
+	PauseCounts map[string]int `json:"pause_counts"` // 每名玩家主动暂停的次数
+}
+
+// DrawOffer 和棋提议，所有存活玩家同意后以平局结束对局
+type DrawOffer struct {
+	OfferedBy string          `json:"offered_by"` // 提议的玩家
+	Accepted  map[string]bool `json:"accepted"`   // 已同意的玩家
+}
+
 // PlayerInfo 房间内玩家信息
 type PlayerInfo struct {
 	Username      string                       `json:"username"`       // 玩家用户名
 	HandCards     []models.Card                `json:"hand_cards"`     // 手牌列表
+	DrawPile      []models.Card                `json:"draw_pile"`      // 玩家卡组构成的抽牌堆，UsesDeck为false时从共享1级卡牌池抽牌
+	DiscardPile   []models.Card                `json:"discard_pile"`   // 玩家卡组的弃牌堆，抽牌堆抽空时洗回抽牌堆
+	UsesDeck      bool                         `json:"uses_deck"`      // 是否使用自己的卡组
 	MaxHealth     float64                      `json:"max_health"`     // 总血量
 	CurrentHealth float64                      `json:"current_health"` // 当前血量
 	IsReady       bool                         `json:"is_ready"`       // 是否准备就绪
 	Round         string                       `json:"round"`          // 是否是当前回合玩家
 	OtherPlayers  []models.OtherPlayerGameInfo `json:"OtherPlayer"`    // 其他玩家信息
 	DamageInfo    []models.DamageInfo          `json:"DamageInfo"`     // 伤害信息列表
+
+	ConsecutiveTimeouts int `json:"consecutive_timeouts"` // 连续回合超时次数
+
+	StatusEffects []models.StatusEffect `json:"status_effects"` // 身上的状态效果（护盾、中毒、眩晕等）
+
+	// 对局统计
+	DamageDealt    float64        `json:"damage_dealt"`    // 累计造成伤害
+	DamageTaken    float64        `json:"damage_taken"`    // 累计承受伤害
+	BondsTriggered map[string]int `json:"bonds_triggered"` // 羁绊触发次数，key为羁绊名称
 }
 
 // RoomInfo 游戏房间信息
@@ -27,17 +107,53 @@ type RoomInfo struct {
 	MaxPlayers int    `json:"max_players"` // 最大玩家数量
 	Status     string `json:"status"`      // 房间状态：waiting, ready, playing, finished
 
+	// 私人房间信息
+	Host       string `json:"host"`        // 房主用户名，匹配房间为空
+	InviteCode string `json:"invite_code"` // 邀请码，匹配房间为空
+	FixedSeed  int64  `json:"fixed_seed"`  // 管理员指定的开局随机种子，0表示随机
+
 	// 玩家信息
-	Players map[string]*PlayerInfo `json:"players"` // 玩家列表，key为username
+	Players   map[string]*PlayerInfo `json:"players"`    // 玩家列表，key为username
+	TurnOrder []string               `json:"turn_order"` // 座位顺序，按加入房间的顺序轮流行动
+
+	// 观战者用户名，观战者不占用玩家位置
+	Spectators []string `json:"spectators"`
 
 	// 共享卡牌池
 	Level1CardPool []models.Card `json:"level1_card_pool"` // 1级共享卡牌池
 	Level2CardPool []models.Card `json:"level2_card_pool"` // 2级共享卡牌池
 	Level3CardPool []models.Card `json:"level3_card_pool"` // 3级共享卡牌池
+	DiscardPile    []models.Card `json:"discard_pile"`     // 共享1级卡牌池的弃牌堆，打出和合成消耗的1级卡牌进入弃牌堆，卡牌池抽空时洗回
+
+	// 开局时的卡牌集快照，卡牌和羁绊热加载不影响进行中的房间
+	CardSet *models.CardSet `json:"-"`
 
 	// 游戏设置
-	InitialHealth float64 `json:"initial_health"` // 初始血量
-	MaxHandCards  int     `json:"max_hand_cards"` // 最大手牌数量
+	InitialHealth   float64 `json:"initial_health"`    // 初始血量
+	MaxHandCards    int     `json:"max_hand_cards"`    // 最大手牌数量
+	OpeningHandSize int     `json:"opening_hand_size"` // 初始手牌数量
+	FatigueDamage   float64 `json:"fatigue_damage"`    // 抽牌堆和弃牌堆都为空时每少抽一张卡牌受到的伤害，0表示关闭
+
+	// 回合超时设置
+	TurnTimeout            time.Duration `json:"turn_timeout"`             // 回合时长
+	TimeoutPolicy          string        `json:"timeout_policy"`           // 回合超时策略：pass, auto_play
+	MaxConsecutiveTimeouts int           `json:"max_consecutive_timeouts"` // 连续超时判负次数，0表示不判负
+
+	// 断线重连设置
+	ReconnectWindow time.Duration `json:"reconnect_window"` // 断线重连等待时间，超时未重连判负
+
+	// 对局统计
+	StartedAt time.Time `json:"started_at"` // 对局开始时间
+	TurnCount int       `json:"turn_count"` // 已进行的回合数
+
+	// 暂停与和棋
+	Pause          PauseState     `json:"pause"`            // 暂停状态
+	DrawOffer      *DrawOffer     `json:"draw_offer"`       // 待处理的和棋提议
+	DrawOfferTurns map[string]int `json:"draw_offer_turns"` // 玩家上次提议和棋时的回合数
+
+	// 随机数
+	Seed int64      `json:"seed"` // 房间随机种子，初始化卡牌池时生成，记录于回放日志
+	rng  *rand.Rand `json:"-"`    // 房间独立的随机源，所有抽卡均使用该随机源
 
 	// 内部使用
 	mutex sync.RWMutex `json:"-"` // 读写锁
@@ -46,17 +162,471 @@ type RoomInfo struct {
 // NewRoomInfo 创建新的房间信息
 func NewRoomInfo(roomID, roomName string, maxPlayers int) *RoomInfo {
 	return &RoomInfo{
-		RoomID:         roomID,
-		RoomName:       roomName,
-		MaxPlayers:     maxPlayers,
-		Status:         "waiting",
-		Players:        make(map[string]*PlayerInfo),
-		Level1CardPool: make([]models.Card, 0),
-		Level2CardPool: make([]models.Card, 0),
-		Level3CardPool: make([]models.Card, 0),
-		InitialHealth:  50, // 默认初始血量
-		MaxHandCards:   10, // 默认最大手牌数量
+		RoomID:          roomID,
+		RoomName:        roomName,
+		MaxPlayers:      maxPlayers,
+		Status:          "waiting",
+		Players:         make(map[string]*PlayerInfo),
+		TurnOrder:       make([]string, 0, maxPlayers),
+		Spectators:      make([]string, 0),
+		Level1CardPool:  make([]models.Card, 0),
+		Level2CardPool:  make([]models.Card, 0),
+		Level3CardPool:  make([]models.Card, 0),
+		DiscardPile:     make([]models.Card, 0),
+		InitialHealth:   DefaultInitialHealth,
+		MaxHandCards:    DefaultMaxHandCards,
+		OpeningHandSize: DefaultOpeningHandSize,
+
+		TurnTimeout:            DefaultTurnTimeout,
+		TimeoutPolicy:          TimeoutPolicyPass,
+		MaxConsecutiveTimeouts: DefaultMaxConsecutiveTimeouts,
+		ReconnectWindow:        DefaultReconnectWindow,
+	}
+}
+
+// SetCardSet 设置房间使用的卡牌集快照
+func (r *RoomInfo) SetCardSet(cardSet *models.CardSet) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.CardSet = cardSet
+}
+
+// GetCardSet 获取房间使用的卡牌集快照，未设置时返回nil
+func (r *RoomInfo) GetCardSet() *models.CardSet {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.CardSet
+}
+
+// GetCardSetVersion 获取房间使用的卡牌集版本号，未设置时为空
+func (r *RoomInfo) GetCardSetVersion() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.CardSet == nil {
+		return ""
+	}
+	return r.CardSet.Version
+}
+
+// SetRandomSeed 使用指定种子重置房间随机源
+func (r *RoomInfo) SetRandomSeed(seed int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Seed = seed
+	r.rng = rand.New(rand.NewSource(seed))
+}
+
+// randomSource 获取房间随机源，未设置种子时以当前时间生成种子（调用方需持有写锁）
+func (r *RoomInfo) randomSource() *rand.Rand {
+	if r.rng == nil {
+		r.Seed = time.Now().UnixNano()
+		r.rng = rand.New(rand.NewSource(r.Seed))
+	}
+	return r.rng
+}
+
+// RollChance 使用房间随机源按概率判定，概率不小于1时必定成功且不消耗随机数
+func (r *RoomInfo) RollChance(rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.randomSource().Float64() < rate
+}
+
+// ValidTimeoutPolicy 判断回合超时策略是否有效
+func ValidTimeoutPolicy(policy string) bool {
+	return policy == TimeoutPolicyPass || policy == TimeoutPolicyAutoPlay
+}
+
+// SetTimeoutPolicy 设置房间回合超时策略，maxConsecutiveTimeouts为0表示不判负
+func (r *RoomInfo) SetTimeoutPolicy(turnTimeout time.Duration, policy string, maxConsecutiveTimeouts int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !ValidTimeoutPolicy(policy) {
+		return fmt.Errorf("invalid timeout policy: %s", policy)
+	}
+	if turnTimeout <= 0 {
+		return fmt.Errorf("turn timeout must be greater than 0")
+	}
+	if maxConsecutiveTimeouts < 0 {
+		return fmt.Errorf("max consecutive timeouts must not be negative")
+	}
+
+	r.TurnTimeout = turnTimeout
+	r.TimeoutPolicy = policy
+	r.MaxConsecutiveTimeouts = maxConsecutiveTimeouts
+	return nil
+}
+
+// GetSettings 获取房间规则设置
+func (r *RoomInfo) GetSettings() models.RoomSettings {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	maxConsecutiveTimeouts := r.MaxConsecutiveTimeouts
+	return models.RoomSettings{
+		MaxPlayers:             r.MaxPlayers,
+		InitialHealth:          r.InitialHealth,
+		MaxHandCards:           r.MaxHandCards,
+		TurnTimeoutSeconds:     int(r.TurnTimeout / time.Second),
+		OpeningHandSize:        r.OpeningHandSize,
+		ReconnectWindowSeconds: int(r.ReconnectWindow / time.Second),
+		FatigueDamage:          r.FatigueDamage,
+		TimeoutPolicy:          r.TimeoutPolicy,
+		MaxConsecutiveTimeouts: &maxConsecutiveTimeouts,
+	}
+}
+
+// ApplySettings 校验并应用房间规则设置，仅可在游戏开始前修改，已加入玩家的血量同步更新
+func (r *RoomInfo) ApplySettings(settings models.RoomSettings) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Status != "waiting" {
+		return fmt.Errorf("room %s has already started", r.RoomID)
+	}
+
+	turnTimeout := time.Duration(settings.TurnTimeoutSeconds) * time.Second
+	reconnectWindow := time.Duration(settings.ReconnectWindowSeconds) * time.Second
+	switch {
+	case settings.MaxPlayers < models.MinRoomSize || settings.MaxPlayers > models.MaxRoomSize:
+		return fmt.Errorf("max players must be between %d and %d", models.MinRoomSize, models.MaxRoomSize)
+	case settings.MaxPlayers < len(r.Players):
+		return fmt.Errorf("max players %d is less than current players %d", settings.MaxPlayers, len(r.Players))
+	case settings.InitialHealth <= 0 || settings.InitialHealth > MaxInitialHealthSetting:
+		return fmt.Errorf("initial health must be between 1 and %d", MaxInitialHealthSetting)
+	case settings.MaxHandCards <= 0 || settings.MaxHandCards > MaxHandCardsSetting:
+		return fmt.Errorf("max hand cards must be between 1 and %d", MaxHandCardsSetting)
+	case turnTimeout < MinTurnTimeoutSetting || turnTimeout > MaxTurnTimeoutSetting:
+		return fmt.Errorf("turn timeout must be between %v and %v", MinTurnTimeoutSetting, MaxTurnTimeoutSetting)
+	case settings.OpeningHandSize <= 0 || settings.OpeningHandSize > settings.MaxHandCards:
+		return fmt.Errorf("opening hand size must be between 1 and max hand cards %d", settings.MaxHandCards)
+	case reconnectWindow < MinReconnectWindowSetting || reconnectWindow > MaxReconnectWindowSetting:
+		return fmt.Errorf("reconnect window must be between %v and %v", MinReconnectWindowSetting, MaxReconnectWindowSetting)
+	case settings.FatigueDamage < 0 || settings.FatigueDamage > MaxFatigueDamageSetting:
+		return fmt.Errorf("fatigue damage must be between 0 and %d", MaxFatigueDamageSetting)
+	case !ValidTimeoutPolicy(settings.TimeoutPolicy):
+		return fmt.Errorf("timeout policy must be %s or %s", TimeoutPolicyPass, TimeoutPolicyAutoPlay)
+	case settings.MaxConsecutiveTimeouts == nil || *settings.MaxConsecutiveTimeouts < 0 || *settings.MaxConsecutiveTimeouts > MaxConsecutiveTimeoutsSetting:
+		return fmt.Errorf("max consecutive timeouts must be between 0 and %d", MaxConsecutiveTimeoutsSetting)
+	}
+
+	r.MaxPlayers = settings.MaxPlayers
+	r.InitialHealth = settings.InitialHealth
+	r.MaxHandCards = settings.MaxHandCards
+	r.TurnTimeout = turnTimeout
+	r.OpeningHandSize = settings.OpeningHandSize
+	r.ReconnectWindow = reconnectWindow
+	r.FatigueDamage = settings.FatigueDamage
+	r.TimeoutPolicy = settings.TimeoutPolicy
+	r.MaxConsecutiveTimeouts = *settings.MaxConsecutiveTimeouts
+
+	for _, player := range r.Players {
+		player.MaxHealth = settings.InitialHealth
+		player.CurrentHealth = settings.InitialHealth
+	}
+	return nil
+}
+
+// SetHost 设置房主
+func (r *RoomInfo) SetHost(username string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Host = username
+}
+
+// GetHost 获取房主
+func (r *RoomInfo) GetHost() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.Host
+}
+
+// SetFixedSeed 设置开局使用的固定随机种子，0表示随机
+func (r *RoomInfo) SetFixedSeed(seed int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.FixedSeed = seed
+}
+
+// GetFixedSeed 获取开局使用的固定随机种子，0表示随机
+func (r *RoomInfo) GetFixedSeed() int64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.FixedSeed
+}
+
+// LobbyInfo 生成私人房间等待信息
+func (r *RoomInfo) LobbyInfo() *models.RoomLobbyInfo {
+	settings := r.GetSettings()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	players := make([]string, len(r.TurnOrder))
+	copy(players, r.TurnOrder)
+
+	return &models.RoomLobbyInfo{
+		RoomID:     r.RoomID,
+		RoomName:   r.RoomName,
+		InviteCode: r.InviteCode,
+		Host:       r.Host,
+		Status:     r.Status,
+		Players:    players,
+		Settings:   settings,
+	}
+}
+
+// GetCurrentPlayer 获取当前回合玩家的用户名
+func (r *RoomInfo) GetCurrentPlayer() (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for username, player := range r.Players {
+		if player.Round == "current" {
+			return username, true
+		}
+	}
+	return "", false
+}
+
+// GetTurnOrder 获取房间座位顺序
+func (r *RoomInfo) GetTurnOrder() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	order := make([]string, len(r.TurnOrder))
+	copy(order, r.TurnOrder)
+	return order
+}
+
+// IsPlayerAlive 检查玩家是否仍存活（血量大于0）
+func (r *RoomInfo) IsPlayerAlive(username string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	player, exists := r.Players[username]
+	return exists && player.CurrentHealth > 0
+}
+
+// GetAlivePlayers 按座位顺序获取存活的玩家
+func (r *RoomInfo) GetAlivePlayers() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	alive := make([]string, 0, len(r.TurnOrder))
+	for _, username := range r.TurnOrder {
+		if player, exists := r.Players[username]; exists && player.CurrentHealth > 0 {
+			alive = append(alive, username)
+		}
+	}
+	return alive
+}
+
+// GetNextAlivePlayer 按座位顺序获取指定玩家之后的下一名存活玩家（不包括该玩家自己）
+func (r *RoomInfo) GetNextAlivePlayer(username string) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	start := -1
+	for i, name := range r.TurnOrder {
+		if name == username {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return "", false
+	}
+
+	for offset := 1; offset < len(r.TurnOrder); offset++ {
+		next := r.TurnOrder[(start+offset)%len(r.TurnOrder)]
+		if player, exists := r.Players[next]; exists && player.CurrentHealth > 0 {
+			return next, true
+		}
+	}
+	return "", false
+}
+
+// IncrementPlayerTimeouts 增加玩家连续超时次数，返回增加后的次数
+func (r *RoomInfo) IncrementPlayerTimeouts(username string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return 0, fmt.Errorf("player %s not found in room", username)
+	}
+
+	player.ConsecutiveTimeouts++
+	return player.ConsecutiveTimeouts, nil
+}
+
+// ResetPlayerTimeouts 重置玩家连续超时次数
+func (r *RoomInfo) ResetPlayerTimeouts(username string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return fmt.Errorf("player %s not found in room", username)
+	}
+
+	player.ConsecutiveTimeouts = 0
+	return nil
+}
+
+// MarkMatchStarted 记录对局开始时间，并将回合数置为第一回合
+func (r *RoomInfo) MarkMatchStarted() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.StartedAt = time.Now()
+	r.TurnCount = 1
+}
+
+// IncrementTurnCount 回合切换时增加回合数
+func (r *RoomInfo) IncrementTurnCount() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.TurnCount++
+}
+
+// RecordDamage 记录一次伤害的造成方与承受方
+func (r *RoomInfo) RecordDamage(attacker, target string, amount float64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	attackerInfo, exists := r.Players[attacker]
+	if !exists {
+		return fmt.Errorf("player %s not found in room", attacker)
+	}
+	targetInfo, exists := r.Players[target]
+	if !exists {
+		return fmt.Errorf("player %s not found in room", target)
+	}
+
+	attackerInfo.DamageDealt += amount
+	targetInfo.DamageTaken += amount
+	return nil
+}
+
+// RecordBondsTriggered 记录玩家触发的羁绊
+func (r *RoomInfo) RecordBondsTriggered(username string, bondNames []string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return fmt.Errorf("player %s not found in room", username)
+	}
+
+	if player.BondsTriggered == nil {
+		player.BondsTriggered = make(map[string]int)
+	}
+	for _, bondName := range bondNames {
+		player.BondsTriggered[bondName]++
+	}
+	return nil
+}
+
+// BuildMatchRecord 根据房间当前数据生成对局记录，winner为空表示平局
+func (r *RoomInfo) BuildMatchRecord(winner, endReason string, endedAt time.Time) *models.MatchRecord {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	startedAt := r.StartedAt
+	if startedAt.IsZero() {
+		startedAt = endedAt
+	}
+
+	record := &models.MatchRecord{
+		RoomID:       r.RoomID,
+		Winner:       winner,
+		EndReason:    endReason,
+		TurnCount:    r.TurnCount,
+		StartedAt:    startedAt,
+		EndedAt:      endedAt,
+		Participants: make([]models.MatchParticipant, 0, len(r.Players)),
+	}
+
+	for username, player := range r.Players {
+		result := models.MatchResultLoss
+		if winner == "" {
+			result = models.MatchResultDraw
+		} else if username == winner {
+			result = models.MatchResultWin
+		}
+
+		bondsTriggered := make(map[string]int, len(player.BondsTriggered))
+		for bondName, count := range player.BondsTriggered {
+			bondsTriggered[bondName] = count
+		}
+
+		record.Participants = append(record.Participants, models.MatchParticipant{
+			Username:       username,
+			Result:         result,
+			FinalHealth:    player.CurrentHealth,
+			DamageDealt:    player.DamageDealt,
+			DamageTaken:    player.DamageTaken,
+			BondsTriggered: bondsTriggered,
+		})
 	}
+
+	return record
+}
+
+// GetPlayersHealth 获取所有玩家的当前血量
+func (r *RoomInfo) GetPlayersHealth() map[string]float64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	health := make(map[string]float64, len(r.Players))
+	for username, player := range r.Players {
+		health[username] = player.CurrentHealth
+	}
+	return health
+}
+
+// ReplaySnapshot 生成用于回放校验的房间状态快照
+func (r *RoomInfo) ReplaySnapshot() *models.ReplayState {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	state := &models.ReplayState{
+		Players:         make(map[string]models.ReplayPlayerState, len(r.Players)),
+		Level1PoolCount: len(r.Level1CardPool),
+		Level2PoolCount: len(r.Level2CardPool),
+		Level3PoolCount: len(r.Level3CardPool),
+	}
+
+	for username, player := range r.Players {
+		handCardUIDs := make([]string, 0, len(player.HandCards))
+		for _, card := range player.HandCards {
+			handCardUIDs = append(handCardUIDs, card.UID)
+		}
+		sort.Strings(handCardUIDs)
+
+		state.Players[username] = models.ReplayPlayerState{
+			Health:       player.CurrentHealth,
+			Round:        player.Round,
+			HandCardUIDs: handCardUIDs,
+		}
+	}
+
+	return state
 }
 
 // InitializeCardPools 初始化房间卡牌池（从传入的卡牌池复制）
@@ -74,6 +644,7 @@ func (r *RoomInfo) InitializeCardPools(level1Cards, level2Cards, level3Cards []m
 	r.Level3CardPool = make([]models.Card, len(level3Cards))
 	copy(r.Level3CardPool, level3Cards)
 
+	r.DiscardPile = make([]models.Card, 0)
 	return nil
 }
 
@@ -102,9 +673,12 @@ func (r *RoomInfo) AddPlayer(username string) error {
 		Round:         "waiting",
 		OtherPlayers:  make([]models.OtherPlayerGameInfo, 0),
 		DamageInfo:    make([]models.DamageInfo, 0),
+
+		BondsTriggered: make(map[string]int),
 	}
 
 	r.Players[username] = player
+	r.TurnOrder = append(r.TurnOrder, username)
 	return nil
 }
 
@@ -118,6 +692,12 @@ func (r *RoomInfo) RemovePlayer(username string) error {
 	}
 
 	delete(r.Players, username)
+	for i, name := range r.TurnOrder {
+		if name == username {
+			r.TurnOrder = append(r.TurnOrder[:i], r.TurnOrder[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
@@ -196,6 +776,142 @@ func (r *RoomInfo) SetPlayerHealth(username string, health float64) error {
 	return nil
 }
 
+// ApplyDamage 对玩家造成伤害，护盾优先抵消伤害，返回实际扣除的血量和被护盾抵消的伤害
+func (r *RoomInfo) ApplyDamage(username string, damage float64) (float64, float64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return 0, 0, fmt.Errorf("player %s not found in room", username)
+	}
+	if damage <= 0 {
+		return 0, 0, nil
+	}
+
+	// 依次扣除护盾，耗尽的护盾被移除
+	absorbed := 0.0
+	remaining := player.StatusEffects[:0]
+	for _, effect := range player.StatusEffects {
+		if effect.Type == models.StatusEffectShield && absorbed < damage {
+			used := damage - absorbed
+			if used > effect.Value {
+				used = effect.Value
+			}
+			absorbed += used
+			effect.Value -= used
+			if effect.Value <= 0 {
+				continue
+			}
+		}
+		remaining = append(remaining, effect)
+	}
+	player.StatusEffects = remaining
+
+	dealt := damage - absorbed
+	if dealt > player.CurrentHealth {
+		dealt = player.CurrentHealth
+	}
+	player.CurrentHealth -= dealt
+	return dealt, absorbed, nil
+}
+
+// AddStatusEffect 为玩家附加状态效果，按效果类型的叠加规则与已有的同类效果合并
+func (r *RoomInfo) AddStatusEffect(username string, effect models.StatusEffect) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return fmt.Errorf("player %s not found in room", username)
+	}
+	if effect.TurnsLeft <= 0 {
+		return fmt.Errorf("status effect %s must last at least one turn", effect.Type)
+	}
+
+	rule := models.StatusEffectStackRules[effect.Type]
+	if rule == models.StackAdd || rule == models.StackRefresh {
+		for i := range player.StatusEffects {
+			existing := &player.StatusEffects[i]
+			if existing.Type != effect.Type {
+				continue
+			}
+
+			if rule == models.StackAdd {
+				existing.Value += effect.Value
+			} else if effect.Value > existing.Value {
+				existing.Value = effect.Value
+			}
+			if effect.TurnsLeft > existing.TurnsLeft {
+				existing.TurnsLeft = effect.TurnsLeft
+			}
+			existing.Source = effect.Source
+			existing.Bond = effect.Bond
+			return nil
+		}
+	}
+
+	player.StatusEffects = append(player.StatusEffects, effect)
+	return nil
+}
+
+// TickStatusEffects 玩家回合开始时结算状态效果：返回本回合生效的效果，剩余回合数减一并移除已结束的效果
+func (r *RoomInfo) TickStatusEffects(username string) ([]models.StatusEffect, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return nil, fmt.Errorf("player %s not found in room", username)
+	}
+
+	active := make([]models.StatusEffect, 0, len(player.StatusEffects))
+	remaining := player.StatusEffects[:0]
+	for _, effect := range player.StatusEffects {
+		active = append(active, effect)
+		effect.TurnsLeft--
+		if effect.TurnsLeft > 0 {
+			remaining = append(remaining, effect)
+		}
+	}
+	player.StatusEffects = remaining
+	return active, nil
+}
+
+// GetStatusEffects 获取玩家身上状态效果的副本
+func (r *RoomInfo) GetStatusEffects(username string) []models.StatusEffect {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	effects := make([]models.StatusEffect, 0)
+	if player, exists := r.Players[username]; exists {
+		effects = append(effects, player.StatusEffects...)
+	}
+	return effects
+}
+
+// DiscardRandomCards 使用房间随机源从玩家手牌中随机弃置指定数量的卡牌放入弃牌堆，手牌不足时全部弃置
+func (r *RoomInfo) DiscardRandomCards(username string, count int) ([]models.Card, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return nil, fmt.Errorf("player %s not found in room", username)
+	}
+
+	var discarded []models.Card
+	for i := 0; i < count && len(player.HandCards) > 0; i++ {
+		randomIndex := r.randomSource().Intn(len(player.HandCards))
+		discarded = append(discarded, player.HandCards[randomIndex])
+		player.HandCards = append(player.HandCards[:randomIndex:randomIndex], player.HandCards[randomIndex+1:]...)
+	}
+	if err := r.discardCards(username, discarded); err != nil {
+		return nil, err
+	}
+	return discarded, nil
+}
+
 // SetOpponentPlayerDamage 设置其他玩家触发的伤害信息
 func (r *RoomInfo) SetPlayerDamage(username string, DamageInfo models.DamageInfo) error {
 	r.mutex.Lock()
@@ -281,6 +997,56 @@ func (r *RoomInfo) RemoveCardsFromPlayerByUID(username string, cardUIDs []string
 		return fmt.Errorf("player %s not found in room", username)
 	}
 
+	_, err := r.removeHandCards(player, cardUIDs)
+	return err
+}
+
+// DiscardCardsFromPlayerByUID 从玩家手牌中移除指定UID的卡牌并放入弃牌堆
+// 1级卡牌进入玩家抽牌使用的卡牌池对应的弃牌堆，2、3级卡牌放回对应的共享卡牌池供之后合成使用
+func (r *RoomInfo) DiscardCardsFromPlayerByUID(username string, cardUIDs []string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return fmt.Errorf("player %s not found in room", username)
+	}
+
+	removedCards, err := r.removeHandCards(player, cardUIDs)
+	if err != nil {
+		return err
+	}
+	return r.discardCards(username, removedCards)
+}
+
+// DiscardCards 将不在手牌中的卡牌（如连锁合成的中间产物）放入玩家的弃牌堆，2、3级卡牌放回对应的共享卡牌池
+func (r *RoomInfo) DiscardCards(username string, cards []models.Card) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.discardCards(username, cards)
+}
+
+// discardCards 将离开手牌的卡牌放入弃牌堆，2、3级卡牌放回对应的共享卡牌池（需持有锁）
+func (r *RoomInfo) discardCards(username string, cards []models.Card) error {
+	_, discardPile, err := r.playerDrawSource(username)
+	if err != nil {
+		return err
+	}
+	for _, card := range cards {
+		switch card.Level {
+		case 2:
+			r.Level2CardPool = append(r.Level2CardPool, card)
+		case 3:
+			r.Level3CardPool = append(r.Level3CardPool, card)
+		default:
+			*discardPile = append(*discardPile, card)
+		}
+	}
+	return nil
+}
+
+// removeHandCards 从玩家手牌中移除指定UID的卡牌，返回移除的卡牌（需持有锁）
+func (r *RoomInfo) removeHandCards(player *PlayerInfo, cardUIDs []string) ([]models.Card, error) {
 	// 创建UID映射用于快速查找
 	uidMap := make(map[string]bool)
 	for _, uid := range cardUIDs {
@@ -289,24 +1055,24 @@ func (r *RoomInfo) RemoveCardsFromPlayerByUID(username string, cardUIDs []string
 
 	// 过滤出不在移除列表中的卡牌
 	var remainingCards []models.Card
-	removedCount := 0
+	var removedCards []models.Card
 
 	for _, card := range player.HandCards {
 		if uidMap[card.UID] {
-			removedCount++
+			removedCards = append(removedCards, card)
 		} else {
 			remainingCards = append(remainingCards, card)
 		}
 	}
 
 	// 检查是否所有要移除的卡牌都找到了
-	if removedCount != len(cardUIDs) {
-		return fmt.Errorf("could not find all cards to remove: expected %d, found %d", len(cardUIDs), removedCount)
+	if len(removedCards) != len(cardUIDs) {
+		return nil, fmt.Errorf("could not find all cards to remove: expected %d, found %d", len(cardUIDs), len(removedCards))
 	}
 
 	// 更新玩家手牌
 	player.HandCards = remainingCards
-	return nil
+	return removedCards, nil
 }
 
 // SetPlayerReady 设置玩家准备状态
@@ -345,7 +1111,30 @@ func (r *RoomInfo) GetRoomStats() map[string]interface{} {
 		"level1_cards":    len(r.Level1CardPool),
 		"level2_cards":    len(r.Level2CardPool),
 		"level3_cards":    len(r.Level3CardPool),
+		"seed":            r.Seed,
+	}
+}
+
+// HasCardInPool 检查指定等级的共享卡牌池中是否还有指定名称的卡牌
+func (r *RoomInfo) HasCardInPool(cardName string, level int) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var cardPool []models.Card
+	switch level {
+	case 1:
+		cardPool = r.Level1CardPool
+	case 2:
+		cardPool = r.Level2CardPool
+	case 3:
+		cardPool = r.Level3CardPool
+	}
+	for _, card := range cardPool {
+		if card.Name == cardName {
+			return true
+		}
 	}
+	return false
 }
 
 // GetSharedCardPool 获取指定等级的共享卡牌池
@@ -378,6 +1167,197 @@ func (r *RoomInfo) UpdateRoomStatus(status string) {
 	r.Status = status
 }
 
+// AddSpectator 添加观战者，观战人数达到上限时返回错误
+func (r *RoomInfo) AddSpectator(username string, maxSpectators int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.Players[username]; exists {
+		return fmt.Errorf("player %s cannot spectate their own room", username)
+	}
+	for _, spectator := range r.Spectators {
+		if spectator == username {
+			return fmt.Errorf("spectator %s already in room", username)
+		}
+	}
+	if len(r.Spectators) >= maxSpectators {
+		return fmt.Errorf("room %s has reached the spectator limit %d", r.RoomID, maxSpectators)
+	}
+
+	r.Spectators = append(r.Spectators, username)
+	return nil
+}
+
+// RemoveSpectator 移除观战者，返回是否移除成功
+func (r *RoomInfo) RemoveSpectator(username string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, spectator := range r.Spectators {
+		if spectator == username {
+			r.Spectators = append(r.Spectators[:i], r.Spectators[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetSpectators 获取观战者列表
+func (r *RoomInfo) GetSpectators() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	spectators := make([]string, len(r.Spectators))
+	copy(spectators, r.Spectators)
+	return spectators
+}
+
+// BeginPause 开始暂停，返回本次暂停的时长（不超过单次时长和本局剩余的暂停时间）
+// 玩家主动暂停计入该玩家的暂停次数，maxPausesPerPlayer为0表示不限制次数
+func (r *RoomInfo) BeginPause(username, reason string, maxDuration, totalBudget time.Duration, maxPausesPerPlayer int) (time.Duration, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Pause.Paused {
+		return 0, ErrGamePaused
+	}
+	if reason == PauseReasonPlayer && maxPausesPerPlayer > 0 && r.Pause.PauseCounts[username] >= maxPausesPerPlayer {
+		return 0, ErrPauseLimitReached
+	}
+
+	duration := maxDuration
+	if remaining := totalBudget - r.Pause.TotalPaused; remaining < duration {
+		duration = remaining
+	}
+	if duration <= 0 {
+		return 0, ErrPauseTimeExhausted
+	}
+
+	if reason == PauseReasonPlayer {
+		if r.Pause.PauseCounts == nil {
+			r.Pause.PauseCounts = make(map[string]int)
+		}
+		r.Pause.PauseCounts[username]++
+	}
+	r.Pause.Paused = true
+	r.Pause.PausedBy = username
+	r.Pause.Reason = reason
+	r.Pause.PausedAt = time.Now()
+	r.Pause.Duration = duration
+	return duration, nil
+}
+
+// EndPause 结束暂停并累计暂停时长，返回结束前的暂停状态
+func (r *RoomInfo) EndPause() (PauseState, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.Pause.Paused {
+		return PauseState{}, ErrGameNotPaused
+	}
+
+	previous := r.Pause
+	elapsed := time.Since(r.Pause.PausedAt)
+	if elapsed > r.Pause.Duration {
+		elapsed = r.Pause.Duration
+	}
+	r.Pause.TotalPaused += elapsed
+	r.Pause.Paused = false
+	r.Pause.PausedBy = ""
+	r.Pause.Reason = ""
+	return previous, nil
+}
+
+// IsPaused 检查对局是否暂停中
+func (r *RoomInfo) IsPaused() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.Pause.Paused
+}
+
+// GetPauseState 获取暂停状态副本
+func (r *RoomInfo) GetPauseState() PauseState {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	state := r.Pause
+	state.PauseCounts = make(map[string]int, len(r.Pause.PauseCounts))
+	for username, count := range r.Pause.PauseCounts {
+		state.PauseCounts[username] = count
+	}
+	return state
+}
+
+// OfferDraw 玩家提议和棋，同一回合内每名玩家只能提议一次
+func (r *RoomInfo) OfferDraw(username string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.DrawOffer != nil {
+		return ErrDrawOfferPending
+	}
+	if turn, exists := r.DrawOfferTurns[username]; exists && turn == r.TurnCount {
+		return ErrDrawOfferTooSoon
+	}
+
+	if r.DrawOfferTurns == nil {
+		r.DrawOfferTurns = make(map[string]int)
+	}
+	r.DrawOfferTurns[username] = r.TurnCount
+	r.DrawOffer = &DrawOffer{
+		OfferedBy: username,
+		Accepted:  map[string]bool{username: true},
+	}
+	return nil
+}
+
+// RespondDraw 玩家回应和棋提议：拒绝时撤销提议，所有存活玩家都同意时返回true并清除提议
+func (r *RoomInfo) RespondDraw(username string, accept bool) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.DrawOffer == nil {
+		return false, ErrNoDrawOffer
+	}
+	if !accept {
+		r.DrawOffer = nil
+		return false, nil
+	}
+
+	r.DrawOffer.Accepted[username] = true
+	for _, name := range r.TurnOrder {
+		if player, exists := r.Players[name]; exists && player.CurrentHealth > 0 && !r.DrawOffer.Accepted[name] {
+			return false, nil
+		}
+	}
+	r.DrawOffer = nil
+	return true, nil
+}
+
+// GetDrawOffer 获取待处理的和棋提议发起者
+func (r *RoomInfo) GetDrawOffer() (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.DrawOffer == nil {
+		return "", false
+	}
+	return r.DrawOffer.OfferedBy, true
+}
+
+// TransitionStatus 房间状态为from时切换为to，返回是否切换成功
+func (r *RoomInfo) TransitionStatus(from, to string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Status != from {
+		return false
+	}
+	r.Status = to
+	return true
+}
+
 // IsRoomFull 检查房间是否已满
 func (r *RoomInfo) IsRoomFull() bool {
 	r.mutex.RLock()
@@ -402,6 +1382,161 @@ func (r *RoomInfo) IsAllPlayersReady() bool {
 	return true
 }
 
+// SetPlayerDrawPile 设置玩家卡组构成的抽牌堆，之后该玩家从自己的抽牌堆抽牌
+func (r *RoomInfo) SetPlayerDrawPile(username string, cards []models.Card) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists {
+		return fmt.Errorf("player %s not found in room", username)
+	}
+	player.DrawPile = make([]models.Card, len(cards))
+	copy(player.DrawPile, cards)
+	player.DiscardPile = make([]models.Card, 0)
+	player.UsesDeck = true
+	return nil
+}
+
+// GetDrawPiles 获取使用卡组的玩家的抽牌堆副本，key为用户名
+func (r *RoomInfo) GetDrawPiles() map[string][]models.Card {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	piles := make(map[string][]models.Card)
+	for username, player := range r.Players {
+		if player.UsesDeck {
+			pile := make([]models.Card, len(player.DrawPile))
+			copy(pile, player.DrawPile)
+			piles[username] = pile
+		}
+	}
+	return piles
+}
+
+// playerDrawSource 获取玩家抽牌使用的卡牌池及其弃牌堆：使用卡组时为自己的抽牌堆，否则为共享1级卡牌池（需持有锁）
+func (r *RoomInfo) playerDrawSource(username string) (*[]models.Card, *[]models.Card, error) {
+	player, exists := r.Players[username]
+	if !exists {
+		return nil, nil, fmt.Errorf("player %s not found in room", username)
+	}
+	if player.UsesDeck {
+		return &player.DrawPile, &player.DiscardPile, nil
+	}
+	return &r.Level1CardPool, &r.DiscardPile, nil
+}
+
+// DrawCardsForPlayer 使用房间随机源为玩家抽取多张卡牌（不加入手牌），卡牌不足时返回错误且不抽取
+func (r *RoomInfo) DrawCardsForPlayer(username string, count int) ([]models.Card, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be greater than 0")
+	}
+	pool, _, err := r.playerDrawSource(username)
+	if err != nil {
+		return nil, err
+	}
+	if len(*pool) < count {
+		return nil, fmt.Errorf("not enough cards to draw for %s: requested %d, available %d", username, count, len(*pool))
+	}
+
+	drawnCards := make([]models.Card, 0, count)
+	for i := 0; i < count; i++ {
+		randomIndex := r.randomSource().Intn(len(*pool))
+		drawnCards = append(drawnCards, (*pool)[randomIndex])
+		*pool = append((*pool)[:randomIndex], (*pool)[randomIndex+1:]...)
+	}
+	return drawnCards, nil
+}
+
+// ReturnCardsToPlayerPile 将抽出但未能加入手牌的卡牌放回玩家抽牌使用的卡牌池
+func (r *RoomInfo) ReturnCardsToPlayerPile(username string, cards []models.Card) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	pool, _, err := r.playerDrawSource(username)
+	if err != nil {
+		return err
+	}
+	*pool = append(*pool, cards...)
+	return nil
+}
+
+// DrawResult 玩家抽牌结果
+type DrawResult struct {
+	Cards       []models.Card // 抽到的卡牌
+	PoolEmptied bool          // 抽牌时卡牌池已被抽空
+	Reshuffled  int           // 从弃牌堆洗回卡牌池的卡牌数量
+	Shortfall   int           // 卡牌池和弃牌堆都为空而未能抽到的卡牌数量
+}
+
+// DrawCardsWithReshuffle 使用房间随机源为玩家抽取多张卡牌（不加入手牌）
+// 卡牌池抽空时将弃牌堆洗回卡牌池继续抽取，两者都为空时停止抽取并记录缺少的数量
+func (r *RoomInfo) DrawCardsWithReshuffle(username string, count int) (*DrawResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be greater than 0")
+	}
+	pool, discardPile, err := r.playerDrawSource(username)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DrawResult{Cards: make([]models.Card, 0, count)}
+	for i := 0; i < count; i++ {
+		if len(*pool) == 0 {
+			result.PoolEmptied = true
+			if len(*discardPile) == 0 {
+				result.Shortfall = count - i
+				break
+			}
+			result.Reshuffled += len(*discardPile)
+			*pool = append(*pool, *discardPile...)
+			*discardPile = make([]models.Card, 0)
+		}
+
+		randomIndex := r.randomSource().Intn(len(*pool))
+		result.Cards = append(result.Cards, (*pool)[randomIndex])
+		*pool = append((*pool)[:randomIndex], (*pool)[randomIndex+1:]...)
+	}
+	return result, nil
+}
+
+// GetDrawPileCount 获取玩家可抽取的剩余卡牌数量
+func (r *RoomInfo) GetDrawPileCount(username string) int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	pool, _, err := r.playerDrawSource(username)
+	if err != nil {
+		return 0
+	}
+	return len(*pool)
+}
+
+// GetDiscardPileCount 获取玩家抽牌使用的卡牌池对应的弃牌堆卡牌数量
+func (r *RoomInfo) GetDiscardPileCount(username string) int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, discardPile, err := r.playerDrawSource(username)
+	if err != nil {
+		return 0
+	}
+	return len(*discardPile)
+}
+
+// GetFatigueDamage 获取疲劳伤害，0表示关闭
+func (r *RoomInfo) GetFatigueDamage() float64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.FatigueDamage
+}
+
 // DrawRandomCardFromLevel1Pool 从一级卡牌池随机抽取一张卡牌
 func (r *RoomInfo) DrawRandomCardFromLevel1Pool() (*models.Card, error) {
 	r.mutex.Lock()
@@ -412,7 +1547,7 @@ func (r *RoomInfo) DrawRandomCardFromLevel1Pool() (*models.Card, error) {
 	}
 
 	// 随机选择一个索引
-	randomIndex := rand.Intn(len(r.Level1CardPool))
+	randomIndex := r.randomSource().Intn(len(r.Level1CardPool))
 
 	// 获取选中的卡牌
 	selectedCard := r.Level1CardPool[randomIndex]
@@ -445,7 +1580,7 @@ func (r *RoomInfo) DrawRandomCardsFromLevel1Pool(count int) ([]models.Card, erro
 		}
 
 		// 随机选择一个索引
-		randomIndex := rand.Intn(len(r.Level1CardPool))
+		randomIndex := r.randomSource().Intn(len(r.Level1CardPool))
 
 		// 获取选中的卡牌
 		selectedCard := r.Level1CardPool[randomIndex]
@@ -480,7 +1615,7 @@ func (r *RoomInfo) DrawRandomCardsFromLevel2Pool(count int) ([]models.Card, erro
 		}
 
 		// 随机选择一个索引
-		randomIndex := rand.Intn(len(r.Level2CardPool))
+		randomIndex := r.randomSource().Intn(len(r.Level2CardPool))
 
 		// 获取选中的卡牌
 		selectedCard := r.Level2CardPool[randomIndex]
@@ -515,7 +1650,7 @@ func (r *RoomInfo) DrawRandomCardsFromLevel3Pool(count int) ([]models.Card, erro
 		}
 
 		// 随机选择一个索引
-		randomIndex := rand.Intn(len(r.Level3CardPool))
+		randomIndex := r.randomSource().Intn(len(r.Level3CardPool))
 
 		// 获取选中的卡牌
 		selectedCard := r.Level3CardPool[randomIndex]
@@ -0,0 +1,30 @@
+package tcpserver
+
+import (
+	"GoServer/tcpgameserver/protocol"
+	"net/http"
+
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketPath 游戏WebSocket网关路径（供Unity WebGL客户端使用）
+const WebSocketPath = "/game/ws"
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// 来源校验已由HTTP服务器的CORS中间件统一处理
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// HandleWebSocket 将HTTP请求升级为WebSocket连接，并接入与TCP相同的消息处理流程
+// 每条WebSocket文本消息对应一个 models.TcpRequest / TcpResponse JSON
+func HandleWebSocket(r *ghttp.Request) {
+	wsConn, err := wsUpgrader.Upgrade(r.Response.Writer, r.Request, nil)
+	if err != nil {
+		return
+	}
+
+	serveConnection(protocol.NewWSConn(wsConn), "websocket")
+}